@@ -12,6 +12,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"ach-concourse/internal/common/auth"
 	"ach-concourse/internal/console"
 )
 
@@ -19,9 +20,26 @@ func main() {
 	// Get port from environment
 	port := getEnv("PORT", "8080")
 
+	// Bearer token verification is configured by AUTH_ISSUER unless AUTH_DISABLED=true; routes
+	// are then guarded by the role policy in AUTH_POLICY_FILE, or the default one
+	verifier, err := auth.NewVerifierFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure authentication: %v", err)
+	}
+	var policy *auth.Policy
+	if verifier != nil {
+		policy, err = auth.PolicyFromEnv(console.DefaultPolicy)
+		if err == nil {
+			err = policy.Validate(console.Permissions)
+		}
+		if err != nil {
+			log.Fatalf("Failed to load authorization policy: %v", err)
+		}
+	}
+
 	// Initialize service (no database needed - console is stateless)
 	service := console.NewService()
-	handler := console.NewHandler(service)
+	handler := console.NewHandler(service, policy)
 
 	// Setup router
 	r := chi.NewRouter()
@@ -30,6 +48,11 @@ func main() {
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Timeout(60 * time.Second))
+	if verifier != nil {
+		r.Use(auth.Middleware(verifier, "/healthz"))
+	} else {
+		log.Println("WARNING: AUTH_DISABLED=true; console requests are not authenticated")
+	}
 
 	// Register routes
 	handler.RegisterRoutes(r)
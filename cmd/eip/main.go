@@ -12,7 +12,10 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"ach-concourse/internal/common/blob"
+	"ach-concourse/internal/common/calendar"
 	"ach-concourse/internal/common/db"
+	"ach-concourse/internal/common/idempotency"
 	"ach-concourse/internal/eip"
 )
 
@@ -28,14 +31,37 @@ func main() {
 	defer database.Close()
 
 	// Initialize schema
-	if err := db.InitSchema(database, []string{eip.GetSchema()}); err != nil {
+	if err := db.InitSchema(database, []string{eip.GetSchema(), idempotency.GetSchema()}); err != nil {
 		log.Fatalf("Failed to initialize schema: %v", err)
 	}
 
+	// Load the business-day calendar SLA due dates are counted on
+	cal, err := calendar.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load holiday calendar: %v", err)
+	}
+
+	// Open the store attachment bytes are kept in
+	blobs, err := blob.NewStoreFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to open blob store: %v", err)
+	}
+
+	// Reach the RDFI service to return entries on approved disputes
+	rdfiClient := eip.NewRDFIClientFromEnv()
+	if rdfiClient == nil {
+		log.Println("RDFI_BASE_URL not set; the dispute workflow is disabled")
+	}
+
 	// Initialize service layers
 	repo := eip.NewRepository(database)
-	service := eip.NewService(repo)
-	handler := eip.NewHandler(service)
+	service := eip.NewService(repo, cal, blobs, eip.MaxAttachmentBytesFromEnv(), rdfiClient)
+	handler := eip.NewHandler(service, idempotency.NewStoreFromEnv(database))
+
+	// Flag and escalate at-risk and overdue cases
+	sweepCtx, stopSweep := context.WithCancel(context.Background())
+	defer stopSweep()
+	go eip.NewSweeperFromEnv(service).Run(sweepCtx)
 
 	// Setup router
 	r := chi.NewRouter()
@@ -13,6 +13,7 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 
 	"ach-concourse/internal/common/db"
+	"ach-concourse/internal/common/idempotency"
 	"ach-concourse/internal/ledger"
 )
 
@@ -28,14 +29,23 @@ func main() {
 	defer database.Close()
 
 	// Initialize schema
-	if err := db.InitSchema(database, []string{ledger.GetSchema()}); err != nil {
+	if err := db.InitSchema(database, []string{ledger.GetSchema(), idempotency.GetSchema()}); err != nil {
 		log.Fatalf("Failed to initialize schema: %v", err)
 	}
 
+	// Load the rules that turn entry lifecycle events into postings
+	rules, err := ledger.PostingRulesFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load posting rules: %v", err)
+	}
+
 	// Initialize service layers
 	repo := ledger.NewRepository(database)
-	service := ledger.NewService(repo)
-	handler := ledger.NewHandler(service)
+	service := ledger.NewService(repo, rules)
+	if err := service.CheckPostingRules(context.Background()); err != nil {
+		log.Fatalf("Invalid posting rules: %v", err)
+	}
+	handler := ledger.NewHandler(service, idempotency.NewStoreFromEnv(database))
 
 	// Setup router
 	r := chi.NewRouter()
@@ -12,7 +12,11 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"ach-concourse/internal/common/calendar"
+	commoncrypto "ach-concourse/internal/common/crypto"
 	"ach-concourse/internal/common/db"
+	"ach-concourse/internal/common/events"
+	"ach-concourse/internal/common/idempotency"
 	"ach-concourse/internal/odfi"
 )
 
@@ -28,14 +32,35 @@ func main() {
 	defer database.Close()
 
 	// Initialize schema
-	if err := db.InitSchema(database, []string{odfi.GetSchema()}); err != nil {
+	if err := db.InitSchema(database, []string{odfi.GetSchema(), idempotency.GetSchema()}); err != nil {
 		log.Fatalf("Failed to initialize schema: %v", err)
 	}
 
+	// Initialize account number encryption
+	cipher, err := commoncrypto.NewFieldCipherFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to initialize encryption: %v", err)
+	}
+
+	// Load the business-day calendar
+	cal, err := calendar.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load holiday calendar: %v", err)
+	}
+
 	// Initialize service layers
 	repo := odfi.NewRepository(database)
-	service := odfi.NewService(repo)
-	handler := odfi.NewHandler(service)
+	service := odfi.NewService(repo, cipher, cal)
+	handler := odfi.NewHandler(service, idempotency.NewStoreFromEnv(database))
+
+	// Deliver entry status changes to the ledger
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	defer stopDispatch()
+	if dispatcher := events.NewDispatcherFromEnv(repo.Outbox()); dispatcher != nil {
+		go dispatcher.Run(dispatchCtx)
+	} else {
+		log.Println("LEDGER_BASE_URL not set; entry events will queue until it is")
+	}
 
 	// Setup router
 	r := chi.NewRouter()
@@ -71,6 +96,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopDispatch()
 
 	// Graceful shutdown with 30 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -12,7 +12,10 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	commoncrypto "ach-concourse/internal/common/crypto"
 	"ach-concourse/internal/common/db"
+	"ach-concourse/internal/common/events"
+	"ach-concourse/internal/common/idempotency"
 	"ach-concourse/internal/rdfi"
 )
 
@@ -28,14 +31,29 @@ func main() {
 	defer database.Close()
 
 	// Initialize schema
-	if err := db.InitSchema(database, []string{rdfi.GetSchema()}); err != nil {
+	if err := db.InitSchema(database, []string{rdfi.GetSchema(), idempotency.GetSchema()}); err != nil {
 		log.Fatalf("Failed to initialize schema: %v", err)
 	}
 
+	// Initialize account number encryption
+	cipher, err := commoncrypto.NewFieldCipherFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to initialize encryption: %v", err)
+	}
+
 	// Initialize service layers
 	repo := rdfi.NewRepository(database)
-	service := rdfi.NewService(repo)
-	handler := rdfi.NewHandler(service)
+	service := rdfi.NewService(repo, cipher)
+	handler := rdfi.NewHandler(service, idempotency.NewStoreFromEnv(database))
+
+	// Deliver entry status changes to the ledger
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	defer stopDispatch()
+	if dispatcher := events.NewDispatcherFromEnv(repo.Outbox()); dispatcher != nil {
+		go dispatcher.Run(dispatchCtx)
+	} else {
+		log.Println("LEDGER_BASE_URL not set; entry events will queue until it is")
+	}
 
 	// Setup router
 	r := chi.NewRouter()
@@ -71,6 +89,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopDispatch()
 
 	// Graceful shutdown with 30 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -13,11 +13,10 @@ import (
 
 // Configurable seed counts - over 1000 of each type for demo
 const (
-	ODFICount   = 1200 // ODFI entries (1000+)
-	RDFICount   = 1200 // RDFI entries (1000+)
-	LedgerCount = 800  // Ledger postings
-	EIPCount    = 500  // EIP cases
-	Workers     = 5    // Concurrent workers (reduced to prevent Docker resource exhaustion)
+	ODFICount = 1200 // ODFI entries (1000+)
+	RDFICount = 1200 // RDFI entries (1000+)
+	EIPCount  = 500  // EIP cases
+	Workers   = 5    // Concurrent workers (reduced to prevent Docker resource exhaustion)
 )
 
 var (
@@ -26,6 +25,9 @@ var (
 	ledgerURL = "http://localhost:8083"
 	eipURL    = "http://localhost:8084"
 
+	// Routing numbers with valid ABA check digits
+	routingNumbers = []string{"011000015", "021000021", "026009593", "121000248", "091000019"}
+
 	httpClient = &http.Client{
 		Timeout: 10 * time.Second,
 		Transport: &http.Transport{
@@ -56,9 +58,10 @@ func main() {
 
 	fmt.Println("🌱 ACH Concourse - High-Volume Database Seeding")
 	fmt.Println("================================================")
-	fmt.Printf("  Target: %d ODFI + %d RDFI + %d Ledger + %d EIP = %d records\n",
-		ODFICount, RDFICount, LedgerCount, EIPCount,
-		ODFICount+RDFICount+LedgerCount+EIPCount)
+	fmt.Printf("  Target: %d ODFI + %d RDFI + %d EIP = %d records\n",
+		ODFICount, RDFICount, EIPCount,
+		ODFICount+RDFICount+EIPCount)
+	fmt.Println("  Ledger postings follow from entries moving to SENT, POSTED and RETURNED")
 	fmt.Println("  Mode: Interleaved ODFI/RDFI for realistic timestamp distribution")
 	fmt.Println()
 
@@ -74,10 +77,10 @@ func main() {
 	start := time.Now()
 
 	// Seed ODFI and RDFI interleaved (for realistic timestamp mixing)
-	// Ledger and EIP can run concurrently alongside
+	// EIP can run concurrently alongside
 	var wg sync.WaitGroup
 
-	wg.Add(3)
+	wg.Add(2)
 
 	// Interleaved ODFI/RDFI seeding - timestamps will be mixed
 	go func() {
@@ -85,12 +88,6 @@ func main() {
 		seedODFIAndRDFIInterleaved(ODFICount, RDFICount)
 	}()
 
-	// Ledger seeding (concurrent with above)
-	go func() {
-		defer wg.Done()
-		seedLedgerConcurrent(LedgerCount)
-	}()
-
 	// EIP seeding (concurrent with above)
 	go func() {
 		defer wg.Done()
@@ -107,13 +104,13 @@ func main() {
 	fmt.Println("📊 Summary:")
 	fmt.Printf("  ODFI entries:      %d records\n", ODFICount)
 	fmt.Printf("  RDFI entries:      %d records\n", RDFICount)
-	fmt.Printf("  Ledger postings:   %d records\n", LedgerCount)
+	fmt.Println("  Ledger postings:   posted from entry lifecycle events")
 	fmt.Printf("  EIP cases:         %d records\n", EIPCount)
 	fmt.Println("  ─────────────────────────────")
-	fmt.Printf("  Total:             %d records\n", ODFICount+RDFICount+LedgerCount+EIPCount)
+	fmt.Printf("  Total:             %d records\n", ODFICount+RDFICount+EIPCount)
 	fmt.Printf("  Time elapsed:      %s\n", elapsed.Round(time.Millisecond))
 	fmt.Printf("  Throughput:        %.0f records/sec\n",
-		float64(ODFICount+RDFICount+LedgerCount+EIPCount)/elapsed.Seconds())
+		float64(ODFICount+RDFICount+EIPCount)/elapsed.Seconds())
 	fmt.Println()
 	fmt.Println("🔍 Verify unified query (fan-out/fan-in):")
 	fmt.Println("  curl http://localhost:8080/api/v1/ach-items | jq '.total_count, .service_info'")
@@ -171,7 +168,7 @@ func seedODFIAndRDFIInterleaved(odfiCount, rdfiCount int) {
 			defer wg.Done()
 			for j := range jobs {
 				if j.side == "ODFI" {
-					createODFIEntry(j.index, companyNames, secCodes, odfiStatuses)
+					createODFIEntry(j.index, companyNames, secCodes, receiverNames, odfiStatuses)
 					atomic.AddInt64(&odfiCreated, 1)
 				} else {
 					createRDFIEntry(j.index, receiverNames, returnCodes)
@@ -205,14 +202,29 @@ func seedODFIAndRDFIInterleaved(odfiCount, rdfiCount int) {
 	fmt.Printf("✅ RDFI entries created: %d\n", atomic.LoadInt64(&rdfiCreated))
 }
 
-func createODFIEntry(i int, companyNames, secCodes, odfiStatuses []string) {
+func createODFIEntry(i int, companyNames, secCodes, receiverNames, odfiStatuses []string) {
 	traceNum := fmt.Sprintf("%015d", 1000000000000+i)
 
+	secCode := secCodes[i%len(secCodes)]
+
+	// WEB and TEL are consumer debits; PPD and CCD alternate credits and debits
+	transactionCode := "27"
+	if secCode == "PPD" || secCode == "CCD" {
+		transactionCode = []string{"22", "27", "32", "37"}[(i/len(secCodes))%4]
+	}
+
 	entry := map[string]interface{}{
-		"trace_number": traceNum,
-		"company_name": companyNames[i%len(companyNames)],
-		"sec_code":     secCodes[i%len(secCodes)],
-		"amount_cents": rand.Int63n(100000) + 1000,
+		"trace_number":     traceNum,
+		"company_name":     companyNames[i%len(companyNames)],
+		"sec_code":         secCode,
+		"amount_cents":     rand.Int63n(100000) + 1000,
+		"routing_number":   routingNumbers[i%len(routingNumbers)],
+		"account_number":   fmt.Sprintf("%010d", 4000000000+i),
+		"transaction_code": transactionCode,
+		"receiver_name":    receiverNames[i%len(receiverNames)],
+	}
+	if secCode == "WEB" || secCode == "TEL" {
+		entry["authorization_type"] = []string{"SINGLE", "RECURRING"}[i%2]
 	}
 
 	body, _ := json.Marshal(entry)
@@ -239,9 +251,12 @@ func createRDFIEntry(i int, receiverNames, returnCodes []string) {
 	traceNum := fmt.Sprintf("%015d", 2000000000000+i)
 
 	entry := map[string]interface{}{
-		"trace_number":  traceNum,
-		"receiver_name": receiverNames[i%len(receiverNames)],
-		"amount_cents":  rand.Int63n(80000) + 500,
+		"trace_number":     traceNum,
+		"receiver_name":    receiverNames[i%len(receiverNames)],
+		"amount_cents":     rand.Int63n(80000) + 500,
+		"routing_number":   routingNumbers[(i+2)%len(routingNumbers)],
+		"account_number":   fmt.Sprintf("%010d", 7000000000+i),
+		"transaction_code": []string{"22", "27", "32", "37"}[i%4],
 	}
 
 	body, _ := json.Marshal(entry)
@@ -251,74 +266,27 @@ func createRDFIEntry(i int, receiverNames, returnCodes []string) {
 	}
 	defer resp.Body.Close()
 
-	// Return ~16% of entries (every 6th)
-	if i%6 == 0 && resp.StatusCode == 201 {
-		var createdEntry RDFIEntry
-		json.NewDecoder(resp.Body).Decode(&createdEntry)
-		returnReq := map[string]string{"reason": returnCodes[i%len(returnCodes)]}
-		body, _ := json.Marshal(returnReq)
-		httpClient.Post(rdfiURL+"/api/v1/entries/"+createdEntry.ID+"/return", "application/json", bytes.NewReader(body))
+	if resp.StatusCode != 201 {
+		return
 	}
-}
-
-// seedLedgerConcurrent seeds ledger postings using concurrent workers
-func seedLedgerConcurrent(count int) {
-	fmt.Println("📝 Seeding Ledger postings...")
-
-	descriptions := []string{"Payment processing", "Vendor payment", "Payroll deposit", "Invoice payment",
-		"Refund processing", "Settlement transfer", "Account funding", "Bill payment",
-		"Wire transfer", "ACH settlement", "Batch reconciliation", "Fee collection"}
-
-	var created int64
-	jobs := make(chan int, count)
-	var wg sync.WaitGroup
-
-	// Start workers
-	for w := 0; w < Workers; w++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for i := range jobs {
-				var achSide, traceNum string
-				if i%2 == 0 {
-					achSide = "ODFI"
-					traceNum = fmt.Sprintf("%015d", 1000000000000+(i/2))
-				} else {
-					achSide = "RDFI"
-					traceNum = fmt.Sprintf("%015d", 2000000000000+((i+1)/2))
-				}
-
-				direction := "DEBIT"
-				if i%2 == 1 {
-					direction = "CREDIT"
-				}
+	var createdEntry RDFIEntry
+	json.NewDecoder(resp.Body).Decode(&createdEntry)
 
-				posting := map[string]interface{}{
-					"ach_side":     achSide,
-					"trace_number": traceNum,
-					"amount_cents": rand.Int63n(90000) + 1000,
-					"direction":    direction,
-					"description":  descriptions[i%len(descriptions)],
-				}
-
-				body, _ := json.Marshal(posting)
-				resp, err := httpClient.Post(ledgerURL+"/api/v1/postings", "application/json", bytes.NewReader(body))
-				if err == nil {
-					resp.Body.Close()
-					atomic.AddInt64(&created, 1)
-				}
-			}
-		}()
+	// Post half of the entries; the ledger books each one as it moves to POSTED
+	if i%4 < 2 {
+		if resp, err := httpClient.Post(rdfiURL+"/api/v1/entries/"+createdEntry.ID+"/post", "application/json", nil); err == nil {
+			resp.Body.Close()
+		}
 	}
 
-	// Send jobs
-	for i := 1; i <= count; i++ {
-		jobs <- i
+	// Return ~16% of entries (every 6th); the ledger reverses returns of posted entries
+	if i%6 == 0 {
+		returnReq := map[string]string{"reason": returnCodes[i%len(returnCodes)]}
+		body, _ := json.Marshal(returnReq)
+		if resp, err := httpClient.Post(rdfiURL+"/api/v1/entries/"+createdEntry.ID+"/return", "application/json", bytes.NewReader(body)); err == nil {
+			resp.Body.Close()
+		}
 	}
-	close(jobs)
-
-	wg.Wait()
-	fmt.Printf("✅ Ledger postings created: %d\n", atomic.LoadInt64(&created))
 }
 
 // seedEIPConcurrent seeds EIP cases using concurrent workers
@@ -379,13 +347,20 @@ func seedEIPConcurrent(count int) {
 						}
 						json.NewDecoder(resp.Body).Decode(&createdCase)
 						updateReq := map[string]string{"status": status}
+						if status == "RESOLVED" {
+							updateReq["resolution_code"] = "NO_ACTION_REQUIRED"
+							updateReq["resolution_summary"] = "Reviewed during seeding; no action required"
+						}
 						body, _ := json.Marshal(updateReq)
 						req, _ := http.NewRequest("PATCH", eipURL+"/api/v1/cases/"+createdCase.ID+"/status", bytes.NewReader(body))
 						req.Header.Set("Content-Type", "application/json")
 						httpClient.Do(req)
 					}
+					// A rerun finds the same open cases and is answered 409
+					if resp.StatusCode == 201 {
+						atomic.AddInt64(&created, 1)
+					}
 					resp.Body.Close()
-					atomic.AddInt64(&created, 1)
 				}
 			}
 		}()
@@ -30,7 +30,13 @@ ODFI_RESPONSE=$(curl -s -X POST "$GATEWAY/api/v1/odfi/entries" \
         "trace_number": "GATEWAY111111111",
         "company_name": "Gateway Demo Corp",
         "sec_code": "WEB",
-        "amount_cents": 75000
+        "amount_cents": 75000,
+        "routing_number": "021000021",
+        "account_number": "123456789",
+        "transaction_code": "27",
+        "receiver_name": "Jane Doe",
+        "authorization_type": "SINGLE",
+        "addenda": ["INV 2024-0042"]
     }')
 
 ODFI_ID=$(echo "$ODFI_RESPONSE" | grep -o '"id":"[^"]*"' | head -1 | cut -d'"' -f4)
@@ -61,7 +67,10 @@ RDFI_RESPONSE=$(curl -s -X POST "$GATEWAY/api/v1/rdfi/entries" \
     -d '{
         "trace_number": "GATEWAY999999999",
         "receiver_name": "Gateway Demo User",
-        "amount_cents": 35000
+        "amount_cents": 35000,
+        "routing_number": "011000015",
+        "account_number": "987654321",
+        "transaction_code": "22"
     }')
 
 RDFI_ID=$(echo "$RDFI_RESPONSE" | grep -o '"id":"[^"]*"' | head -1 | cut -d'"' -f4)
@@ -73,7 +82,9 @@ RDFI_COUNT=$(curl -s "$GATEWAY/api/v1/rdfi/entries" | grep -o '"id"' | wc -l | t
 echo "   ✅ Found $RDFI_COUNT RDFI entries"
 echo ""
 
-echo "📝 7. Returning RDFI Entry (via Gateway)..."
+echo "📝 7. Posting and Returning RDFI Entry (via Gateway)..."
+curl -s -X POST "$GATEWAY/api/v1/rdfi/entries/$RDFI_ID/post" > /dev/null
+echo "   ✅ Posted entry to the receiver's account"
 curl -s -X POST "$GATEWAY/api/v1/rdfi/entries/$RDFI_ID/return" \
     -H "Content-Type: application/json" \
     -d '{"reason": "R01"}' > /dev/null
@@ -81,17 +92,10 @@ echo "   ✅ Returned entry with R01"
 echo ""
 
 # ========== Ledger Operations ==========
-echo "📝 8. Creating Ledger Posting (via Gateway)..."
-curl -s -X POST "$GATEWAY/api/v1/ledger/postings" \
-    -H "Content-Type: application/json" \
-    -d '{
-        "ach_side": "ODFI",
-        "trace_number": "GATEWAY111111111",
-        "amount_cents": 75000,
-        "direction": "DEBIT",
-        "description": "Gateway demo payment"
-    }' > /dev/null
-echo "   ✅ Created ledger posting"
+echo "📝 8. Checking Automatic Ledger Postings (via Gateway)..."
+sleep 3 # give the ODFI and RDFI dispatchers a moment to deliver
+EVENT_COUNT=$(curl -s "$GATEWAY/api/v1/ledger/events?entry_id=$ODFI_ID" | grep -o '"event_id"' | wc -l | tr -d ' ')
+echo "   ✅ Ledger processed $EVENT_COUNT status change(s) for the sent ODFI entry"
 echo ""
 
 echo "📝 9. Listing Ledger Postings (via Gateway)..."
@@ -0,0 +1,143 @@
+package ach
+
+import (
+	"errors"
+	"strings"
+
+	"ach-concourse/internal/common/validation"
+)
+
+// Transaction code constants (NACHA entry detail record, field 2)
+const (
+	TransactionCodeCheckingCredit = "22"
+	TransactionCodeCheckingDebit  = "27"
+	TransactionCodeSavingsCredit  = "32"
+	TransactionCodeSavingsDebit   = "37"
+)
+
+// Account type constants
+const (
+	AccountTypeChecking = "CHECKING"
+	AccountTypeSavings  = "SAVINGS"
+)
+
+// Direction constants
+const (
+	DirectionDebit  = "DEBIT"
+	DirectionCredit = "CREDIT"
+)
+
+// TransactionCode describes the account type and direction implied by a transaction code
+type TransactionCode struct {
+	Code        string `json:"code"`
+	AccountType string `json:"account_type"`
+	Direction   string `json:"direction"`
+}
+
+var transactionCodes = map[string]TransactionCode{
+	TransactionCodeCheckingCredit: {Code: TransactionCodeCheckingCredit, AccountType: AccountTypeChecking, Direction: DirectionCredit},
+	TransactionCodeCheckingDebit:  {Code: TransactionCodeCheckingDebit, AccountType: AccountTypeChecking, Direction: DirectionDebit},
+	TransactionCodeSavingsCredit:  {Code: TransactionCodeSavingsCredit, AccountType: AccountTypeSavings, Direction: DirectionCredit},
+	TransactionCodeSavingsDebit:   {Code: TransactionCodeSavingsDebit, AccountType: AccountTypeSavings, Direction: DirectionDebit},
+}
+
+// LookupTransactionCode returns the details for a supported transaction code
+func LookupTransactionCode(code string) (TransactionCode, bool) {
+	tc, ok := transactionCodes[code]
+	return tc, ok
+}
+
+// DirectionForTransactionCode returns DEBIT or CREDIT for a transaction code, or "" if unknown
+func DirectionForTransactionCode(code string) string {
+	return transactionCodes[code].Direction
+}
+
+// ValidateRoutingNumber checks that a routing number is 9 digits with a valid ABA check digit.
+// The check digit satisfies 3(d1+d4+d7) + 7(d2+d5+d8) + (d3+d6+d9) ≡ 0 (mod 10).
+func ValidateRoutingNumber(routingNumber string) error {
+	if len(routingNumber) != 9 {
+		return errors.New("routing_number must be 9 digits")
+	}
+
+	weights := [3]int{3, 7, 1}
+	sum := 0
+	for i, c := range routingNumber {
+		if c < '0' || c > '9' {
+			return errors.New("routing_number must be 9 digits")
+		}
+		sum += int(c-'0') * weights[i%3]
+	}
+
+	if sum%10 != 0 {
+		return errors.New("routing_number has an invalid check digit")
+	}
+
+	return nil
+}
+
+// ValidateAccountNumber checks that an account number fits the 17 character DFI account field
+func ValidateAccountNumber(accountNumber string) error {
+	if accountNumber == "" {
+		return errors.New("account_number is required")
+	}
+	if len(accountNumber) > 17 {
+		return errors.New("account_number must be at most 17 characters")
+	}
+	for _, c := range accountNumber {
+		isDigit := c >= '0' && c <= '9'
+		isLetter := (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+		if !isDigit && !isLetter && c != '-' {
+			return errors.New("account_number may only contain letters, digits and hyphens")
+		}
+	}
+	return nil
+}
+
+// ValidateAccountDetails validates the receiver account fields shared by ODFI and RDFI entries.
+// An empty accountType is allowed and should be filled from the transaction code by the caller.
+func ValidateAccountDetails(routingNumber, accountNumber, accountType, transactionCode string) validation.Errors {
+	var errs validation.Errors
+
+	if routingNumber == "" {
+		errs.Add("routing_number", "is required")
+	} else if err := ValidateRoutingNumber(routingNumber); err != nil {
+		errs.Add("routing_number", strings.TrimPrefix(err.Error(), "routing_number "))
+	}
+
+	if err := ValidateAccountNumber(accountNumber); err != nil {
+		errs.Add("account_number", strings.TrimPrefix(err.Error(), "account_number "))
+	}
+
+	if transactionCode == "" {
+		errs.Add("transaction_code", "is required")
+		return errs
+	}
+
+	tc, ok := LookupTransactionCode(transactionCode)
+	if !ok {
+		errs.Add("transaction_code", "must be one of 22, 27, 32, 37")
+		return errs
+	}
+
+	if accountType != "" && !strings.EqualFold(accountType, tc.AccountType) {
+		errs.Add("account_type", "does not match transaction_code")
+	}
+
+	return errs
+}
+
+// Last4 returns the last four characters of an account number
+func Last4(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return accountNumber
+	}
+	return accountNumber[len(accountNumber)-4:]
+}
+
+// MaskAccountNumber renders the last four characters of an account number behind asterisks
+func MaskAccountNumber(last4 string) string {
+	if last4 == "" {
+		return ""
+	}
+	return "****" + last4
+}
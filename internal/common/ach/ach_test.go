@@ -0,0 +1,63 @@
+package ach
+
+import "testing"
+
+func TestValidateRoutingNumber(t *testing.T) {
+	for _, tc := range []struct {
+		routingNumber string
+		valid         bool
+	}{
+		{"021000021", true},
+		{"011000015", true},
+		{"122105155", true},
+		{"021000022", false},
+		{"02100002", false},
+		{"0210000210", false},
+		{"02100002a", false},
+		{"", false},
+	} {
+		err := ValidateRoutingNumber(tc.routingNumber)
+		if (err == nil) != tc.valid {
+			t.Errorf("ValidateRoutingNumber(%q) = %v, want valid=%v", tc.routingNumber, err, tc.valid)
+		}
+	}
+}
+
+func TestValidateAccountDetails(t *testing.T) {
+	if errs := ValidateAccountDetails("021000021", "123456789", "checking", TransactionCodeCheckingDebit); len(errs) != 0 {
+		t.Errorf("valid details: got %v", errs)
+	}
+
+	errs := ValidateAccountDetails("021000022", "", AccountTypeSavings, TransactionCodeCheckingCredit)
+	fields := map[string]bool{}
+	for _, fe := range errs {
+		fields[fe.Field] = true
+	}
+	for _, field := range []string{"routing_number", "account_number", "account_type"} {
+		if !fields[field] {
+			t.Errorf("errors %v do not name %s", errs, field)
+		}
+	}
+}
+
+func TestSecRules(t *testing.T) {
+	for _, code := range SupportedSecCodes {
+		if _, ok := LookupSecRule(code); !ok {
+			t.Errorf("SupportedSecCodes lists %s but it has no rule", code)
+		}
+	}
+
+	for _, code := range []string{SecWEB, SecTEL} {
+		rule, _ := LookupSecRule(code)
+		if rule.AllowsTransactionCode(TransactionCodeCheckingCredit) || rule.AllowsTransactionCode(TransactionCodeSavingsCredit) {
+			t.Errorf("%s allows credits, want debits only", code)
+		}
+		if !rule.AllowsTransactionCode(TransactionCodeCheckingDebit) {
+			t.Errorf("%s does not allow checking debits", code)
+		}
+	}
+
+	if rule, _ := LookupSecRule(SecIAT); rule.MaxAddenda != 0 {
+		t.Errorf("IAT allows %d payment related addenda, want none", rule.MaxAddenda)
+	}
+}
@@ -0,0 +1,115 @@
+package ach
+
+// SEC (Standard Entry Class) code constants
+const (
+	SecPPD = "PPD" // Prearranged payment and deposit (consumer)
+	SecCCD = "CCD" // Corporate credit or debit
+	SecWEB = "WEB" // Internet-initiated consumer debit
+	SecTEL = "TEL" // Telephone-initiated consumer debit
+	SecCTX = "CTX" // Corporate trade exchange
+	SecIAT = "IAT" // International ACH transaction
+)
+
+// Authorization type constants for WEB and TEL entries (payment type code)
+const (
+	AuthorizationSingle    = "SINGLE"
+	AuthorizationRecurring = "RECURRING"
+)
+
+// AddendaTypePaymentRelated is the addenda type code for payment related information
+const AddendaTypePaymentRelated = "05"
+
+// AddendaInfoMaxLength is the width of the payment related information field
+const AddendaInfoMaxLength = 80
+
+// MaxEntryAmountCents is the largest amount the 10 digit entry amount field can carry
+const MaxEntryAmountCents int64 = 9999999999
+
+// SecRule describes what an entry of a given SEC code must satisfy
+type SecRule struct {
+	Code                     string
+	AllowedTransactionCodes  []string
+	RequireReceiverName      bool
+	ReceiverNameMaxLength    int
+	RequireReceiverID        bool
+	RequireAuthorizationType bool
+	// MaxAddenda counts type 05 payment related addenda; zero means none are accepted
+	MaxAddenda int
+}
+
+var allTransactionCodes = []string{
+	TransactionCodeCheckingCredit, TransactionCodeCheckingDebit,
+	TransactionCodeSavingsCredit, TransactionCodeSavingsDebit,
+}
+
+var debitTransactionCodes = []string{TransactionCodeCheckingDebit, TransactionCodeSavingsDebit}
+
+var secRules = map[string]SecRule{
+	SecPPD: {
+		Code:                    SecPPD,
+		AllowedTransactionCodes: allTransactionCodes,
+		RequireReceiverName:     true,
+		ReceiverNameMaxLength:   22,
+		MaxAddenda:              1,
+	},
+	SecCCD: {
+		Code:                    SecCCD,
+		AllowedTransactionCodes: allTransactionCodes,
+		RequireReceiverName:     true,
+		ReceiverNameMaxLength:   22,
+		MaxAddenda:              1,
+	},
+	SecWEB: {
+		Code:                     SecWEB,
+		AllowedTransactionCodes:  debitTransactionCodes,
+		RequireReceiverName:      true,
+		ReceiverNameMaxLength:    22,
+		RequireAuthorizationType: true,
+		MaxAddenda:               1,
+	},
+	SecTEL: {
+		Code:                     SecTEL,
+		AllowedTransactionCodes:  debitTransactionCodes,
+		RequireReceiverName:      true,
+		ReceiverNameMaxLength:    22,
+		RequireAuthorizationType: true,
+		MaxAddenda:               0,
+	},
+	SecCTX: {
+		Code:                    SecCTX,
+		AllowedTransactionCodes: allTransactionCodes,
+		RequireReceiverName:     true,
+		ReceiverNameMaxLength:   16,
+		RequireReceiverID:       true,
+		MaxAddenda:              9999,
+	},
+	SecIAT: {
+		Code:                    SecIAT,
+		AllowedTransactionCodes: allTransactionCodes,
+		RequireReceiverName:     true,
+		ReceiverNameMaxLength:   35,
+		RequireReceiverID:       true,
+		// IAT carries its mandatory 710-716 addenda instead of type 05 records, and this
+		// service does not build those, so no payment related addenda are accepted
+		MaxAddenda: 0,
+	},
+}
+
+// LookupSecRule returns the rule for a supported SEC code
+func LookupSecRule(code string) (SecRule, bool) {
+	rule, ok := secRules[code]
+	return rule, ok
+}
+
+// AllowsTransactionCode reports whether the rule permits a transaction code
+func (r SecRule) AllowsTransactionCode(code string) bool {
+	for _, allowed := range r.AllowedTransactionCodes {
+		if allowed == code {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportedSecCodes lists the SEC codes with rules, in display order
+var SupportedSecCodes = []string{SecPPD, SecCCD, SecWEB, SecTEL, SecCTX, SecIAT}
@@ -0,0 +1,146 @@
+// Package auth authenticates requests carrying an OIDC-issued JWT bearer token. Tokens are
+// verified against the issuer's JWKS, fetched from a URL or loaded from a file, and must name
+// the configured issuer and audience.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultClockSkew is how far token times may disagree with the local clock unless
+// AUTH_CLOCK_SKEW is set
+const DefaultClockSkew = time.Minute
+
+// Config locates the token issuer and its signing keys
+type Config struct {
+	Issuer   string
+	Audience string
+	// JWKSURL is fetched and refreshed periodically; JWKSFile is loaded once. Exactly one is set.
+	JWKSURL   string
+	JWKSFile  string
+	ClockSkew time.Duration
+}
+
+// Token verification errors. Their messages are safe to return to the client.
+var (
+	ErrMissingToken     = errors.New("bearer token required")
+	ErrMalformedToken   = errors.New("token is malformed")
+	ErrUnsupportedAlg   = errors.New("token signing algorithm is not supported")
+	ErrUnknownKey       = errors.New("token signing key is unknown")
+	ErrInvalidSignature = errors.New("token signature is invalid")
+	ErrTokenExpired     = errors.New("token is expired")
+	ErrTokenNotYetValid = errors.New("token is not yet valid")
+	ErrInvalidIssuer    = errors.New("token issuer is not accepted")
+	ErrInvalidAudience  = errors.New("token audience is not accepted")
+)
+
+// Claims is the verified content of a token
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Audience  []string
+	ExpiresAt time.Time
+	// Raw holds every claim in the token, registered or not
+	Raw map[string]any
+}
+
+// Verifier checks tokens against one issuer
+type Verifier struct {
+	config Config
+	keys   *keySet
+	now    func() time.Time
+}
+
+// NewVerifier creates a verifier for config. A JWKS file is read immediately; a JWKS URL is
+// fetched on first use, so the issuer being down does not stop startup.
+func NewVerifier(config Config) (*Verifier, error) {
+	if config.Issuer == "" || config.Audience == "" {
+		return nil, errors.New("AUTH_ISSUER and AUTH_AUDIENCE are required for authentication")
+	}
+	if (config.JWKSURL == "") == (config.JWKSFile == "") {
+		return nil, errors.New("exactly one of AUTH_JWKS_URL and AUTH_JWKS_FILE is required for authentication")
+	}
+	if config.ClockSkew < 0 {
+		return nil, errors.New("AUTH_CLOCK_SKEW must not be negative")
+	}
+
+	var keys *keySet
+	if config.JWKSFile != "" {
+		data, err := os.ReadFile(config.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read AUTH_JWKS_FILE: %w", err)
+		}
+		keys, err = staticKeySet(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTH_JWKS_FILE: %w", err)
+		}
+	} else {
+		keys = remoteKeySet(config.JWKSURL)
+	}
+
+	return &Verifier{config: config, keys: keys, now: time.Now}, nil
+}
+
+// NewVerifierFromEnv creates a verifier using AUTH_ISSUER, AUTH_AUDIENCE, AUTH_JWKS_URL or
+// AUTH_JWKS_FILE, and AUTH_CLOCK_SKEW (a Go duration such as "30s"). Authentication can only
+// be turned off explicitly: it returns nil when AUTH_DISABLED is "true", which leaves the
+// service unauthenticated, and an error when AUTH_ISSUER is not set otherwise.
+func NewVerifierFromEnv() (*Verifier, error) {
+	issuer := os.Getenv("AUTH_ISSUER")
+	if os.Getenv("AUTH_DISABLED") == "true" {
+		if issuer != "" {
+			return nil, errors.New("AUTH_ISSUER and AUTH_DISABLED=true are both set")
+		}
+		if os.Getenv("AUTH_POLICY_FILE") != "" {
+			return nil, errors.New("AUTH_POLICY_FILE is set but authentication is disabled; roles need AUTH_ISSUER")
+		}
+		return nil, nil
+	}
+	if issuer == "" {
+		return nil, errors.New("AUTH_ISSUER is required; set AUTH_DISABLED=true to run without authentication")
+	}
+
+	clockSkew := DefaultClockSkew
+	if value := os.Getenv("AUTH_CLOCK_SKEW"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTH_CLOCK_SKEW %q", value)
+		}
+		clockSkew = parsed
+	}
+
+	return NewVerifier(Config{
+		Issuer:    issuer,
+		Audience:  os.Getenv("AUTH_AUDIENCE"),
+		JWKSURL:   os.Getenv("AUTH_JWKS_URL"),
+		JWKSFile:  os.Getenv("AUTH_JWKS_FILE"),
+		ClockSkew: clockSkew,
+	})
+}
+
+type contextKey struct{}
+
+// WithClaims returns a copy of ctx carrying claims
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims of the authenticated caller, or nil when the request
+// was not authenticated
+func ClaimsFromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(contextKey{}).(*Claims)
+	return claims
+}
+
+// Subject returns the authenticated caller's subject, or "" when the request was not
+// authenticated
+func Subject(ctx context.Context) string {
+	if claims := ClaimsFromContext(ctx); claims != nil {
+		return claims.Subject
+	}
+	return ""
+}
@@ -0,0 +1,275 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jwksMaxAge is how long fetched keys are used before the JWKS is fetched again
+const jwksMaxAge = time.Hour
+
+// jwksMinRefresh bounds how often fetches are attempted, so neither forged kids nor an issuer
+// that is down can make the console hammer it
+const jwksMinRefresh = 30 * time.Second
+
+// maxJWKSBytes bounds the JWKS document read from the issuer
+const maxJWKSBytes = 1 << 20
+
+// jwk is one key of a JSON Web Key Set. Only the public parameters are read.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey is a parsed signing key with the constraints its JWK declared
+type publicKey struct {
+	kid     string
+	alg     string
+	keyType string
+	curve   string
+	key     any
+}
+
+// keySet holds the issuer's signing keys. A set loaded from a file never changes; a set
+// backed by a URL is fetched on first use, again once it is older than jwksMaxAge, and early
+// when a token names a kid it does not hold. Concurrent callers share one fetch, and mu is
+// never held while it runs.
+type keySet struct {
+	url    string
+	client *http.Client
+
+	mu          sync.Mutex
+	keys        []publicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	// fetching is closed when the fetch in progress finishes; nil when none is
+	fetching chan struct{}
+}
+
+func staticKeySet(data []byte) (*keySet, error) {
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return &keySet{keys: keys}, nil
+}
+
+func remoteKeySet(url string) *keySet {
+	return &keySet{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// key finds the key for a token's kid and alg. A token without a kid is accepted only when
+// exactly one key can verify alg.
+func (s *keySet) key(ctx context.Context, kid, alg string) (any, error) {
+	key, stale := s.lookup(kid, alg)
+	if key != nil && !stale {
+		return key, nil
+	}
+	if s.url != "" {
+		s.refresh(ctx)
+		key, _ = s.lookup(kid, alg)
+	}
+	if key == nil {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// lookup finds the key for kid and alg in the keys held now, and reports whether they are
+// due to be fetched again
+func (s *keySet) lookup(kid, alg string) (key any, stale bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.find(kid, alg), s.url != "" && time.Since(s.fetchedAt) > jwksMaxAge
+}
+
+func (s *keySet) find(kid, alg string) any {
+	spec := algorithms[alg]
+
+	var match any
+	matches := 0
+	for _, k := range s.keys {
+		if kid != "" && k.kid != kid {
+			continue
+		}
+		if k.keyType != spec.keyType || (k.alg != "" && k.alg != alg) || k.curve != spec.curve {
+			continue
+		}
+		match = k.key
+		matches++
+	}
+	if matches != 1 {
+		return nil
+	}
+	return match
+}
+
+// refresh starts a fetch of the JWKS, or joins the one in progress, and waits until it ends
+// or ctx is done. No fetch starts within jwksMinRefresh of the last attempt. The fetch is not
+// tied to ctx, so a caller that gives up does not abort it for the others.
+func (s *keySet) refresh(ctx context.Context) {
+	s.mu.Lock()
+	if s.fetching == nil {
+		if time.Since(s.attemptedAt) < jwksMinRefresh {
+			s.mu.Unlock()
+			return
+		}
+		s.attemptedAt = time.Now()
+		s.fetching = make(chan struct{})
+		go s.fetchKeys(s.fetching)
+	}
+	done := s.fetching
+	s.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// fetchKeys replaces the keys with a fresh fetch and closes done. On failure the previous
+// keys stay in use.
+func (s *keySet) fetchKeys(done chan struct{}) {
+	keys, err := s.fetch()
+
+	s.mu.Lock()
+	if err != nil {
+		log.Printf("auth: failed to fetch JWKS from %s: %v", s.url, err)
+	} else {
+		s.keys = keys
+		s.fetchedAt = time.Now()
+	}
+	s.fetching = nil
+	s.mu.Unlock()
+	close(done)
+}
+
+// fetch downloads and parses the JWKS, bounded by the client's timeout
+func (s *keySet) fetch() ([]publicKey, error) {
+	req, err := http.NewRequest(http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSBytes))
+	if err != nil {
+		return nil, err
+	}
+	return parseJWKS(data)
+}
+
+// parseJWKS reads the signing keys of a JWKS document. Keys that are not for signatures or
+// are of an unsupported type are skipped, as the spec allows.
+func parseJWKS(data []byte) ([]publicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	var keys []publicKey
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			keys = append(keys, *key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no usable signing keys")
+	}
+	return keys, nil
+}
+
+// publicKey parses k, returning nil for key types tokens cannot be verified with
+func (k jwk) publicKey() (*publicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, errors.New("invalid modulus")
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+		if n.BitLen() < 2048 {
+			return nil, errors.New("RSA keys must be at least 2048 bits")
+		}
+		return &publicKey{kid: k.Kid, alg: k.Alg, keyType: "RSA",
+			key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, errors.New("invalid x coordinate")
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, errors.New("invalid y coordinate")
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &publicKey{kid: k.Kid, alg: k.Alg, keyType: "EC", curve: k.Crv,
+			key: &ecdsa.PublicKey{Curve: curve, X: x, Y: y}}, nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
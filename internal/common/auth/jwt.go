@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// algorithm describes how a JWS alg value is verified
+type algorithm struct {
+	hash crypto.Hash
+	// keyType is the JWK kty the alg requires: RSA or EC
+	keyType string
+	pss     bool
+	// curve is the EC curve an ES alg requires
+	curve string
+}
+
+// algorithms are the accepted signing algorithms. Symmetric algorithms and "none" are
+// deliberately absent: the console holds no shared secret with the issuer.
+var algorithms = map[string]algorithm{
+	"RS256": {hash: crypto.SHA256, keyType: "RSA"},
+	"RS384": {hash: crypto.SHA384, keyType: "RSA"},
+	"RS512": {hash: crypto.SHA512, keyType: "RSA"},
+	"PS256": {hash: crypto.SHA256, keyType: "RSA", pss: true},
+	"PS384": {hash: crypto.SHA384, keyType: "RSA", pss: true},
+	"PS512": {hash: crypto.SHA512, keyType: "RSA", pss: true},
+	"ES256": {hash: crypto.SHA256, keyType: "EC", curve: "P-256"},
+	"ES384": {hash: crypto.SHA384, keyType: "EC", curve: "P-384"},
+	"ES512": {hash: crypto.SHA512, keyType: "EC", curve: "P-521"},
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify checks the token's signature, issuer, audience and validity period and returns its
+// claims. The token must carry an exp claim.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, ErrMalformedToken
+	}
+	alg, ok := algorithms[h.Alg]
+	if !ok {
+		return nil, ErrUnsupportedAlg
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	key, err := v.keys.key(ctx, h.Kid, h.Alg)
+	if err != nil {
+		return nil, err
+	}
+	if !verifySignature(alg, key, parts[0]+"."+parts[1], signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	raw := map[string]any{}
+	if err := decodeSegment(parts[1], &raw); err != nil {
+		return nil, ErrMalformedToken
+	}
+	claims, err := parseClaims(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Issuer != v.config.Issuer {
+		return nil, ErrInvalidIssuer
+	}
+	if !contains(claims.Audience, v.config.Audience) {
+		return nil, ErrInvalidAudience
+	}
+
+	now := v.now()
+	if claims.ExpiresAt.IsZero() || !now.Before(claims.ExpiresAt.Add(v.config.ClockSkew)) {
+		return nil, ErrTokenExpired
+	}
+	if notBefore, ok := numericDate(raw["nbf"]); ok && now.Add(v.config.ClockSkew).Before(notBefore) {
+		return nil, ErrTokenNotYetValid
+	}
+
+	return claims, nil
+}
+
+// verifySignature checks signature over input with key, which the key set has already
+// matched to alg's key type and curve
+func verifySignature(alg algorithm, key any, input string, signature []byte) bool {
+	hasher := alg.hash.New()
+	hasher.Write([]byte(input))
+	digest := hasher.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if alg.pss {
+			return rsa.VerifyPSS(key, alg.hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+		return rsa.VerifyPKCS1v15(key, alg.hash, digest, signature) == nil
+	case *ecdsa.PublicKey:
+		// JWS encodes an ECDSA signature as r and s, each padded to the curve size
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(key, digest, r, s)
+	}
+	return false
+}
+
+// parseClaims reads the registered claims out of raw
+func parseClaims(raw map[string]any) (*Claims, error) {
+	claims := &Claims{Raw: raw}
+
+	var ok bool
+	if value, present := raw["iss"]; present {
+		if claims.Issuer, ok = value.(string); !ok {
+			return nil, ErrMalformedToken
+		}
+	}
+	if value, present := raw["sub"]; present {
+		if claims.Subject, ok = value.(string); !ok {
+			return nil, ErrMalformedToken
+		}
+	}
+
+	// aud may be a single string or an array of strings
+	switch aud := raw["aud"].(type) {
+	case nil:
+	case string:
+		claims.Audience = []string{aud}
+	case []any:
+		for _, value := range aud {
+			s, ok := value.(string)
+			if !ok {
+				return nil, ErrMalformedToken
+			}
+			claims.Audience = append(claims.Audience, s)
+		}
+	default:
+		return nil, ErrMalformedToken
+	}
+
+	if value, present := raw["exp"]; present {
+		if claims.ExpiresAt, ok = numericDate(value); !ok {
+			return nil, ErrMalformedToken
+		}
+	}
+
+	return claims, nil
+}
+
+// numericDate converts a JWT NumericDate, seconds since the epoch, to a time
+func numericDate(value any) (time.Time, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	whole := math.Floor(seconds)
+	return time.Unix(int64(whole), int64((seconds-whole)*1e9)), true
+}
+
+// decodeSegment decodes a base64url JSON segment of a token, keeping numbers exact
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func contains(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
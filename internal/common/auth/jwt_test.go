@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	testIssuer   = "https://issuer.example.com"
+	testAudience = "ach-console"
+	testKid      = "key-1"
+)
+
+// testNow is the verifier's clock in every test, so expiry does not depend on the wall clock
+var testNow = time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+// newTestVerifier returns a verifier trusting a fresh RSA key, loaded from a JWKS file, and
+// the private key to sign tokens with
+func newTestVerifier(t *testing.T) (*Verifier, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": testKid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	v, err := NewVerifier(Config{Issuer: testIssuer, Audience: testAudience, JWKSFile: path, ClockSkew: DefaultClockSkew})
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+	v.now = func() time.Time { return testNow }
+	return v, key
+}
+
+// validClaims are claims the test verifier accepts
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss": testIssuer,
+		"sub": "jdoe",
+		"aud": testAudience,
+		"exp": testNow.Add(time.Hour).Unix(),
+	}
+}
+
+// signRS256 builds a token with the given header and claims signed by key
+func signRS256(t *testing.T, key *rsa.PrivateKey, hdr map[string]any, claims map[string]any) string {
+	t.Helper()
+
+	input := encodeTestSegment(t, hdr) + "." + encodeTestSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("SignPKCS1v15: %v", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeTestSegment(t *testing.T, v any) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func TestVerifyAcceptsValidToken(t *testing.T) {
+	v, key := newTestVerifier(t)
+
+	token := signRS256(t, key, map[string]any{"alg": "RS256", "kid": testKid}, validClaims())
+	claims, err := v.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.Subject != "jdoe" || claims.Issuer != testIssuer || len(claims.Audience) != 1 || claims.Audience[0] != testAudience {
+		t.Errorf("claims = %+v, want the signed claims", claims)
+	}
+	if !claims.ExpiresAt.Equal(testNow.Add(time.Hour)) {
+		t.Errorf("ExpiresAt = %v, want %v", claims.ExpiresAt, testNow.Add(time.Hour))
+	}
+
+	// A token without a kid is accepted while only one key can verify its alg
+	token = signRS256(t, key, map[string]any{"alg": "RS256"}, validClaims())
+	if _, err := v.Verify(context.Background(), token); err != nil {
+		t.Errorf("Verify without kid: %v", err)
+	}
+}
+
+func TestVerifyRejectsBadTokens(t *testing.T) {
+	v, key := newTestVerifier(t)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	rs256 := map[string]any{"alg": "RS256", "kid": testKid}
+	with := func(name string, value any) map[string]any {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	for _, tc := range []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"not three segments", "abc.def", ErrMalformedToken},
+		{"alg none", encodeTestSegment(t, map[string]any{"alg": "none"}) + "." + encodeTestSegment(t, validClaims()) + ".", ErrUnsupportedAlg},
+		{"alg HS256", signRS256(t, key, map[string]any{"alg": "HS256", "kid": testKid}, validClaims()), ErrUnsupportedAlg},
+		{"alg the key does not declare", signRS256(t, key, map[string]any{"alg": "PS256", "kid": testKid}, validClaims()), ErrUnknownKey},
+		{"unknown kid", signRS256(t, key, map[string]any{"alg": "RS256", "kid": "key-2"}, validClaims()), ErrUnknownKey},
+		{"signed by another key", signRS256(t, other, rs256, validClaims()), ErrInvalidSignature},
+		{"wrong issuer", signRS256(t, key, rs256, with("iss", "https://evil.example.com")), ErrInvalidIssuer},
+		{"no issuer", signRS256(t, key, rs256, with("iss", nil)), ErrInvalidIssuer},
+		{"wrong audience", signRS256(t, key, rs256, with("aud", "other-app")), ErrInvalidAudience},
+		{"audience list without ours", signRS256(t, key, rs256, with("aud", []string{"a", "b"})), ErrInvalidAudience},
+		{"expired beyond skew", signRS256(t, key, rs256, with("exp", testNow.Add(-2*time.Minute).Unix())), ErrTokenExpired},
+		{"no exp", signRS256(t, key, rs256, with("exp", nil)), ErrTokenExpired},
+		{"exp not a number", signRS256(t, key, rs256, with("exp", "tomorrow")), ErrMalformedToken},
+		{"not yet valid", signRS256(t, key, rs256, with("nbf", testNow.Add(5*time.Minute).Unix())), ErrTokenNotYetValid},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := v.Verify(context.Background(), tc.token)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("err = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAllowsClockSkew(t *testing.T) {
+	v, key := newTestVerifier(t)
+	rs256 := map[string]any{"alg": "RS256", "kid": testKid}
+
+	claims := validClaims()
+	claims["exp"] = testNow.Add(-30 * time.Second).Unix()
+	claims["nbf"] = testNow.Add(30 * time.Second).Unix()
+	if _, err := v.Verify(context.Background(), signRS256(t, key, rs256, claims)); err != nil {
+		t.Errorf("Verify within clock skew: %v", err)
+	}
+}
+
+func TestVerifyAcceptsAudienceList(t *testing.T) {
+	v, key := newTestVerifier(t)
+
+	claims := validClaims()
+	claims["aud"] = []string{"other-app", testAudience}
+	if _, err := v.Verify(context.Background(), signRS256(t, key, map[string]any{"alg": "RS256", "kid": testKid}, claims)); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
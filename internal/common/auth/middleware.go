@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	commonhttp "ach-concourse/internal/common/http"
+)
+
+// Middleware rejects requests without a valid bearer token with 401 and puts the verified
+// claims in the request context. Requests for publicPaths, such as /healthz, pass through
+// unauthenticated.
+func Middleware(v *Verifier, publicPaths ...string) func(http.Handler) http.Handler {
+	public := make(map[string]bool, len(publicPaths))
+	for _, path := range publicPaths {
+		public[path] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if public[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := bearerToken(r)
+			if !ok {
+				unauthorized(w, ErrMissingToken)
+				return
+			}
+
+			claims, err := v.Verify(r.Context(), token)
+			if err != nil {
+				unauthorized(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// bearerToken reads the token from an "Authorization: Bearer <token>" header
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// unauthorized writes a 401 with the WWW-Authenticate challenge RFC 6750 describes
+func unauthorized(w http.ResponseWriter, err error) {
+	challenge := `Bearer`
+	if !errors.Is(err, ErrMissingToken) {
+		challenge = fmt.Sprintf(`Bearer error="invalid_token", error_description=%q`, err.Error())
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	commonhttp.Error(w, http.StatusUnauthorized, err.Error())
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	commonhttp "ach-concourse/internal/common/http"
+)
+
+// DefaultRoleClaim is the token claim roles are read from unless the policy names another
+const DefaultRoleClaim = "roles"
+
+// AllPermissions, granted to a role, allows every permission
+const AllPermissions = "*"
+
+// Policy maps roles to the permissions they grant. A caller holds the roles listed in the
+// token claim RoleClaim, which may be a dotted path into nested claims such as
+// "realm_access.roles", and is allowed the union of their permissions.
+type Policy struct {
+	RoleClaim string              `json:"role_claim"`
+	Roles     map[string][]string `json:"roles"`
+}
+
+// LoadPolicy reads a JSON policy file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// PolicyFromEnv loads the policy file named by AUTH_POLICY_FILE, or returns defaultPolicy
+// when it is not set
+func PolicyFromEnv(defaultPolicy *Policy) (*Policy, error) {
+	path := os.Getenv("AUTH_POLICY_FILE")
+	if path == "" {
+		return defaultPolicy, nil
+	}
+	return LoadPolicy(path)
+}
+
+// Validate checks that the policy defines at least one role and grants only permissions in
+// known, so a typo in a policy file fails at startup instead of silently denying access
+func (p *Policy) Validate(known []string) error {
+	if len(p.Roles) == 0 {
+		return fmt.Errorf("policy defines no roles")
+	}
+
+	isKnown := make(map[string]bool, len(known))
+	for _, permission := range known {
+		isKnown[permission] = true
+	}
+	for role, permissions := range p.Roles {
+		for _, permission := range permissions {
+			if permission != AllPermissions && !isKnown[permission] {
+				return fmt.Errorf("role %s grants unknown permission %q", role, permission)
+			}
+		}
+	}
+	return nil
+}
+
+// RolesOf returns the policy roles claims holds, sorted. Roles the policy does not define
+// are ignored.
+func (p *Policy) RolesOf(claims *Claims) []string {
+	roles := []string{}
+	if claims == nil {
+		return roles
+	}
+
+	claimName := p.RoleClaim
+	if claimName == "" {
+		claimName = DefaultRoleClaim
+	}
+
+	var value any = claims.Raw
+	for _, name := range strings.Split(claimName, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return roles
+		}
+		value = object[name]
+	}
+
+	// The claim may be an array of role names or a single space-separated string
+	var names []string
+	switch value := value.(type) {
+	case string:
+		names = strings.Fields(value)
+	case []any:
+		for _, item := range value {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, name := range names {
+		if _, defined := p.Roles[name]; defined && !seen[name] {
+			seen[name] = true
+			roles = append(roles, name)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Allows reports whether any of the roles claims holds grants permission
+func (p *Policy) Allows(claims *Claims, permission string) bool {
+	for _, role := range p.RolesOf(claims) {
+		for _, granted := range p.Roles[role] {
+			if granted == permission || granted == AllPermissions {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ForbiddenResponse is the 403 body naming the permission the caller lacks
+type ForbiddenResponse struct {
+	Error             string `json:"error"`
+	MissingPermission string `json:"missing_permission"`
+}
+
+// Require rejects requests whose caller lacks permission with 403. It must run after
+// Middleware has put the caller's claims in the request context.
+func Require(p *Policy, permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !p.Allows(ClaimsFromContext(r.Context()), permission) {
+				commonhttp.JSON(w, http.StatusForbidden, ForbiddenResponse{
+					Error:             "missing permission " + permission,
+					MissingPermission: permission,
+				})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	_ "time/tzdata" // Containers may not ship a zoneinfo database
+)
+
+// DateLayout is the wire format for calendar dates (effective entry dates, settlement dates)
+const DateLayout = "2006-01-02"
+
+// SameDayEntryLimitCents is the per-entry ceiling for same-day ACH ($1,000,000)
+const SameDayEntryLimitCents int64 = 100000000
+
+//go:embed fed_holidays.txt
+var defaultHolidays []byte
+
+// Location is the time zone the ACH operators run on
+var Location = mustLoadLocation("America/New_York")
+
+// SameDayWindow is a same-day ACH submission window, expressed in Eastern time
+type SameDayWindow struct {
+	Name           string `json:"name"`
+	DeadlineHour   int    `json:"deadline_hour"`
+	DeadlineMinute int    `json:"deadline_minute"`
+	SettlementHour int    `json:"settlement_hour"`
+}
+
+// SameDayWindows are the Federal Reserve same-day ACH windows, in order
+var SameDayWindows = []SameDayWindow{
+	{Name: "SDA1", DeadlineHour: 10, DeadlineMinute: 30, SettlementHour: 13},
+	{Name: "SDA2", DeadlineHour: 14, DeadlineMinute: 45, SettlementHour: 17},
+	{Name: "SDA3", DeadlineHour: 16, DeadlineMinute: 45, SettlementHour: 18},
+}
+
+// Holiday is a single non-settlement day
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// ErrOutsideCalendar is returned for dates beyond the years the holiday list covers, where
+// holidays are unknown and business days cannot be counted
+var ErrOutsideCalendar = errors.New("date is outside the holiday calendar")
+
+// Calendar answers business-day questions for ACH settlement. It covers the whole years its
+// holiday list spans; business days cannot be counted outside them.
+type Calendar struct {
+	holidays map[string]Holiday
+	first    time.Time
+	last     time.Time
+}
+
+// New creates a calendar from holiday data in the fed_holidays.txt format
+func New(r io.Reader) (*Calendar, error) {
+	cal := &Calendar{holidays: make(map[string]Holiday)}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		date, name, _ := strings.Cut(line, " ")
+		if _, err := time.ParseInLocation(DateLayout, date, Location); err != nil {
+			return nil, fmt.Errorf("invalid holiday date on line %d: %w", lineNum, err)
+		}
+		cal.holidays[date] = Holiday{Date: date, Name: strings.TrimSpace(name)}
+
+		day, _ := ParseDate(date)
+		if first := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, Location); cal.first.IsZero() || first.Before(cal.first) {
+			cal.first = first
+		}
+		if last := time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, Location); last.After(cal.last) {
+			cal.last = last
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read holidays: %w", err)
+	}
+	if len(cal.holidays) == 0 {
+		return nil, errors.New("holiday calendar lists no holidays")
+	}
+
+	return cal, nil
+}
+
+// Default returns a calendar using the embedded Federal Reserve holiday list
+func Default() *Calendar {
+	cal, err := New(bytes.NewReader(defaultHolidays))
+	if err != nil {
+		panic(err)
+	}
+	return cal
+}
+
+// NewFromEnv loads holidays from HOLIDAY_CALENDAR_FILE, falling back to the embedded list.
+// It fails when the calendar does not cover today, so a stale list stops the service at
+// startup instead of miscounting business days.
+func NewFromEnv() (*Calendar, error) {
+	cal := Default()
+	if path := os.Getenv("HOLIDAY_CALENDAR_FILE"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open holiday calendar: %w", err)
+		}
+		defer f.Close()
+
+		if cal, err = New(f); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cal.check(Today()); err != nil {
+		return nil, fmt.Errorf("%w; extend fed_holidays.txt or set HOLIDAY_CALENDAR_FILE", err)
+	}
+	return cal, nil
+}
+
+// ParseDate parses a YYYY-MM-DD date as midnight Eastern time
+func ParseDate(s string) (time.Time, error) {
+	return time.ParseInLocation(DateLayout, s, Location)
+}
+
+// FormatDate renders t as YYYY-MM-DD in Eastern time
+func FormatDate(t time.Time) string {
+	return t.In(Location).Format(DateLayout)
+}
+
+// Today returns midnight Eastern time for the current day
+func Today() time.Time {
+	return startOfDay(time.Now())
+}
+
+// Covers reports whether t falls within the years the holiday list spans
+func (c *Calendar) Covers(t time.Time) bool {
+	day := startOfDay(t)
+	return !day.Before(c.first) && !day.After(c.last)
+}
+
+// check returns ErrOutsideCalendar, naming the covered range, when t is not covered
+func (c *Calendar) check(t time.Time) error {
+	if c.Covers(t) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is not between %s and %s", ErrOutsideCalendar, FormatDate(t), FormatDate(c.first), FormatDate(c.last))
+}
+
+// IsHoliday reports whether t falls on a Federal Reserve holiday
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	_, ok := c.holidays[FormatDate(t)]
+	return ok
+}
+
+// IsBusinessDay reports whether t is a weekday that is not a holiday. Holidays are unknown
+// for days the calendar does not cover; the methods that count business days return
+// ErrOutsideCalendar for them.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	switch t.In(Location).Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	return !c.IsHoliday(t)
+}
+
+// NextBusinessDay returns the first business day strictly after t
+func (c *Calendar) NextBusinessDay(t time.Time) (time.Time, error) {
+	return c.AddBusinessDays(t, 1)
+}
+
+// OnOrAfter returns t's day if it is a business day, otherwise the next business day
+func (c *Calendar) OnOrAfter(t time.Time) (time.Time, error) {
+	day := startOfDay(t)
+	if err := c.check(day); err != nil {
+		return time.Time{}, err
+	}
+	if c.IsBusinessDay(day) {
+		return day, nil
+	}
+	return c.NextBusinessDay(day)
+}
+
+// AddBusinessDays moves n business days forward (or backward when n is negative) from t.
+// It returns ErrOutsideCalendar if the count passes a day the calendar does not cover.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	day := startOfDay(t)
+	step := 1
+	if n < 0 {
+		step = -1
+		n = -n
+	}
+	for n > 0 {
+		day = day.AddDate(0, 0, step)
+		if err := c.check(day); err != nil {
+			return time.Time{}, err
+		}
+		if c.IsBusinessDay(day) {
+			n--
+		}
+	}
+	return day, nil
+}
+
+// Holidays returns the holidays between from and to inclusive, in date order
+func (c *Calendar) Holidays(from, to time.Time) []Holiday {
+	var result []Holiday
+	for day := startOfDay(from); !day.After(startOfDay(to)); day = day.AddDate(0, 0, 1) {
+		if h, ok := c.holidays[FormatDate(day)]; ok {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// SameDayWindowFor returns the first same-day window still open at submittedAt, if any
+func (c *Calendar) SameDayWindowFor(submittedAt time.Time) (SameDayWindow, bool) {
+	if !c.IsBusinessDay(submittedAt) {
+		return SameDayWindow{}, false
+	}
+
+	local := submittedAt.In(Location)
+	day := startOfDay(local)
+	for _, w := range SameDayWindows {
+		deadline := day.Add(time.Duration(w.DeadlineHour)*time.Hour + time.Duration(w.DeadlineMinute)*time.Minute)
+		if !local.After(deadline) {
+			return w, true
+		}
+	}
+	return SameDayWindow{}, false
+}
+
+// SettlementDate computes when an entry with the given effective entry date settles.
+// Effective dates in the past or on non-business days roll forward; an entry effective
+// today settles today only if a same-day window is still open and it is within the
+// same-day dollar limit, otherwise it settles on the next business day. It returns
+// ErrOutsideCalendar when the settlement date cannot be known.
+func (c *Calendar) SettlementDate(effective, submittedAt time.Time, amountCents int64) (time.Time, error) {
+	submissionDay := startOfDay(submittedAt)
+	day := startOfDay(effective)
+	if day.Before(submissionDay) {
+		day = submissionDay
+	}
+	day, err := c.OnOrAfter(day)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if day.Equal(submissionDay) {
+		_, open := c.SameDayWindowFor(submittedAt)
+		if !open || amountCents > SameDayEntryLimitCents {
+			return c.NextBusinessDay(day)
+		}
+	}
+
+	return day, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	local := t.In(Location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, Location)
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
@@ -0,0 +1,163 @@
+package calendar
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testHolidays spans 2025 and 2026, so the calendar covers exactly those two years
+const testHolidays = `# test calendar
+2025-01-01 New Year's Day
+2025-01-20 Birthday of Martin Luther King, Jr.
+2025-07-04 Independence Day
+2025-11-27 Thanksgiving Day
+2025-12-25 Christmas Day
+
+2026-01-01 New Year's Day
+2026-12-25 Christmas Day
+`
+
+func newTestCalendar(t *testing.T) *Calendar {
+	t.Helper()
+
+	cal, err := New(strings.NewReader(testHolidays))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return cal
+}
+
+func mustParseDate(t *testing.T, s string) time.Time {
+	t.Helper()
+
+	day, err := ParseDate(s)
+	if err != nil {
+		t.Fatalf("ParseDate(%q): %v", s, err)
+	}
+	return day
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	cal := newTestCalendar(t)
+
+	for _, tc := range []struct {
+		name  string
+		start string
+		n     int
+		want  string
+	}{
+		{"zero days", "2025-03-04", 0, "2025-03-04"},
+		{"within a week", "2025-03-04", 2, "2025-03-06"},
+		{"over a weekend", "2025-03-07", 1, "2025-03-10"},
+		{"from a Saturday", "2025-07-05", 1, "2025-07-07"},
+		{"over a Monday holiday", "2025-01-17", 1, "2025-01-21"},
+		{"over a holiday and a weekend", "2025-11-26", 2, "2025-12-01"},
+		{"over Christmas", "2025-12-24", 1, "2025-12-26"},
+		{"into the next year over New Year's Day", "2025-12-31", 1, "2026-01-02"},
+		{"back into the previous year over New Year's Day", "2026-01-02", -1, "2025-12-31"},
+		{"back over a weekend and a holiday", "2025-07-07", -1, "2025-07-03"},
+		{"back from a Sunday", "2025-03-09", -1, "2025-03-07"},
+		{"many days across a year end", "2025-12-15", 15, "2026-01-07"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := cal.AddBusinessDays(mustParseDate(t, tc.start), tc.n)
+			if err != nil {
+				t.Fatalf("AddBusinessDays: %v", err)
+			}
+			if FormatDate(got) != tc.want {
+				t.Errorf("AddBusinessDays(%s, %d) = %s, want %s", tc.start, tc.n, FormatDate(got), tc.want)
+			}
+		})
+	}
+}
+
+func TestAddBusinessDaysCountsFromTheEasternDay(t *testing.T) {
+	cal := newTestCalendar(t)
+
+	// 03:00 UTC on Christmas is still the 24th in New York
+	got, err := cal.AddBusinessDays(time.Date(2025, 12, 25, 3, 0, 0, 0, time.UTC), 1)
+	if err != nil {
+		t.Fatalf("AddBusinessDays: %v", err)
+	}
+	if FormatDate(got) != "2025-12-26" {
+		t.Errorf("got %s, want 2025-12-26", FormatDate(got))
+	}
+}
+
+func TestAddBusinessDaysOutsideCalendar(t *testing.T) {
+	cal := newTestCalendar(t)
+
+	for _, tc := range []struct {
+		start string
+		n     int
+	}{
+		{"2026-12-31", 1},
+		{"2025-01-02", -1},
+		{"2026-12-28", 5},
+	} {
+		if _, err := cal.AddBusinessDays(mustParseDate(t, tc.start), tc.n); !errors.Is(err, ErrOutsideCalendar) {
+			t.Errorf("AddBusinessDays(%s, %d) err = %v, want ErrOutsideCalendar", tc.start, tc.n, err)
+		}
+	}
+}
+
+func TestOnOrAfter(t *testing.T) {
+	cal := newTestCalendar(t)
+
+	for start, want := range map[string]string{
+		"2025-03-04": "2025-03-04",
+		"2025-03-08": "2025-03-10",
+		"2025-12-25": "2025-12-26",
+	} {
+		got, err := cal.OnOrAfter(mustParseDate(t, start))
+		if err != nil {
+			t.Fatalf("OnOrAfter(%s): %v", start, err)
+		}
+		if FormatDate(got) != want {
+			t.Errorf("OnOrAfter(%s) = %s, want %s", start, FormatDate(got), want)
+		}
+	}
+
+	if _, err := cal.OnOrAfter(mustParseDate(t, "2027-01-04")); !errors.Is(err, ErrOutsideCalendar) {
+		t.Errorf("OnOrAfter outside the calendar err = %v, want ErrOutsideCalendar", err)
+	}
+}
+
+func TestCovers(t *testing.T) {
+	cal := newTestCalendar(t)
+
+	for date, want := range map[string]bool{
+		"2024-12-31": false,
+		"2025-01-01": true,
+		"2026-12-31": true,
+		"2027-01-01": false,
+	} {
+		if got := cal.Covers(mustParseDate(t, date)); got != want {
+			t.Errorf("Covers(%s) = %v, want %v", date, got, want)
+		}
+	}
+}
+
+func TestNewRejectsBadInput(t *testing.T) {
+	if _, err := New(strings.NewReader("# nothing but comments\n")); err == nil {
+		t.Error("New with no holidays succeeded, want an error")
+	}
+	if _, err := New(strings.NewReader("2025-13-01 Not a date\n")); err == nil {
+		t.Error("New with an invalid date succeeded, want an error")
+	}
+}
+
+func TestDefaultCalendar(t *testing.T) {
+	cal := Default()
+
+	// Juneteenth 2026 is a Friday, so the next business day is Monday
+	got, err := cal.NextBusinessDay(mustParseDate(t, "2026-06-18"))
+	if err != nil {
+		t.Fatalf("NextBusinessDay: %v", err)
+	}
+	if FormatDate(got) != "2026-06-22" {
+		t.Errorf("NextBusinessDay(2026-06-18) = %s, want 2026-06-22", FormatDate(got))
+	}
+}
@@ -0,0 +1,96 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// developmentKeySeed derives the key used when ACCOUNT_ENCRYPTION_DEV_KEY=true and
+// ACCOUNT_ENCRYPTION_KEY is not set. It is in the source, so it protects nothing; never
+// rely on it outside local development.
+const developmentKeySeed = "ach-concourse-development-key"
+
+// FieldCipher encrypts individual column values (e.g., account numbers) with AES-256-GCM
+type FieldCipher struct {
+	aead cipher.AEAD
+}
+
+// NewFieldCipher creates a new field cipher from a 32 byte key
+func NewFieldCipher(key []byte) (*FieldCipher, error) {
+	if len(key) != 32 {
+		return nil, errors.New("encryption key must be 32 bytes")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return &FieldCipher{aead: aead}, nil
+}
+
+// NewFieldCipherFromEnv creates a field cipher from the base64 encoded ACCOUNT_ENCRYPTION_KEY.
+// A missing key is an error unless ACCOUNT_ENCRYPTION_DEV_KEY=true explicitly opts into the
+// well-known development key.
+func NewFieldCipherFromEnv() (*FieldCipher, error) {
+	encoded := os.Getenv("ACCOUNT_ENCRYPTION_KEY")
+	if encoded == "" {
+		if os.Getenv("ACCOUNT_ENCRYPTION_DEV_KEY") != "true" {
+			return nil, errors.New("ACCOUNT_ENCRYPTION_KEY is required (generate one with: openssl rand -base64 32)")
+		}
+		log.Println("ACCOUNT_ENCRYPTION_DEV_KEY set; account numbers are encrypted with the public development key")
+		key := sha256.Sum256([]byte(developmentKeySeed))
+		return NewFieldCipher(key[:])
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode ACCOUNT_ENCRYPTION_KEY: %w", err)
+	}
+
+	return NewFieldCipher(key)
+}
+
+// Encrypt encrypts plaintext and returns base64(nonce || ciphertext)
+func (c *FieldCipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt reverses Encrypt
+func (c *FieldCipher) Decrypt(encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode ciphertext: %w", err)
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("ciphertext too short")
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return string(plaintext), nil
+}
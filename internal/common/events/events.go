@@ -0,0 +1,320 @@
+// Package events carries ACH entry lifecycle changes from the ODFI and RDFI services to
+// the ledger. A status change and its event are written in the same transaction (an
+// outbox), and a Dispatcher delivers pending events in order until the ledger accepts them
+// or rejects them outright.
+package events
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ach-concourse/internal/common/idempotency"
+)
+
+// EntryEvent is a single status transition of an ACH entry
+type EntryEvent struct {
+	ID             string    `json:"id"`
+	Side           string    `json:"side"` // ODFI or RDFI
+	EntryID        string    `json:"entry_id"`
+	TraceNumber    string    `json:"trace_number"`
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previous_status"`
+	Direction      string    `json:"direction"` // DEBIT or CREDIT, from the transaction code
+	AmountCents    int64     `json:"amount_cents"`
+	SettlementDate string    `json:"settlement_date,omitempty"` // YYYY-MM-DD, when known
+	Reason         string    `json:"reason,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// DefaultDispatchInterval is how often pending events are polled unless
+// EVENT_DISPATCH_INTERVAL is set
+const DefaultDispatchInterval = 2 * time.Second
+
+// dispatchBatchSize bounds the number of events delivered per poll
+const dispatchBatchSize = 100
+
+// OutboxSchema returns the SQL schema for an outbox table
+func OutboxSchema(table string) string {
+	return fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %[1]s (
+	id UUID PRIMARY KEY,
+	entry_id UUID NOT NULL,
+	trace_number TEXT,
+	status TEXT NOT NULL,
+	previous_status TEXT NOT NULL,
+	direction TEXT,
+	amount_cents BIGINT NOT NULL,
+	reason TEXT,
+	occurred_at TIMESTAMPTZ NOT NULL,
+	delivered_at TIMESTAMPTZ,
+	attempts INT NOT NULL DEFAULT 0,
+	last_error TEXT
+);
+
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS settlement_date DATE;
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS parked_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_%[1]s_pending ON %[1]s(occurred_at) WHERE delivered_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_%[1]s_entry_id ON %[1]s(entry_id);
+`, table)
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Outbox stores the events of one side in a table created with OutboxSchema
+type Outbox struct {
+	db    *sql.DB
+	table string
+	side  string
+}
+
+// NewOutbox creates an outbox for side's events in table
+func NewOutbox(db *sql.DB, table, side string) *Outbox {
+	return &Outbox{db: db, table: table, side: side}
+}
+
+// Record writes an event inside tx, the transaction that makes the status change
+func (o *Outbox) Record(ctx context.Context, tx execer, event *EntryEvent) error {
+	event.ID = uuid.New().String()
+	event.Side = o.side
+	event.OccurredAt = time.Now()
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO `+o.table+` (id, entry_id, trace_number, status, previous_status, direction, amount_cents, settlement_date, reason, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, event.ID, event.EntryID, event.TraceNumber, event.Status, event.PreviousStatus,
+		event.Direction, event.AmountCents, nullString(event.SettlementDate), nullString(event.Reason), event.OccurredAt)
+	return err
+}
+
+// Pending returns undelivered events that are not parked, oldest first. An event whose entry
+// has an earlier parked event is held back too, so an entry's transitions still reach the
+// ledger in order once the parked one is queued again.
+func (o *Outbox) Pending(ctx context.Context, limit int) ([]*EntryEvent, error) {
+	rows, err := o.db.QueryContext(ctx, `
+		SELECT id, entry_id, trace_number, status, previous_status, direction, amount_cents,
+			to_char(settlement_date, 'YYYY-MM-DD'), reason, occurred_at
+		FROM `+o.table+` e
+		WHERE delivered_at IS NULL AND parked_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM `+o.table+` p
+				WHERE p.entry_id = e.entry_id AND p.parked_at IS NOT NULL
+					AND (p.occurred_at, p.id) < (e.occurred_at, e.id)
+			)
+		ORDER BY occurred_at, id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pending []*EntryEvent
+	for rows.Next() {
+		event := &EntryEvent{Side: o.side}
+		var traceNumber, direction, settlementDate, reason sql.NullString
+		err := rows.Scan(&event.ID, &event.EntryID, &traceNumber, &event.Status, &event.PreviousStatus,
+			&direction, &event.AmountCents, &settlementDate, &reason, &event.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+		event.TraceNumber = traceNumber.String
+		event.Direction = direction.String
+		event.SettlementDate = settlementDate.String
+		event.Reason = reason.String
+		pending = append(pending, event)
+	}
+
+	return pending, rows.Err()
+}
+
+// MarkDelivered records that the ledger accepted an event
+func (o *Outbox) MarkDelivered(ctx context.Context, id string) error {
+	_, err := o.db.ExecContext(ctx, `
+		UPDATE `+o.table+` SET delivered_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = $1
+	`, id)
+	return err
+}
+
+// MarkFailed records a failed delivery attempt; the event stays pending
+func (o *Outbox) MarkFailed(ctx context.Context, id string, cause error) error {
+	_, err := o.db.ExecContext(ctx, `
+		UPDATE `+o.table+` SET attempts = attempts + 1, last_error = $2 WHERE id = $1
+	`, id, cause.Error())
+	return err
+}
+
+// MarkParked records a delivery the ledger rejected. The event is no longer dispatched;
+// clearing parked_at queues it again once the cause is fixed.
+func (o *Outbox) MarkParked(ctx context.Context, id string, cause error) error {
+	_, err := o.db.ExecContext(ctx, `
+		UPDATE `+o.table+` SET parked_at = NOW(), attempts = attempts + 1, last_error = $2 WHERE id = $1
+	`, id, cause.Error())
+	return err
+}
+
+// RejectedError is a delivery the ledger refused with a 4xx status. Sending the same event
+// again cannot succeed, so it is parked instead of retried.
+type RejectedError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("ledger rejected event with status %d: %s", e.StatusCode, e.Message)
+}
+
+// Dispatcher delivers outbox events to the ledger service
+type Dispatcher struct {
+	outbox   *Outbox
+	url      string
+	interval time.Duration
+	client   *http.Client
+}
+
+// NewDispatcher creates a dispatcher that posts events to ledgerURL's /api/v1/events
+func NewDispatcher(outbox *Outbox, ledgerURL string, interval time.Duration) *Dispatcher {
+	return &Dispatcher{
+		outbox:   outbox,
+		url:      strings.TrimRight(ledgerURL, "/") + "/api/v1/events",
+		interval: interval,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewDispatcherFromEnv creates a dispatcher using LEDGER_BASE_URL and
+// EVENT_DISPATCH_INTERVAL (a Go duration such as "5s"). It returns nil when
+// LEDGER_BASE_URL is not set, leaving events queued in the outbox.
+func NewDispatcherFromEnv(outbox *Outbox) *Dispatcher {
+	ledgerURL := os.Getenv("LEDGER_BASE_URL")
+	if ledgerURL == "" {
+		return nil
+	}
+
+	interval := DefaultDispatchInterval
+	if value := os.Getenv("EVENT_DISPATCH_INTERVAL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			interval = parsed
+		}
+	}
+
+	return NewDispatcher(outbox, ledgerURL, interval)
+}
+
+// Run delivers pending events every interval until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DispatchPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("event dispatch: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending delivers pending events in order. An event the ledger rejects is parked,
+// along with the later events of its entry, and dispatch moves on to other entries, so one
+// bad event cannot hold up the rest. Any other failure stops dispatch. Either way an entry's
+// later transitions never reach the ledger before its earlier ones.
+func (d *Dispatcher) DispatchPending(ctx context.Context) error {
+	pending, err := d.outbox.Pending(ctx, dispatchBatchSize)
+	if err != nil {
+		return err
+	}
+
+	// Entries with an event parked in this pass; Pending holds back their later events from
+	// the next pass on
+	parked := map[string]bool{}
+	for _, event := range pending {
+		if parked[event.EntryID] {
+			continue
+		}
+		if err := d.deliver(ctx, event); err != nil {
+			var rejected *RejectedError
+			if errors.As(err, &rejected) {
+				log.Printf("event dispatch: parking event %s for entry %s: %v", event.ID, event.EntryID, err)
+				if err := d.outbox.MarkParked(ctx, event.ID, err); err != nil {
+					return err
+				}
+				parked[event.EntryID] = true
+				continue
+			}
+			d.outbox.MarkFailed(ctx, event.ID, err)
+			return fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		if err := d.outbox.MarkDelivered(ctx, event.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deliver posts one event; the event ID doubles as the Idempotency-Key so a redelivery
+// after a lost response is answered from the ledger's stored result
+func (d *Dispatcher) deliver(ctx context.Context, event *EntryEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotency.HeaderKey, event.ID)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if permanentStatus(resp.StatusCode) {
+			return &RejectedError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(message))}
+		}
+		return fmt.Errorf("ledger returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
+
+// permanentStatus reports whether a ledger response rejects the event itself. Timeouts,
+// rate limits and 409, which the ledger returns while an earlier delivery of the event is
+// still being processed, are worth retrying.
+func permanentStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return status >= 400 && status < 500
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -2,7 +2,10 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"ach-concourse/internal/common/validation"
 )
 
 // ErrorResponse represents a standard error response
@@ -27,6 +30,23 @@ func Error(w http.ResponseWriter, status int, message string) {
 	JSON(w, status, ErrorResponse{Error: message})
 }
 
+// ValidationErrorResponse represents a field-level validation failure
+type ValidationErrorResponse struct {
+	Error  string                  `json:"error"`
+	Fields []validation.FieldError `json:"fields"`
+}
+
+// ErrorFrom writes err as a ValidationErrorResponse (400) if it carries field errors,
+// otherwise as a plain error response with the given status
+func ErrorFrom(w http.ResponseWriter, status int, err error) {
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		JSON(w, http.StatusBadRequest, ValidationErrorResponse{Error: "validation failed", Fields: fieldErrs})
+		return
+	}
+	Error(w, status, err.Error())
+}
+
 // HealthResponse represents a standard health check response
 type HealthResponse struct {
 	Status string `json:"status"`
@@ -0,0 +1,275 @@
+// Package idempotency makes POST endpoints safe to retry. A request carrying an
+// Idempotency-Key header is executed once; retries with the same key and body get the
+// stored response back, and reusing a key with a different body is rejected with 422.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	commonhttp "ach-concourse/internal/common/http"
+)
+
+// HeaderKey is the request header carrying the client's idempotency key
+const HeaderKey = "Idempotency-Key"
+
+// HeaderReplayed is set on responses that were served from a stored result
+const HeaderReplayed = "Idempotent-Replayed"
+
+// DefaultRetention is how long keys are remembered unless IDEMPOTENCY_RETENTION is set
+const DefaultRetention = 24 * time.Hour
+
+// DefaultInFlightTimeout is how long a key may stay reserved without a response before a
+// retry takes it over, unless IDEMPOTENCY_IN_FLIGHT_TIMEOUT is set. It outlasts any request,
+// so only reservations orphaned by a crash are reclaimed.
+const DefaultInFlightTimeout = 5 * time.Minute
+
+// maxKeyLength bounds the accepted header value
+const maxKeyLength = 255
+
+const schema = `
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	scope TEXT NOT NULL,
+	key TEXT NOT NULL,
+	request_hash TEXT NOT NULL,
+	status_code INT,
+	content_type TEXT,
+	response_body BYTEA,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+`
+
+// GetSchema returns the SQL schema for the idempotency key table
+func GetSchema() string {
+	return schema
+}
+
+// Store persists idempotency keys and the responses recorded for them
+type Store struct {
+	db              *sql.DB
+	retention       time.Duration
+	inFlightTimeout time.Duration
+	lastPurge       atomic.Int64
+}
+
+// NewStore creates a store that remembers keys for the given retention window and reclaims
+// reservations left without a response for longer than inFlightTimeout
+func NewStore(db *sql.DB, retention, inFlightTimeout time.Duration) *Store {
+	return &Store{db: db, retention: retention, inFlightTimeout: inFlightTimeout}
+}
+
+// NewStoreFromEnv creates a store using IDEMPOTENCY_RETENTION and
+// IDEMPOTENCY_IN_FLIGHT_TIMEOUT (Go durations such as "48h"), falling back to
+// DefaultRetention and DefaultInFlightTimeout
+func NewStoreFromEnv(db *sql.DB) *Store {
+	return NewStore(db,
+		durationFromEnv("IDEMPOTENCY_RETENTION", DefaultRetention),
+		durationFromEnv("IDEMPOTENCY_IN_FLIGHT_TIMEOUT", DefaultInFlightTimeout))
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	if value := os.Getenv(name); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// record is a stored key; a nil StatusCode means the original request is still running
+type record struct {
+	RequestHash  string
+	StatusCode   sql.NullInt64
+	ContentType  sql.NullString
+	ResponseBody []byte
+}
+
+// reserve claims scope/key for a new request. When the claim succeeds it returns the
+// reservation time, which identifies the reservation to complete and release; otherwise it
+// returns the existing record.
+func (s *Store) reserve(ctx context.Context, scope, key, requestHash string) (time.Time, *record, error) {
+	now := time.Now()
+	cutoff := now.Add(-s.retention)
+
+	// A key past its retention window is forgotten and may be used again, and so is a
+	// reservation that never got a response because its process died mid-request
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE scope = $1 AND key = $2
+			AND (created_at < $3 OR (status_code IS NULL AND created_at < $4))
+	`, scope, key, cutoff, now.Add(-s.inFlightTimeout))
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	var reservedAt time.Time
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_keys (scope, key, request_hash, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (scope, key) DO NOTHING
+		RETURNING created_at
+	`, scope, key, requestHash).Scan(&reservedAt)
+	if err == nil {
+		s.purgeExpired(ctx, cutoff)
+		return reservedAt, nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return time.Time{}, nil, err
+	}
+
+	existing := &record{}
+	err = s.db.QueryRowContext(ctx, `
+		SELECT request_hash, status_code, content_type, response_body
+		FROM idempotency_keys WHERE scope = $1 AND key = $2
+	`, scope, key).Scan(&existing.RequestHash, &existing.StatusCode, &existing.ContentType, &existing.ResponseBody)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+
+	return time.Time{}, existing, nil
+}
+
+// complete stores the response for a reservation. A reservation that was reclaimed as
+// stale in the meantime belongs to another request and is left alone.
+func (s *Store) complete(ctx context.Context, scope, key string, reservedAt time.Time, status int, contentType string, body []byte) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET status_code = $4, content_type = $5, response_body = $6
+		WHERE scope = $1 AND key = $2 AND created_at = $3
+	`, scope, key, reservedAt, status, contentType, body)
+	return err
+}
+
+// release forgets a reservation so that the request can be retried
+func (s *Store) release(ctx context.Context, scope, key string, reservedAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND created_at = $3
+	`, scope, key, reservedAt)
+	return err
+}
+
+// replayable reports whether a response is stored and replayed to retries. Successes are,
+// as are 409 and 422, which describe a conflict with the resource's state. Other failures
+// are released so the retry runs again: services report some transient errors as 400, and
+// a request that failed changed nothing.
+func replayable(status int) bool {
+	return (status >= 200 && status < 300) || status == http.StatusConflict || status == http.StatusUnprocessableEntity
+}
+
+// purgeExpired deletes keys past the retention window, at most once an hour
+func (s *Store) purgeExpired(ctx context.Context, cutoff time.Time) {
+	now := time.Now().Unix()
+	last := s.lastPurge.Load()
+	if now-last < int64(time.Hour/time.Second) || !s.lastPurge.CompareAndSwap(last, now) {
+		return
+	}
+	s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < $1`, cutoff)
+}
+
+// Middleware executes each keyed request at most once per route. Requests without the
+// header pass straight through. Only replayable responses are stored; any other failure
+// can be retried with the same key.
+func Middleware(store *Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderKey)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				commonhttp.Error(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := r.Method + " " + r.URL.Path
+			sum := sha256.Sum256(body)
+			requestHash := hex.EncodeToString(sum[:])
+
+			reservedAt, existing, err := store.reserve(r.Context(), scope, key, requestHash)
+			if err != nil {
+				commonhttp.Error(w, http.StatusInternalServerError, "failed to check Idempotency-Key")
+				return
+			}
+
+			if existing != nil {
+				switch {
+				case existing.RequestHash != requestHash:
+					commonhttp.Error(w, http.StatusUnprocessableEntity, "Idempotency-Key has already been used with a different request body")
+				case !existing.StatusCode.Valid:
+					commonhttp.Error(w, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+				default:
+					if existing.ContentType.Valid {
+						w.Header().Set("Content-Type", existing.ContentType.String)
+					}
+					w.Header().Set(HeaderReplayed, "true")
+					w.WriteHeader(int(existing.StatusCode.Int64))
+					w.Write(existing.ResponseBody)
+				}
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w}
+			defer func() {
+				// Use a fresh context: the request's may already be cancelled
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				// Nothing written means the handler panicked
+				// A key left reserved answers retries with 409 until the in-flight timeout
+				// reclaims it
+				if !replayable(rec.status) {
+					if err := store.release(ctx, scope, key, reservedAt); err != nil {
+						log.Printf("idempotency: failed to release key %q for %s: %v", key, scope, err)
+					}
+					return
+				}
+				err := store.complete(ctx, scope, key, reservedAt, rec.status, rec.Header().Get("Content-Type"), rec.body.Bytes())
+				if err != nil {
+					log.Printf("idempotency: failed to store response for key %q for %s: %v", key, scope, err)
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// recorder captures the status and body written by a handler while passing them through.
+// status stays zero until the handler writes.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,182 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default and maximum page sizes
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Sort order constants
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// Field is a column a listing can be sorted by. Column must never be NULL, since rows are
+// paged by comparing (Column, id) with the last row of the previous page.
+type Field struct {
+	// Column is the SQL expression sorted on
+	Column string
+	// Type is the SQL type a cursor value is cast back to, e.g. TIMESTAMPTZ or DATE
+	Type string
+}
+
+// Params holds the limit, sort and cursor of a list request
+type Params struct {
+	Limit        int
+	SortBy       string
+	SortOrder    string
+	IncludeTotal bool
+
+	cursor *cursor
+}
+
+// Page is the envelope list endpoints answer with. NextCursor is set when more rows follow;
+// TotalCount is set when the request asked for include_total.
+type Page[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	TotalCount *int64 `json:"total_count,omitempty"`
+}
+
+// cursor is the position after the last row of a page. It records the sort it was issued
+// for, so it cannot be replayed against a different one.
+type cursor struct {
+	SortBy    string `json:"s"`
+	SortOrder string `json:"o"`
+	Value     string `json:"v"`
+	ID        string `json:"id"`
+}
+
+// FromQuery reads limit, cursor, sort_by, sort_order and include_total from q. sort_by must
+// name one of fields and defaults to defaultSort; sort_order defaults to desc.
+func FromQuery(q url.Values, fields map[string]Field, defaultSort string) (Params, error) {
+	p := Params{Limit: DefaultLimit, SortBy: defaultSort, SortOrder: SortDesc}
+
+	if value := q.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Params{}, fmt.Errorf("limit must be between 1 and %d", MaxLimit)
+		}
+		p.Limit = limit
+	}
+
+	if value := q.Get("sort_by"); value != "" {
+		if _, ok := fields[value]; !ok {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return Params{}, fmt.Errorf("sort_by must be one of: %s", strings.Join(names, ", "))
+		}
+		p.SortBy = value
+	}
+
+	if value := q.Get("sort_order"); value != "" {
+		if value != SortAsc && value != SortDesc {
+			return Params{}, errors.New("sort_order must be 'asc' or 'desc'")
+		}
+		p.SortOrder = value
+	}
+
+	if value := q.Get("include_total"); value != "" {
+		includeTotal, err := strconv.ParseBool(value)
+		if err != nil {
+			return Params{}, errors.New("include_total must be true or false")
+		}
+		p.IncludeTotal = includeTotal
+	}
+
+	if value := q.Get("cursor"); value != "" {
+		c, err := decodeCursor(value)
+		if err != nil {
+			return Params{}, errors.New("cursor is invalid")
+		}
+		if c.SortBy != p.SortBy || c.SortOrder != p.SortOrder {
+			return Params{}, errors.New("cursor was issued for a different sort_by or sort_order")
+		}
+		p.cursor = c
+	}
+
+	return p, nil
+}
+
+// Query appends the listing's keyset condition, ORDER BY and LIMIT to query, whose WHERE
+// clause is already open, numbering placeholders from argNum. idColumn is the unique
+// column that breaks ties. One row more than the limit is fetched so Trim can tell whether
+// another page follows.
+func (p Params) Query(query string, fields map[string]Field, idColumn string, argNum int) (string, []any) {
+	field := fields[p.SortBy]
+	direction, comparison := "DESC", "<"
+	if p.SortOrder == SortAsc {
+		direction, comparison = "ASC", ">"
+	}
+
+	var args []any
+	if p.cursor != nil {
+		query += fmt.Sprintf(" AND (%s, %s) %s ($%d::%s, $%d::UUID)",
+			field.Column, idColumn, comparison, argNum, field.Type, argNum+1)
+		args = append(args, p.cursor.Value, p.cursor.ID)
+		argNum += 2
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT $%d", field.Column, direction, idColumn, direction, argNum)
+	args = append(args, p.Limit+1)
+
+	return query, args
+}
+
+// SortKey is the expression to select alongside each row, as text, so Trim can build the
+// next cursor from the last row
+func (p Params) SortKey(fields map[string]Field) string {
+	return "(" + fields[p.SortBy].Column + ")::TEXT"
+}
+
+// Trim cuts rows fetched by Query down to the page and returns the cursor for the next page,
+// or "" when this is the last page. keys and ids are each row's sort key and id.
+func Trim[T any](p Params, rows []T, keys, ids []string) ([]T, string) {
+	if len(rows) <= p.Limit {
+		return rows, ""
+	}
+
+	last := p.Limit - 1
+	return rows[:p.Limit], encodeCursor(&cursor{
+		SortBy:    p.SortBy,
+		SortOrder: p.SortOrder,
+		Value:     keys[last],
+		ID:        ids[last],
+	})
+}
+
+func encodeCursor(c *cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if c.ID == "" {
+		return nil, errors.New("cursor has no id")
+	}
+	return &c, nil
+}
@@ -0,0 +1,122 @@
+package pagination
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+var testFields = map[string]Field{
+	"created_at": {Column: "created_at", Type: "TIMESTAMPTZ"},
+	"amount":     {Column: "amount_cents", Type: "BIGINT"},
+}
+
+func TestFromQueryDefaults(t *testing.T) {
+	p, err := FromQuery(url.Values{}, testFields, "created_at")
+	if err != nil {
+		t.Fatalf("FromQuery: %v", err)
+	}
+	if p.Limit != DefaultLimit || p.SortBy != "created_at" || p.SortOrder != SortDesc || p.IncludeTotal {
+		t.Errorf("got %+v, want the defaults", p)
+	}
+	if p.cursor != nil {
+		t.Errorf("cursor = %+v, want none", p.cursor)
+	}
+}
+
+func TestFromQueryRejectsBadParams(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"zero limit", "limit=0", "limit must be between"},
+		{"limit too large", "limit=1001", "limit must be between"},
+		{"non-numeric limit", "limit=ten", "limit must be between"},
+		{"unknown sort", "sort_by=name", "sort_by must be one of: amount, created_at"},
+		{"bad order", "sort_order=up", "sort_order must be"},
+		{"bad include_total", "include_total=maybe", "include_total must be"},
+		{"garbage cursor", "cursor=!!!", "cursor is invalid"},
+		{"cursor without id", "cursor=" + encodeCursor(&cursor{SortBy: "created_at", SortOrder: SortDesc, Value: "x"}), "cursor is invalid"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tc.query)
+			if err != nil {
+				t.Fatalf("ParseQuery: %v", err)
+			}
+			_, err = FromQuery(q, testFields, "created_at")
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want one containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestTrimCursorRoundTrip(t *testing.T) {
+	p, err := FromQuery(url.Values{"limit": {"2"}, "sort_by": {"amount"}, "sort_order": {"asc"}}, testFields, "created_at")
+	if err != nil {
+		t.Fatalf("FromQuery: %v", err)
+	}
+
+	rows := []string{"a", "b", "c"}
+	keys := []string{"100", "200", "300"}
+	ids := []string{"id-a", "id-b", "id-c"}
+	page, next := Trim(p, rows, keys, ids)
+	if len(page) != 2 || page[0] != "a" || page[1] != "b" {
+		t.Fatalf("page = %v, want [a b]", page)
+	}
+	if next == "" {
+		t.Fatal("next cursor is empty, want one since a row was left over")
+	}
+
+	q := url.Values{"limit": {"2"}, "sort_by": {"amount"}, "sort_order": {"asc"}, "cursor": {next}}
+	resumed, err := FromQuery(q, testFields, "created_at")
+	if err != nil {
+		t.Fatalf("FromQuery with cursor: %v", err)
+	}
+	if resumed.cursor == nil || resumed.cursor.Value != "200" || resumed.cursor.ID != "id-b" {
+		t.Fatalf("cursor = %+v, want the last row of the first page", resumed.cursor)
+	}
+
+	query, args := resumed.Query("SELECT * FROM entries WHERE 1=1", testFields, "id", 1)
+	wantQuery := "SELECT * FROM entries WHERE 1=1 AND (amount_cents, id) > ($1::BIGINT, $2::UUID) ORDER BY amount_cents ASC, id ASC LIMIT $3"
+	if query != wantQuery {
+		t.Errorf("query = %q, want %q", query, wantQuery)
+	}
+	if len(args) != 3 || args[0] != "200" || args[1] != "id-b" || args[2] != 3 {
+		t.Errorf("args = %v, want [200 id-b 3]", args)
+	}
+}
+
+func TestTrimLastPage(t *testing.T) {
+	p := Params{Limit: 3, SortBy: "created_at", SortOrder: SortDesc}
+	page, next := Trim(p, []int{1, 2, 3}, []string{"k1", "k2", "k3"}, []string{"1", "2", "3"})
+	if len(page) != 3 || next != "" {
+		t.Errorf("got %v and cursor %q, want every row and no cursor", page, next)
+	}
+}
+
+func TestCursorRejectedForDifferentSort(t *testing.T) {
+	next := encodeCursor(&cursor{SortBy: "created_at", SortOrder: SortDesc, Value: "2025-01-02", ID: "id-1"})
+
+	for _, q := range []url.Values{
+		{"cursor": {next}, "sort_order": {"asc"}},
+		{"cursor": {next}, "sort_by": {"amount"}},
+	} {
+		if _, err := FromQuery(q, testFields, "created_at"); err == nil || !strings.Contains(err.Error(), "different sort_by or sort_order") {
+			t.Errorf("FromQuery(%v) err = %v, want a sort mismatch", q, err)
+		}
+	}
+}
+
+func TestQueryWithoutCursor(t *testing.T) {
+	p := Params{Limit: 10, SortBy: "created_at", SortOrder: SortDesc}
+	query, args := p.Query("SELECT * FROM entries WHERE status = $1", testFields, "id", 2)
+	want := "SELECT * FROM entries WHERE status = $1 ORDER BY created_at DESC, id DESC LIMIT $2"
+	if query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+	if len(args) != 1 || args[0] != 11 {
+		t.Errorf("args = %v, want [11]", args)
+	}
+}
@@ -2,64 +2,117 @@ package console
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 
+	"ach-concourse/internal/common/auth"
+	"ach-concourse/internal/common/calendar"
 	commonhttp "ach-concourse/internal/common/http"
+	"ach-concourse/internal/common/idempotency"
 )
 
 // Handler handles HTTP requests for console
 type Handler struct {
 	service *Service
+	policy  *auth.Policy
 }
 
-// NewHandler creates a new console handler
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+// NewHandler creates a new console handler. policy guards each route with a permission;
+// nil leaves every route open.
+func NewHandler(service *Service, policy *auth.Policy) *Handler {
+	return &Handler{service: service, policy: policy}
 }
 
-// RegisterRoutes registers all console routes
+// RegisterRoutes registers all console routes, each guarded by the permission it requires
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	// Unified ACH items (legacy endpoints for backward compatibility)
 	r.Route("/api/v1/ach-items", func(r chi.Router) {
-		r.Get("/", h.GetAchItems)
-		r.Get("/{side}/{id}", h.GetAchItem)
-		r.Post("/{side}/{id}/return", h.ReturnEntry)
+		r.With(h.require(PermAchItemsRead)).Get("/", h.GetAchItems)
+		r.With(h.require(PermAchItemsRead)).Get("/{side}/{id}", h.GetAchItem)
+		r.With(h.require(PermRDFIReturn)).Post("/{side}/{id}/return", h.ReturnEntry)
 	})
 
 	// ODFI operations via gateway
 	r.Route("/api/v1/odfi/entries", func(r chi.Router) {
-		r.Post("/", h.CreateODFIEntry)
-		r.Get("/", h.ListODFIEntries)
-		r.Get("/{id}", h.GetODFIEntry)
-		r.Patch("/{id}/status", h.UpdateODFIStatus)
+		r.With(h.require(PermODFICreate), idempotencyKey).Post("/", h.CreateODFIEntry)
+		r.With(h.require(PermODFIRead)).Get("/", h.ListODFIEntries)
+		r.With(h.require(PermODFIRead)).Get("/{id}", h.GetODFIEntry)
+		r.With(h.require(PermODFIStatus)).Patch("/{id}/status", h.UpdateODFIStatus)
+	})
+
+	r.Route("/api/v1/odfi/batches", func(r chi.Router) {
+		r.With(h.require(PermODFICreate), idempotencyKey).Post("/", h.CreateODFIBatch)
+		r.With(h.require(PermODFIRead)).Get("/", h.ListODFIBatches)
+		r.With(h.require(PermODFIRead)).Get("/{id}", h.GetODFIBatch)
+		r.With(h.require(PermODFICreate), idempotencyKey).Post("/{id}/entries", h.AddODFIBatchEntry)
+		r.With(h.require(PermODFICreate)).Post("/{id}/close", h.CloseODFIBatch)
+		r.With(h.require(PermODFICreate)).Post("/{id}/cancel", h.CancelODFIBatch)
 	})
 
 	// RDFI operations via gateway
 	r.Route("/api/v1/rdfi/entries", func(r chi.Router) {
-		r.Post("/", h.CreateRDFIEntry)
-		r.Get("/", h.ListRDFIEntries)
-		r.Get("/{id}", h.GetRDFIEntry)
-		r.Post("/{id}/return", h.ReturnRDFIEntry)
+		r.With(h.require(PermRDFICreate), idempotencyKey).Post("/", h.CreateRDFIEntry)
+		r.With(h.require(PermRDFIRead)).Get("/", h.ListRDFIEntries)
+		r.With(h.require(PermRDFIRead)).Get("/{id}", h.GetRDFIEntry)
+		r.With(h.require(PermRDFICreate)).Post("/{id}/post", h.PostRDFIEntry)
+		r.With(h.require(PermRDFIReturn)).Post("/{id}/return", h.ReturnRDFIEntry)
 	})
 
 	// Ledger operations via gateway
 	r.Route("/api/v1/ledger", func(r chi.Router) {
-		r.Post("/postings", h.CreateLedgerPosting)
-		r.Get("/postings", h.ListLedgerPostings)
-		r.Get("/balances", h.GetBalances)
+		r.With(h.require(PermLedgerPost), idempotencyKey).Post("/postings", h.CreateLedgerPosting)
+		r.With(h.require(PermLedgerRead)).Get("/postings", h.ListLedgerPostings)
+		r.With(h.require(PermLedgerPost)).Post("/postings/{id}/reverse", h.ReverseLedgerPosting)
+		r.With(h.require(PermLedgerPost), idempotencyKey).Post("/journal-entries", h.CreateLedgerJournalEntry)
+		r.With(h.require(PermLedgerRead)).Get("/journal-entries", h.ListLedgerJournalEntries)
+		r.With(h.require(PermLedgerRead)).Get("/journal-entries/{id}", h.GetLedgerJournalEntry)
+		r.With(h.require(PermLedgerRead)).Get("/events", h.ListLedgerEvents)
+		r.With(h.require(PermLedgerRead)).Get("/accounts", h.ListLedgerAccounts)
+		r.With(h.require(PermLedgerRead)).Get("/periods", h.ListLedgerPeriods)
+		r.With(h.require(PermLedgerRead)).Get("/periods/{date}", h.GetLedgerPeriod)
+		r.With(h.require(PermLedgerSettle)).Post("/periods/{date}/close", h.CloseLedgerPeriod)
+		r.With(h.require(PermLedgerSettle)).Post("/periods/{date}/reopen", h.ReopenLedgerPeriod)
+		r.With(h.require(PermLedgerRead)).Get("/settlements", h.GetLedgerSettlement)
+		r.With(h.require(PermLedgerRead)).Get("/statements", h.DownloadLedgerStatement)
+		r.With(h.require(PermLedgerRead)).Get("/holds", h.ListLedgerHolds)
+		r.With(h.require(PermLedgerPost)).Post("/holds/{id}/release", h.ReleaseLedgerHold)
+		r.With(h.require(PermLedgerSettle)).Post("/settlements/{date}/transfer", h.TransferLedgerSettlement)
+		r.With(h.require(PermLedgerRead)).Get("/balances", h.GetBalances)
+		r.With(h.require(PermLedgerRead)).Get("/balances/series", h.GetBalanceSeries)
 	})
 
 	// EIP operations via gateway
 	r.Route("/api/v1/eip/cases", func(r chi.Router) {
-		r.Post("/", h.CreateEIPCase)
-		r.Get("/", h.ListEIPCases)
-		r.Get("/{id}", h.GetEIPCase)
-		r.Patch("/{id}/status", h.UpdateEIPCaseStatus)
+		r.With(h.require(PermEIPWork), idempotencyKey).Post("/", h.CreateEIPCase)
+		r.With(h.require(PermEIPRead)).Get("/", h.ListEIPCases)
+		r.With(h.require(PermEIPWork)).Post("/claim", h.ClaimNextEIPCase)
+		r.With(h.require(PermEIPRead)).Get("/sla", h.GetEIPSLASummary)
+		r.With(h.require(PermEIPRead)).Get("/metrics", h.GetEIPCaseMetrics)
+		r.With(h.require(PermEIPRead)).Get("/{id}", h.GetEIPCase)
+		r.With(h.require(PermEIPWork)).Patch("/{id}/status", h.UpdateEIPCaseStatus)
+		r.With(h.require(PermEIPWork)).Post("/{id}/assign", h.AssignEIPCase)
+		r.With(h.require(PermEIPWork)).Post("/{id}/unassign", h.UnassignEIPCase)
+		r.With(h.require(PermEIPWork)).Post("/{id}/merge", h.MergeEIPCases)
+		r.With(h.require(PermEIPWork)).Post("/{id}/comments", h.AddEIPCaseComment)
+		r.With(h.require(PermEIPRead)).Get("/{id}/comments", h.ListEIPCaseComments)
+		r.With(h.require(PermEIPWork)).Post("/{id}/attachments", h.UploadEIPAttachment)
+		r.With(h.require(PermEIPRead)).Get("/{id}/attachments", h.ListEIPAttachments)
+		r.With(h.require(PermEIPRead)).Get("/{id}/attachments/{attachmentID}", h.DownloadEIPAttachment)
+		r.With(h.require(PermEIPWork)).Post("/{id}/dispute/statement", h.RecordEIPDisputeStatement)
+		r.With(h.require(PermEIPDispute)).Post("/{id}/dispute/approve", h.ApproveEIPDispute)
+		r.With(h.require(PermEIPDispute)).Post("/{id}/dispute/deny", h.DenyEIPDispute)
 	})
+	r.With(h.require(PermEIPRead)).Get("/api/v1/eip/resolutions", h.ListEIPResolutionCodes)
+	r.With(h.require(PermEIPRead)).Get("/api/v1/eip/resolutions/stats", h.GetEIPResolutionStats)
+
+	// Any authenticated caller may ask what it is allowed to do
+	r.Get("/api/v1/me/permissions", h.GetMyPermissions)
 
 	r.Get("/healthz", h.Health)
 }
@@ -72,6 +125,11 @@ func (h *Handler) GetAchItems(w http.ResponseWriter, r *http.Request) {
 	side := r.URL.Query().Get("side")
 	status := r.URL.Query().Get("status")
 	traceNumber := r.URL.Query().Get("trace_number")
+	settlementFrom, settlementTo, err := settlementRange(r)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	sortBy := r.URL.Query().Get("sort_by")
 	sortOrder := r.URL.Query().Get("sort_order")
 
@@ -111,19 +169,20 @@ func (h *Handler) GetAchItems(w http.ResponseWriter, r *http.Request) {
 
 	// Validate sort_by if provided
 	validSortFields := map[string]bool{
-		"created_at":   true,
-		"status":       true,
-		"amount":       true,
-		"amount_cents": true,
-		"trace_number": true,
-		"side":         true,
+		"created_at":      true,
+		"status":          true,
+		"amount":          true,
+		"amount_cents":    true,
+		"trace_number":    true,
+		"side":            true,
+		"settlement_date": true,
 	}
 	if sortBy != "" && !validSortFields[sortBy] {
-		commonhttp.Error(w, http.StatusBadRequest, "sort_by must be one of: created_at, status, amount, trace_number, side")
+		commonhttp.Error(w, http.StatusBadRequest, "sort_by must be one of: created_at, status, amount, trace_number, side, settlement_date")
 		return
 	}
 
-	response, err := h.service.GetAchItems(r.Context(), side, status, traceNumber, sortBy, sortOrder, limit, offset)
+	response, err := h.service.GetAchItems(r.Context(), side, status, traceNumber, settlementFrom, settlementTo, sortBy, sortOrder, limit, offset)
 	if err != nil {
 		commonhttp.Error(w, http.StatusInternalServerError, "failed to fetch ACH items")
 		return
@@ -201,7 +260,7 @@ func (h *Handler) CreateODFIEntry(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.service.CreateODFIEntry(r.Context(), &req)
 	if err != nil {
-		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		relayError(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -212,8 +271,13 @@ func (h *Handler) CreateODFIEntry(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) ListODFIEntries(w http.ResponseWriter, r *http.Request) {
 	status := r.URL.Query().Get("status")
 	traceNumber := r.URL.Query().Get("trace_number")
+	settlementFrom, settlementTo, err := settlementRange(r)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	entries, err := h.service.ListODFIEntries(r.Context(), status, traceNumber)
+	entries, err := h.service.ListODFIEntries(r.Context(), status, traceNumber, settlementFrom, settlementTo)
 	if err != nil {
 		commonhttp.Error(w, http.StatusInternalServerError, "failed to list ODFI entries")
 		return
@@ -268,6 +332,118 @@ func (h *Handler) UpdateODFIStatus(w http.ResponseWriter, r *http.Request) {
 	commonhttp.JSON(w, http.StatusOK, entry)
 }
 
+// CreateODFIBatch handles POST /api/v1/odfi/batches
+func (h *Handler) CreateODFIBatch(w http.ResponseWriter, r *http.Request) {
+	var req CreateODFIBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	batch, err := h.service.CreateODFIBatch(r.Context(), &req)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, batch)
+}
+
+// ListODFIBatches handles GET /api/v1/odfi/batches
+func (h *Handler) ListODFIBatches(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	batches, err := h.service.ListODFIBatches(r.Context(), status)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list ODFI batches")
+		return
+	}
+
+	if batches == nil {
+		batches = []*ODFIBatch{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, batches)
+}
+
+// GetODFIBatch handles GET /api/v1/odfi/batches/{id}
+func (h *Handler) GetODFIBatch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	batch, err := h.service.GetODFIBatch(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get ODFI batch")
+		return
+	}
+
+	if batch == nil {
+		commonhttp.Error(w, http.StatusNotFound, "batch not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, batch)
+}
+
+// AddODFIBatchEntry handles POST /api/v1/odfi/batches/{id}/entries
+func (h *Handler) AddODFIBatchEntry(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req CreateODFIEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	entry, err := h.service.AddODFIBatchEntry(r.Context(), id, &req)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if entry == nil {
+		commonhttp.Error(w, http.StatusNotFound, "batch not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, entry)
+}
+
+// CloseODFIBatch handles POST /api/v1/odfi/batches/{id}/close
+func (h *Handler) CloseODFIBatch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	batch, err := h.service.CloseODFIBatch(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if batch == nil {
+		commonhttp.Error(w, http.StatusNotFound, "batch not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, batch)
+}
+
+// CancelODFIBatch handles POST /api/v1/odfi/batches/{id}/cancel
+func (h *Handler) CancelODFIBatch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	batch, err := h.service.CancelODFIBatch(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if batch == nil {
+		commonhttp.Error(w, http.StatusNotFound, "batch not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, batch)
+}
+
 // ========== RDFI Handlers ==========
 
 // CreateRDFIEntry handles POST /api/v1/rdfi/entries
@@ -280,7 +456,7 @@ func (h *Handler) CreateRDFIEntry(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.service.CreateRDFIEntry(r.Context(), &req)
 	if err != nil {
-		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		relayError(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -323,6 +499,24 @@ func (h *Handler) GetRDFIEntry(w http.ResponseWriter, r *http.Request) {
 	commonhttp.JSON(w, http.StatusOK, entry)
 }
 
+// PostRDFIEntry handles POST /api/v1/rdfi/entries/{id}/post
+func (h *Handler) PostRDFIEntry(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	entry, err := h.service.PostRDFIEntry(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if entry == nil {
+		commonhttp.Error(w, http.StatusNotFound, "entry not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, entry)
+}
+
 // ReturnRDFIEntry handles POST /api/v1/rdfi/entries/{id}/return
 func (h *Handler) ReturnRDFIEntry(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -359,7 +553,7 @@ func (h *Handler) CreateLedgerPosting(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.service.CreateLedgerPosting(r.Context(), &req)
 	if err != nil {
-		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		relayError(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -368,114 +562,847 @@ func (h *Handler) CreateLedgerPosting(w http.ResponseWriter, r *http.Request) {
 
 // ListLedgerPostings handles GET /api/v1/ledger/postings
 func (h *Handler) ListLedgerPostings(w http.ResponseWriter, r *http.Request) {
-	achSide := r.URL.Query().Get("ach_side")
-	traceNumber := r.URL.Query().Get("trace_number")
+	q := r.URL.Query()
+	filter := LedgerPostingFilter{
+		AchSide:     q.Get("ach_side"),
+		TraceNumber: q.Get("trace_number"),
+		From:        q.Get("from"),
+		To:          q.Get("to"),
+		Page:        pageQuery(r),
+	}
 
-	entries, err := h.service.ListLedgerPostings(r.Context(), achSide, traceNumber)
+	page, err := h.service.ListLedgerPostings(r.Context(), filter)
 	if err != nil {
-		commonhttp.Error(w, http.StatusInternalServerError, "failed to list ledger postings")
+		if !relayUpstream(w, err) {
+			commonhttp.Error(w, http.StatusInternalServerError, "failed to list ledger postings")
+		}
 		return
 	}
 
-	if entries == nil {
-		entries = []*LedgerEntry{}
+	if page.Data == nil {
+		page.Data = []*LedgerEntry{}
 	}
 
-	commonhttp.JSON(w, http.StatusOK, entries)
+	commonhttp.JSON(w, http.StatusOK, page)
 }
 
-// GetBalances handles GET /api/v1/ledger/balances
-func (h *Handler) GetBalances(w http.ResponseWriter, r *http.Request) {
-	balances, err := h.service.GetBalances(r.Context())
+// ReverseLedgerPosting handles POST /api/v1/ledger/postings/{id}/reverse
+func (h *Handler) ReverseLedgerPosting(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req ReverseLedgerPostingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	journal, err := h.service.ReverseLedgerPosting(r.Context(), id, req.Reason)
 	if err != nil {
-		commonhttp.Error(w, http.StatusInternalServerError, "failed to get balances")
+		relayError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	commonhttp.JSON(w, http.StatusOK, balances)
-}
+	if journal == nil {
+		commonhttp.Error(w, http.StatusNotFound, "posting not found")
+		return
+	}
 
-// ========== EIP Handlers ==========
+	commonhttp.JSON(w, http.StatusCreated, journal)
+}
 
-// CreateEIPCase handles POST /api/v1/eip/cases
-func (h *Handler) CreateEIPCase(w http.ResponseWriter, r *http.Request) {
-	var req CreateEIPCaseRequest
+// CreateLedgerJournalEntry handles POST /api/v1/ledger/journal-entries
+func (h *Handler) CreateLedgerJournalEntry(w http.ResponseWriter, r *http.Request) {
+	var req CreateLedgerJournalEntryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	eipCase, err := h.service.CreateEIPCase(r.Context(), &req)
+	journal, err := h.service.CreateLedgerJournalEntry(r.Context(), &req)
 	if err != nil {
-		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		relayError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	commonhttp.JSON(w, http.StatusCreated, eipCase)
+	commonhttp.JSON(w, http.StatusCreated, journal)
 }
 
-// ListEIPCases handles GET /api/v1/eip/cases
-func (h *Handler) ListEIPCases(w http.ResponseWriter, r *http.Request) {
-	status := r.URL.Query().Get("status")
-	side := r.URL.Query().Get("side")
+// ListLedgerJournalEntries handles GET /api/v1/ledger/journal-entries
+func (h *Handler) ListLedgerJournalEntries(w http.ResponseWriter, r *http.Request) {
+	achSide := r.URL.Query().Get("ach_side")
 	traceNumber := r.URL.Query().Get("trace_number")
 
-	cases, err := h.service.ListEIPCases(r.Context(), status, side, traceNumber)
+	journals, err := h.service.ListLedgerJournalEntries(r.Context(), achSide, traceNumber)
 	if err != nil {
-		commonhttp.Error(w, http.StatusInternalServerError, "failed to list EIP cases")
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list journal entries")
 		return
 	}
 
-	if cases == nil {
-		cases = []*EIPCase{}
+	if journals == nil {
+		journals = []*LedgerJournalEntry{}
 	}
 
-	commonhttp.JSON(w, http.StatusOK, cases)
+	commonhttp.JSON(w, http.StatusOK, journals)
 }
 
-// GetEIPCase handles GET /api/v1/eip/cases/{id}
-func (h *Handler) GetEIPCase(w http.ResponseWriter, r *http.Request) {
+// GetLedgerJournalEntry handles GET /api/v1/ledger/journal-entries/{id}
+func (h *Handler) GetLedgerJournalEntry(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
-	eipCase, err := h.service.GetEIPCase(r.Context(), id)
+	journal, err := h.service.GetLedgerJournalEntry(r.Context(), id)
 	if err != nil {
-		commonhttp.Error(w, http.StatusInternalServerError, "failed to get EIP case")
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get journal entry")
 		return
 	}
 
-	if eipCase == nil {
-		commonhttp.Error(w, http.StatusNotFound, "case not found")
+	if journal == nil {
+		commonhttp.Error(w, http.StatusNotFound, "journal entry not found")
 		return
 	}
 
-	commonhttp.JSON(w, http.StatusOK, eipCase)
+	commonhttp.JSON(w, http.StatusOK, journal)
 }
 
-// UpdateEIPCaseStatus handles PATCH /api/v1/eip/cases/{id}/status
-func (h *Handler) UpdateEIPCaseStatus(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
+// ListLedgerEvents handles GET /api/v1/ledger/events
+func (h *Handler) ListLedgerEvents(w http.ResponseWriter, r *http.Request) {
+	entryID := r.URL.Query().Get("entry_id")
+	traceNumber := r.URL.Query().Get("trace_number")
 
-	var req UpdateEIPCaseStatusRequest
+	processed, err := h.service.ListLedgerEvents(r.Context(), entryID, traceNumber)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list ledger events")
+		return
+	}
+
+	if processed == nil {
+		processed = []*LedgerProcessedEvent{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, processed)
+}
+
+// ListLedgerAccounts handles GET /api/v1/ledger/accounts
+func (h *Handler) ListLedgerAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.service.ListLedgerAccounts(r.Context())
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list ledger accounts")
+		return
+	}
+
+	if accounts == nil {
+		accounts = []*LedgerAccount{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, accounts)
+}
+
+// ListLedgerPeriods handles GET /api/v1/ledger/periods
+func (h *Handler) ListLedgerPeriods(w http.ResponseWriter, r *http.Request) {
+	periods, err := h.service.ListLedgerPeriods(r.Context())
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list ledger periods")
+		return
+	}
+
+	if periods == nil {
+		periods = []*LedgerPeriod{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, periods)
+}
+
+// GetLedgerPeriod handles GET /api/v1/ledger/periods/{date}
+func (h *Handler) GetLedgerPeriod(w http.ResponseWriter, r *http.Request) {
+	date := chi.URLParam(r, "date")
+
+	period, err := h.service.GetLedgerPeriod(r.Context(), date)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get ledger period")
+		return
+	}
+
+	if period == nil {
+		commonhttp.Error(w, http.StatusNotFound, "period not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, period)
+}
+
+// CloseLedgerPeriod handles POST /api/v1/ledger/periods/{date}/close
+func (h *Handler) CloseLedgerPeriod(w http.ResponseWriter, r *http.Request) {
+	date := chi.URLParam(r, "date")
+
+	period, err := h.service.CloseLedgerPeriod(r.Context(), date)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, period)
+}
+
+// ReopenLedgerPeriod handles POST /api/v1/ledger/periods/{date}/reopen
+func (h *Handler) ReopenLedgerPeriod(w http.ResponseWriter, r *http.Request) {
+	date := chi.URLParam(r, "date")
+
+	var req ReopenLedgerPeriodRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	eipCase, err := h.service.UpdateEIPCaseStatus(r.Context(), id, req.Status)
+	period, err := h.service.ReopenLedgerPeriod(r.Context(), date, req.Reason)
 	if err != nil {
-		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		relayError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	if eipCase == nil {
-		commonhttp.Error(w, http.StatusNotFound, "case not found")
+	commonhttp.JSON(w, http.StatusOK, period)
+}
+
+// GetLedgerSettlement handles GET /api/v1/ledger/settlements
+func (h *Handler) GetLedgerSettlement(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	settlement, err := h.service.GetLedgerSettlement(r.Context(), q.Get("date"), q.Get("ach_side"))
+	if err != nil {
+		if !relayUpstream(w, err) {
+			commonhttp.Error(w, http.StatusInternalServerError, "failed to get settlement")
+		}
 		return
 	}
 
-	commonhttp.JSON(w, http.StatusOK, eipCase)
+	commonhttp.JSON(w, http.StatusOK, settlement)
 }
 
-// Health handles GET /healthz
-func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
-	commonhttp.Health(w)
+// TransferLedgerSettlement handles POST /api/v1/ledger/settlements/{date}/transfer
+func (h *Handler) TransferLedgerSettlement(w http.ResponseWriter, r *http.Request) {
+	date := chi.URLParam(r, "date")
+
+	settlement, err := h.service.TransferLedgerSettlement(r.Context(), date)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, settlement)
+}
+
+// DownloadLedgerStatement handles GET /api/v1/ledger/statements
+func (h *Handler) DownloadLedgerStatement(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	file, err := h.service.DownloadLedgerStatement(r.Context(), q.Get("account"), q.Get("from"), q.Get("to"), q.Get("format"))
+	if err != nil {
+		if !relayUpstream(w, err) {
+			commonhttp.Error(w, http.StatusInternalServerError, "failed to download statement")
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", file.ContentType)
+	if file.ContentDisposition != "" {
+		w.Header().Set("Content-Disposition", file.ContentDisposition)
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(file.Body)
+}
+
+// ListLedgerHolds handles GET /api/v1/ledger/holds
+func (h *Handler) ListLedgerHolds(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	holds, err := h.service.ListLedgerHolds(r.Context(), q.Get("account"), q.Get("status"), q.Get("entry_id"), q.Get("trace_number"))
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list ledger holds")
+		return
+	}
+
+	if holds == nil {
+		holds = []*LedgerHold{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, holds)
+}
+
+// ReleaseLedgerHold handles POST /api/v1/ledger/holds/{id}/release
+func (h *Handler) ReleaseLedgerHold(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	hold, err := h.service.ReleaseLedgerHold(r.Context(), id)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if hold == nil {
+		commonhttp.Error(w, http.StatusNotFound, "hold not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, hold)
+}
+
+// GetBalances handles GET /api/v1/ledger/balances
+func (h *Handler) GetBalances(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	balances, err := h.service.GetBalances(r.Context(), q.Get("ach_side"), q.Get("account"), q.Get("trace_number"), q.Get("as_of"))
+	if err != nil {
+		if !relayUpstream(w, err) {
+			commonhttp.Error(w, http.StatusInternalServerError, "failed to get balances")
+		}
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, balances)
+}
+
+// GetBalanceSeries handles GET /api/v1/ledger/balances/series
+func (h *Handler) GetBalanceSeries(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	series, err := h.service.GetBalanceSeries(r.Context(), q.Get("ach_side"), q.Get("account"), q.Get("trace_number"),
+		q.Get("interval"), q.Get("from"), q.Get("to"))
+	if err != nil {
+		if !relayUpstream(w, err) {
+			commonhttp.Error(w, http.StatusInternalServerError, "failed to get balance series")
+		}
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, series)
+}
+
+// ========== EIP Handlers ==========
+
+// CreateEIPCase handles POST /api/v1/eip/cases
+func (h *Handler) CreateEIPCase(w http.ResponseWriter, r *http.Request) {
+	var req CreateEIPCaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, created, err := h.service.CreateEIPCase(r.Context(), &req)
+	if errors.Is(err, ErrLinkedEntryUnavailable) {
+		commonhttp.Error(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !created {
+		commonhttp.JSON(w, http.StatusOK, eipCase)
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, eipCase)
+}
+
+// ListEIPCases handles GET /api/v1/eip/cases
+func (h *Handler) ListEIPCases(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := EIPCaseFilter{
+		Status:      q.Get("status"),
+		Side:        q.Get("side"),
+		EntryID:     q.Get("entry_id"),
+		TraceNumber: q.Get("trace_number"),
+		Assignee:    q.Get("assignee"),
+		Queue:       q.Get("queue"),
+		Overdue:     q.Get("overdue"),
+		From:        q.Get("from"),
+		To:          q.Get("to"),
+		Page:        pageQuery(r),
+	}
+
+	page, err := h.service.ListEIPCases(r.Context(), filter)
+	if err != nil {
+		if !relayUpstream(w, err) {
+			commonhttp.Error(w, http.StatusInternalServerError, "failed to list EIP cases")
+		}
+		return
+	}
+
+	if page.Data == nil {
+		page.Data = []*EIPCase{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, page)
+}
+
+// GetEIPCase handles GET /api/v1/eip/cases/{id}
+func (h *Handler) GetEIPCase(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	eipCase, err := h.service.GetEIPCase(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get EIP case")
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// UpdateEIPCaseStatus handles PATCH /api/v1/eip/cases/{id}/status
+func (h *Handler) UpdateEIPCaseStatus(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req UpdateEIPCaseStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.UpdateEIPCaseStatus(r.Context(), id, &req)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// GetEIPSLASummary handles GET /api/v1/eip/cases/sla
+func (h *Handler) GetEIPSLASummary(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.service.GetEIPSLASummary(r.Context())
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get EIP SLA summary")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, summary)
+}
+
+// ListEIPResolutionCodes handles GET /api/v1/eip/resolutions
+func (h *Handler) ListEIPResolutionCodes(w http.ResponseWriter, r *http.Request) {
+	codes, err := h.service.ListEIPResolutionCodes(r.Context())
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list EIP resolution codes")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, codes)
+}
+
+// GetEIPResolutionStats handles GET /api/v1/eip/resolutions/stats
+func (h *Handler) GetEIPResolutionStats(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	stats, err := h.service.GetEIPResolutionStats(r.Context(), q.Get("interval"), q.Get("side"), q.Get("from"), q.Get("to"))
+	if err != nil {
+		if !relayUpstream(w, err) {
+			commonhttp.Error(w, http.StatusInternalServerError, "failed to get EIP resolution stats")
+		}
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, stats)
+}
+
+// GetEIPCaseMetrics handles GET /api/v1/eip/cases/metrics
+func (h *Handler) GetEIPCaseMetrics(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	metrics, err := h.service.GetEIPCaseMetrics(r.Context(), q.Get("side"), q.Get("type"), q.Get("from"), q.Get("to"))
+	if err != nil {
+		if !relayUpstream(w, err) {
+			commonhttp.Error(w, http.StatusInternalServerError, "failed to get EIP case metrics")
+		}
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, metrics)
+}
+
+// AssignEIPCase handles POST /api/v1/eip/cases/{id}/assign
+func (h *Handler) AssignEIPCase(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req AssignEIPCaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.AssignEIPCase(r.Context(), id, req.Assignee)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// UnassignEIPCase handles POST /api/v1/eip/cases/{id}/unassign
+func (h *Handler) UnassignEIPCase(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	eipCase, err := h.service.UnassignEIPCase(r.Context(), id)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// MergeEIPCases handles POST /api/v1/eip/cases/{id}/merge
+func (h *Handler) MergeEIPCases(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req MergeEIPCasesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.MergeEIPCases(r.Context(), id, &req)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// ClaimNextEIPCase handles POST /api/v1/eip/cases/claim
+func (h *Handler) ClaimNextEIPCase(w http.ResponseWriter, r *http.Request) {
+	var req ClaimEIPCaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.ClaimNextEIPCase(r.Context(), &req)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "no unassigned open cases in queue")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// RecordEIPDisputeStatement handles POST /api/v1/eip/cases/{id}/dispute/statement
+func (h *Handler) RecordEIPDisputeStatement(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req EIPDisputeStatementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.RecordEIPDisputeStatement(r.Context(), id, &req)
+	if err != nil {
+		relayError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// ApproveEIPDispute handles POST /api/v1/eip/cases/{id}/dispute/approve
+func (h *Handler) ApproveEIPDispute(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req ApproveEIPDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.ApproveEIPDispute(r.Context(), id, &req)
+	if err != nil {
+		relayError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// DenyEIPDispute handles POST /api/v1/eip/cases/{id}/dispute/deny
+func (h *Handler) DenyEIPDispute(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req DenyEIPDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.DenyEIPDispute(r.Context(), id, &req)
+	if err != nil {
+		relayError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// AddEIPCaseComment handles POST /api/v1/eip/cases/{id}/comments
+func (h *Handler) AddEIPCaseComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req CreateEIPCaseCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	comment, err := h.service.AddEIPCaseComment(r.Context(), id, &req)
+	if err != nil {
+		relayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if comment == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, comment)
+}
+
+// ListEIPCaseComments handles GET /api/v1/eip/cases/{id}/comments
+func (h *Handler) ListEIPCaseComments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	comments, err := h.service.ListEIPCaseComments(r.Context(), id, r.URL.Query().Get("visibility"))
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list EIP case comments")
+		return
+	}
+
+	if comments == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, comments)
+}
+
+// UploadEIPAttachment handles POST /api/v1/eip/cases/{id}/attachments, streaming the
+// multipart body to the EIP service without buffering it
+func (h *Handler) UploadEIPAttachment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	attachment, err := h.service.UploadEIPAttachment(r.Context(), id, r.Header.Get("Content-Type"), r.Body)
+	if err != nil {
+		if !relayUpstream(w, err) {
+			commonhttp.Error(w, http.StatusInternalServerError, "failed to upload EIP attachment")
+		}
+		return
+	}
+
+	if attachment == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, attachment)
+}
+
+// ListEIPAttachments handles GET /api/v1/eip/cases/{id}/attachments
+func (h *Handler) ListEIPAttachments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	attachments, err := h.service.ListEIPAttachments(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list EIP attachments")
+		return
+	}
+
+	if attachments == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, attachments)
+}
+
+// DownloadEIPAttachment handles GET /api/v1/eip/cases/{id}/attachments/{attachmentID},
+// streaming the file from the EIP service
+func (h *Handler) DownloadEIPAttachment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	attachmentID := chi.URLParam(r, "attachmentID")
+
+	download, err := h.service.OpenEIPAttachment(r.Context(), id, attachmentID)
+	if err != nil {
+		if !relayUpstream(w, err) {
+			commonhttp.Error(w, http.StatusInternalServerError, "failed to download EIP attachment")
+		}
+		return
+	}
+
+	if download == nil {
+		commonhttp.Error(w, http.StatusNotFound, "attachment not found")
+		return
+	}
+	defer download.Body.Close()
+
+	w.Header().Set("Content-Type", download.ContentType)
+	if download.ContentLength != "" {
+		w.Header().Set("Content-Length", download.ContentLength)
+	}
+	if download.ContentDisposition != "" {
+		w.Header().Set("Content-Disposition", download.ContentDisposition)
+	}
+	if download.SHA256 != "" {
+		w.Header().Set("X-Content-SHA256", download.SHA256)
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, download.Body)
+}
+
+// settlementRange reads settlement_date (exact) or settlement_date_from/settlement_date_to
+func settlementRange(r *http.Request) (string, string, error) {
+	from := r.URL.Query().Get("settlement_date_from")
+	to := r.URL.Query().Get("settlement_date_to")
+	if date := r.URL.Query().Get("settlement_date"); date != "" {
+		from, to = date, date
+	}
+
+	for _, d := range []string{from, to} {
+		if d == "" {
+			continue
+		}
+		if _, err := calendar.ParseDate(d); err != nil {
+			return "", "", errors.New("settlement dates must be YYYY-MM-DD")
+		}
+	}
+
+	return from, to, nil
+}
+
+// pageQuery reads the paging parameters a list endpoint passes through to its service
+func pageQuery(r *http.Request) PageQuery {
+	q := r.URL.Query()
+	return PageQuery{
+		Limit:        q.Get("limit"),
+		Cursor:       q.Get("cursor"),
+		SortBy:       q.Get("sort_by"),
+		SortOrder:    q.Get("sort_order"),
+		IncludeTotal: q.Get("include_total"),
+	}
+}
+
+// GetMyPermissions handles GET /api/v1/me/permissions. When the console runs without a
+// policy every permission is listed, since every route is open.
+func (h *Handler) GetMyPermissions(w http.ResponseWriter, r *http.Request) {
+	claims := auth.ClaimsFromContext(r.Context())
+	mine := MyPermissions{
+		Subject:       auth.Subject(r.Context()),
+		Authenticated: claims != nil,
+		Roles:         []string{},
+		Permissions:   []string{},
+	}
+
+	if h.policy == nil {
+		mine.Permissions = append(mine.Permissions, Permissions...)
+		commonhttp.JSON(w, http.StatusOK, mine)
+		return
+	}
+
+	mine.Roles = h.policy.RolesOf(claims)
+	for _, permission := range Permissions {
+		if h.policy.Allows(claims, permission) {
+			mine.Permissions = append(mine.Permissions, permission)
+		}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, mine)
+}
+
+// Health handles GET /healthz
+func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
+	commonhttp.Health(w)
+}
+
+// idempotencyKey forwards the caller's Idempotency-Key to the upstream service, or
+// generates one so that the gateway's own retries are safe. The key is echoed in the
+// response so the caller can retry with it.
+func idempotencyKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotency.HeaderKey)
+		if key == "" {
+			key = uuid.New().String()
+		}
+		w.Header().Set(idempotency.HeaderKey, key)
+
+		next.ServeHTTP(w, r.WithContext(WithIdempotencyKey(r.Context(), key)))
+	})
+}
+
+// relayError passes an upstream 4xx response through unchanged so that structured
+// field errors reach the caller; anything else is reported with the given status
+func relayError(w http.ResponseWriter, status int, err error) {
+	if relayUpstream(w, err) {
+		return
+	}
+
+	commonhttp.Error(w, status, err.Error())
+}
+
+// relayUpstream writes err's upstream response if it is a 4xx with a JSON body and
+// reports whether it did
+func relayUpstream(w http.ResponseWriter, err error) bool {
+	var upstream *UpstreamError
+	if !errors.As(err, &upstream) || upstream.StatusCode < 400 || upstream.StatusCode >= 500 || !json.Valid(upstream.Body) {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(upstream.StatusCode)
+	w.Write(upstream.Body)
+	return true
 }
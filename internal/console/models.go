@@ -1,15 +1,21 @@
 package console
 
+import "io"
+
 // UnifiedAchItem represents a unified view of ACH entries from ODFI/RDFI
 type UnifiedAchItem struct {
-	Side        string `json:"side"`             // "ODFI" or "RDFI"
-	Source      string `json:"source"`           // "odfi", "rdfi"
-	EntryID     string `json:"entry_id"`
-	TraceNumber string `json:"trace_number"`
-	AmountCents int64  `json:"amount_cents"`
-	Status      string `json:"status"`
-	CreatedAt   string `json:"created_at"`       // For sorting
-	Extra       any    `json:"extra,omitempty"`  // Optional service-specific fields
+	Side           string `json:"side"`   // "ODFI" or "RDFI"
+	Source         string `json:"source"` // "odfi", "rdfi"
+	EntryID        string `json:"entry_id"`
+	TraceNumber    string `json:"trace_number"`
+	AmountCents    int64  `json:"amount_cents"`
+	Status         string `json:"status"`
+	CreatedAt      string `json:"created_at"`                // For sorting
+	SettlementDate string `json:"settlement_date,omitempty"` // ODFI only (YYYY-MM-DD)
+	Extra          any    `json:"extra,omitempty"`           // Optional service-specific fields
+
+	// OpenCases lists the unresolved EIP cases linked to the entry; single-item lookups only
+	OpenCases []*EIPCase `json:"open_cases,omitempty"`
 }
 
 // ServiceHealth represents the health status of an upstream service
@@ -30,22 +36,76 @@ type UnifiedAchResponse struct {
 
 // ODFIEntry represents an ODFI entry from the ODFI service
 type ODFIEntry struct {
-	ID          string `json:"id"`
-	TraceNumber string `json:"trace_number"`
-	CompanyName string `json:"company_name"`
-	SecCode     string `json:"sec_code"`
-	AmountCents int64  `json:"amount_cents"`
-	Status      string `json:"status"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID                 string        `json:"id"`
+	BatchID            string        `json:"batch_id,omitempty"`
+	TraceNumber        string        `json:"trace_number"`
+	CompanyName        string        `json:"company_name"`
+	SecCode            string        `json:"sec_code"`
+	AmountCents        int64         `json:"amount_cents"`
+	RoutingNumber      string        `json:"routing_number"`
+	AccountNumber      string        `json:"account_number"` // Masked by the ODFI service
+	AccountType        string        `json:"account_type"`
+	TransactionCode    string        `json:"transaction_code"`
+	Direction          string        `json:"direction"`
+	ReceiverName       string        `json:"receiver_name,omitempty"`
+	ReceiverID         string        `json:"receiver_id,omitempty"`
+	AuthorizationType  string        `json:"authorization_type,omitempty"`
+	EffectiveEntryDate string        `json:"effective_entry_date,omitempty"`
+	SettlementDate     string        `json:"settlement_date,omitempty"`
+	Status             string        `json:"status"`
+	CreatedAt          string        `json:"created_at"`
+	UpdatedAt          string        `json:"updated_at"`
+	Addenda            []ODFIAddenda `json:"addenda,omitempty"`
+}
+
+// ODFIAddenda represents a payment related (type 05) addenda record on an ODFI entry
+type ODFIAddenda struct {
+	SequenceNumber            int    `json:"sequence_number"`
+	TypeCode                  string `json:"type_code"`
+	PaymentRelatedInformation string `json:"payment_related_information"`
 }
 
 // CreateODFIEntryRequest represents request to create ODFI entry
 type CreateODFIEntryRequest struct {
-	TraceNumber string `json:"trace_number"`
-	CompanyName string `json:"company_name"`
-	SecCode     string `json:"sec_code"`
-	AmountCents int64  `json:"amount_cents"`
+	TraceNumber        string   `json:"trace_number"`
+	CompanyName        string   `json:"company_name"`
+	SecCode            string   `json:"sec_code"`
+	AmountCents        int64    `json:"amount_cents"`
+	RoutingNumber      string   `json:"routing_number"`
+	AccountNumber      string   `json:"account_number"`
+	AccountType        string   `json:"account_type,omitempty"`
+	TransactionCode    string   `json:"transaction_code"`
+	EffectiveEntryDate string   `json:"effective_entry_date,omitempty"`
+	ReceiverName       string   `json:"receiver_name,omitempty"`
+	ReceiverID         string   `json:"receiver_id,omitempty"`
+	AuthorizationType  string   `json:"authorization_type,omitempty"`
+	Addenda            []string `json:"addenda,omitempty"`
+}
+
+// ODFIBatch represents an ODFI batch with its summed totals
+type ODFIBatch struct {
+	ID                      string       `json:"id"`
+	CompanyName             string       `json:"company_name"`
+	CompanyID               string       `json:"company_id"`
+	CompanyEntryDescription string       `json:"company_entry_description"`
+	SecCode                 string       `json:"sec_code"`
+	EffectiveEntryDate      string       `json:"effective_entry_date"`
+	Status                  string       `json:"status"`
+	EntryCount              int64        `json:"entry_count"`
+	TotalDebitCents         int64        `json:"total_debit_cents"`
+	TotalCreditCents        int64        `json:"total_credit_cents"`
+	CreatedAt               string       `json:"created_at"`
+	UpdatedAt               string       `json:"updated_at"`
+	Entries                 []*ODFIEntry `json:"entries,omitempty"`
+}
+
+// CreateODFIBatchRequest represents request to open an ODFI batch
+type CreateODFIBatchRequest struct {
+	CompanyName             string `json:"company_name"`
+	CompanyID               string `json:"company_id"`
+	CompanyEntryDescription string `json:"company_entry_description"`
+	SecCode                 string `json:"sec_code"`
+	EffectiveEntryDate      string `json:"effective_entry_date,omitempty"`
 }
 
 // UpdateODFIStatusRequest represents request to update ODFI status
@@ -55,21 +115,32 @@ type UpdateODFIStatusRequest struct {
 
 // RDFIEntry represents an RDFI entry from the RDFI service
 type RDFIEntry struct {
-	ID           string `json:"id"`
-	TraceNumber  string `json:"trace_number"`
-	ReceiverName string `json:"receiver_name"`
-	AmountCents  int64  `json:"amount_cents"`
-	Status       string `json:"status"`
-	ReturnReason string `json:"return_reason,omitempty"`
-	CreatedAt    string `json:"created_at"`
-	UpdatedAt    string `json:"updated_at"`
+	ID              string `json:"id"`
+	TraceNumber     string `json:"trace_number"`
+	ReceiverName    string `json:"receiver_name"`
+	AmountCents     int64  `json:"amount_cents"`
+	RoutingNumber   string `json:"routing_number"`
+	AccountNumber   string `json:"account_number"` // Masked by the RDFI service
+	AccountType     string `json:"account_type"`
+	TransactionCode string `json:"transaction_code"`
+	Direction       string `json:"direction"`
+	Status          string `json:"status"`
+	ReturnReason    string `json:"return_reason,omitempty"`
+	SettlementDate  string `json:"settlement_date,omitempty"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
 }
 
 // CreateRDFIEntryRequest represents request to create RDFI entry
 type CreateRDFIEntryRequest struct {
-	TraceNumber  string `json:"trace_number"`
-	ReceiverName string `json:"receiver_name"`
-	AmountCents  int64  `json:"amount_cents"`
+	TraceNumber     string `json:"trace_number"`
+	ReceiverName    string `json:"receiver_name"`
+	AmountCents     int64  `json:"amount_cents"`
+	RoutingNumber   string `json:"routing_number"`
+	AccountNumber   string `json:"account_number"`
+	AccountType     string `json:"account_type,omitempty"`
+	TransactionCode string `json:"transaction_code"`
+	SettlementDate  string `json:"settlement_date,omitempty"`
 }
 
 // ReturnRequest represents a request to return an entry
@@ -77,15 +148,27 @@ type ReturnRequest struct {
 	Reason string `json:"reason"`
 }
 
-// LedgerEntry represents a ledger posting
+// LedgerEntry represents a ledger posting (one leg of a journal entry)
 type LedgerEntry struct {
-	ID          string `json:"id"`
-	AchSide     string `json:"ach_side"`
-	TraceNumber string `json:"trace_number"`
-	AmountCents int64  `json:"amount_cents"`
-	Direction   string `json:"direction"`
-	Description string `json:"description"`
-	CreatedAt   string `json:"created_at"`
+	ID             string `json:"id"`
+	JournalEntryID string `json:"journal_entry_id"`
+	AccountCode    string `json:"account_code"`
+	AchSide        string `json:"ach_side"`
+	TraceNumber    string `json:"trace_number"`
+	AmountCents    int64  `json:"amount_cents"`
+	Direction      string `json:"direction"`
+	Description    string `json:"description"`
+	EffectiveDate  string `json:"effective_date"`
+	CreatedAt      string `json:"created_at"`
+
+	ReversesJournalEntryID   string `json:"reverses_journal_entry_id,omitempty"`
+	ReversalReason           string `json:"reversal_reason,omitempty"`
+	ReversedByJournalEntryID string `json:"reversed_by_journal_entry_id,omitempty"`
+}
+
+// ReverseLedgerPostingRequest represents request to reverse a ledger posting
+type ReverseLedgerPostingRequest struct {
+	Reason string `json:"reason"`
 }
 
 // CreateLedgerPostingRequest represents request to create ledger posting
@@ -95,37 +178,457 @@ type CreateLedgerPostingRequest struct {
 	AmountCents int64  `json:"amount_cents"`
 	Direction   string `json:"direction"`
 	Description string `json:"description"`
+
+	EffectiveDate string `json:"effective_date,omitempty"`
+}
+
+// LedgerJournalEntry represents a balanced journal entry with its legs and reversal links
+type LedgerJournalEntry struct {
+	ID                       string         `json:"id"`
+	AchSide                  string         `json:"ach_side"`
+	TraceNumber              string         `json:"trace_number"`
+	Description              string         `json:"description"`
+	EffectiveDate            string         `json:"effective_date"`
+	SettlementDate           string         `json:"settlement_date,omitempty"`
+	ReversesJournalEntryID   string         `json:"reverses_journal_entry_id,omitempty"`
+	ReversalReason           string         `json:"reversal_reason,omitempty"`
+	ReversedByJournalEntryID string         `json:"reversed_by_journal_entry_id,omitempty"`
+	CreatedAt                string         `json:"created_at"`
+	Legs                     []*LedgerEntry `json:"legs"`
+}
+
+// CreateLedgerJournalEntryRequest represents request to post a journal entry
+type CreateLedgerJournalEntryRequest struct {
+	AchSide     string           `json:"ach_side"`
+	TraceNumber string           `json:"trace_number"`
+	Description string           `json:"description"`
+	Legs        []LedgerLegInput `json:"legs"`
+
+	EffectiveDate  string `json:"effective_date,omitempty"`
+	SettlementDate string `json:"settlement_date,omitempty"`
+}
+
+// LedgerLegInput is one leg of a journal entry request
+type LedgerLegInput struct {
+	AccountCode string `json:"account_code"`
+	Direction   string `json:"direction"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+// LedgerAccount represents an account in the ledger's chart of accounts
+type LedgerAccount struct {
+	Code          string `json:"code"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	NormalBalance string `json:"normal_balance"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// LedgerProcessedEvent records how the ledger handled an ODFI or RDFI entry status change
+type LedgerProcessedEvent struct {
+	EventID        string `json:"event_id"`
+	Side           string `json:"side"`
+	EntryID        string `json:"entry_id"`
+	TraceNumber    string `json:"trace_number"`
+	Status         string `json:"status"`
+	Action         string `json:"action"`
+	JournalEntryID string `json:"journal_entry_id,omitempty"`
+	ProcessedAt    string `json:"processed_at"`
 }
 
 // BalanceResponse represents balance calculation
 type BalanceResponse struct {
-	TotalDebits  int64 `json:"total_debits"`
-	TotalCredits int64 `json:"total_credits"`
-	NetBalance   int64 `json:"net_balance"`
+	TotalDebits  int64                  `json:"total_debits"`
+	TotalCredits int64                  `json:"total_credits"`
+	NetBalance   int64                  `json:"net_balance"`
+	Accounts     []LedgerAccountBalance `json:"accounts"`
+	Holds        []*LedgerHold          `json:"holds"`
+	SnapshotDate string                 `json:"snapshot_date,omitempty"`
+}
+
+// LedgerAccountBalance is the balance of one ledger account
+type LedgerAccountBalance struct {
+	AccountCode      string `json:"account_code"`
+	AccountName      string `json:"account_name"`
+	NormalBalance    string `json:"normal_balance"`
+	TotalDebits      int64  `json:"total_debits"`
+	TotalCredits     int64  `json:"total_credits"`
+	Balance          int64  `json:"balance"`
+	LedgerBalance    int64  `json:"ledger_balance"`
+	HeldCents        int64  `json:"held_cents"`
+	AvailableBalance int64  `json:"available_balance"`
+}
+
+// LedgerHold represents an amount held against a ledger account for a pending entry
+type LedgerHold struct {
+	ID             string `json:"id"`
+	AccountCode    string `json:"account_code"`
+	AchSide        string `json:"ach_side"`
+	EntryID        string `json:"entry_id"`
+	TraceNumber    string `json:"trace_number"`
+	AmountCents    int64  `json:"amount_cents"`
+	Status         string `json:"status"`
+	JournalEntryID string `json:"journal_entry_id,omitempty"`
+	ExpiresAt      string `json:"expires_at"`
+	CreatedAt      string `json:"created_at"`
+	ResolvedAt     string `json:"resolved_at,omitempty"`
+}
+
+// LedgerPeriod represents a closed or reopened accounting day
+type LedgerPeriod struct {
+	Date         string                   `json:"date"`
+	Status       string                   `json:"status"`
+	ClosedAt     string                   `json:"closed_at,omitempty"`
+	ReopenedAt   string                   `json:"reopened_at,omitempty"`
+	ReopenReason string                   `json:"reopen_reason,omitempty"`
+	Balances     []LedgerAccountBalance   `json:"balances,omitempty"`
+	History      []LedgerPeriodAuditEntry `json:"history,omitempty"`
+}
+
+// LedgerPeriodAuditEntry records one close or reopen of a period
+type LedgerPeriodAuditEntry struct {
+	Action     string `json:"action"`
+	Reason     string `json:"reason,omitempty"`
+	OccurredAt string `json:"occurred_at"`
+}
+
+// ReopenLedgerPeriodRequest represents request to reopen a closed period
+type ReopenLedgerPeriodRequest struct {
+	Reason string `json:"reason"`
+}
+
+// LedgerSettlement represents the net settlement position per side for a settlement date
+type LedgerSettlement struct {
+	SettlementDate string                     `json:"settlement_date"`
+	Positions      []LedgerSettlementPosition `json:"positions"`
+	Transfers      []*LedgerJournalEntry      `json:"transfers,omitempty"`
+}
+
+// LedgerSettlementPosition is one side's gross and net position against the ACH operator
+type LedgerSettlementPosition struct {
+	AchSide           string `json:"ach_side"`
+	GrossDebits       int64  `json:"gross_debits"`
+	GrossCredits      int64  `json:"gross_credits"`
+	NetPosition       int64  `json:"net_position"`
+	JournalEntryCount int64  `json:"journal_entry_count"`
+	TransferredCents  int64  `json:"transferred_cents"`
+	OutstandingCents  int64  `json:"outstanding_cents"`
+	LastTransferAt    string `json:"last_transfer_at,omitempty"`
+}
+
+// LedgerStatementFile is a rendered statement download relayed from the ledger
+type LedgerStatementFile struct {
+	ContentType        string
+	ContentDisposition string
+	Body               []byte
+}
+
+// BalanceSeriesResponse represents per-account running balances by period
+type BalanceSeriesResponse struct {
+	Interval string               `json:"interval"`
+	From     string               `json:"from"`
+	To       string               `json:"to"`
+	Points   []LedgerBalancePoint `json:"points"`
+}
+
+// LedgerBalancePoint is one account's activity in a period and its closing balance
+type LedgerBalancePoint struct {
+	PeriodStart   string `json:"period_start"`
+	AccountCode   string `json:"account_code"`
+	PeriodDebits  int64  `json:"period_debits"`
+	PeriodCredits int64  `json:"period_credits"`
+	Balance       int64  `json:"balance"`
 }
 
 // EIPCase represents an exception case
 type EIPCase struct {
 	ID          string `json:"id"`
 	Side        string `json:"side"`
+	EntryID     string `json:"entry_id,omitempty"`
 	TraceNumber string `json:"trace_number"`
 	Status      string `json:"status"`
 	Type        string `json:"type"`
 	Notes       string `json:"notes"`
+	Queue       string `json:"queue"`
+	Assignee    string `json:"assignee,omitempty"`
+	AssignedAt  string `json:"assigned_at,omitempty"`
+	Priority    string `json:"priority"`
+	DueDate     string `json:"due_date,omitempty"`
+	AtRiskDate  string `json:"at_risk_date,omitempty"`
+	SLAStatus   string `json:"sla_status"`
+	EscalatedAt string `json:"escalated_at,omitempty"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
+
+	LatestComment *EIPCaseComment `json:"latest_comment,omitempty"`
+
+	// Entry is the linked ODFI or RDFI entry, embedded in case detail. EntryError explains
+	// why it is missing when the owning service could not produce it.
+	Entry      *UnifiedAchItem `json:"entry,omitempty"`
+	EntryError string          `json:"entry_error,omitempty"`
+
+	ResolutionCode    string `json:"resolution_code,omitempty"`
+	ResolutionSummary string `json:"resolution_summary,omitempty"`
+	ResolvedBy        string `json:"resolved_by,omitempty"`
+	ResolvedAt        string `json:"resolved_at,omitempty"`
+
+	MergedInto string      `json:"merged_into,omitempty"`
+	Dispute    *EIPDispute `json:"dispute,omitempty"`
+}
+
+// EIPDispute tracks a customer dispute case through the unauthorized-debit workflow
+type EIPDispute struct {
+	State               string `json:"state"`
+	CustomerName        string `json:"customer_name"`
+	Statement           string `json:"statement"`
+	SignedOn            string `json:"signed_on"`
+	StatementReceivedAt string `json:"statement_received_at,omitempty"`
+	EntryDate           string `json:"entry_date"`
+	WindowEndsOn        string `json:"window_ends_on"`
+	ReturnReason        string `json:"return_reason,omitempty"`
+	ReturnedAt          string `json:"returned_at,omitempty"`
+	LastError           string `json:"last_error,omitempty"`
+}
+
+// EIPDisputeStatementRequest represents request to record a customer's written statement
+// of unauthorized debit
+type EIPDisputeStatementRequest struct {
+	CustomerName string `json:"customer_name"`
+	Statement    string `json:"statement"`
+	SignedOn     string `json:"signed_on"`
+	ReceivedBy   string `json:"received_by"`
+}
+
+// ApproveEIPDisputeRequest represents request to approve a dispute and return the entry
+type ApproveEIPDisputeRequest struct {
+	ReturnReason string `json:"return_reason,omitempty"`
+	ApprovedBy   string `json:"approved_by"`
+}
+
+// DenyEIPDisputeRequest represents request to deny a dispute
+type DenyEIPDisputeRequest struct {
+	Summary  string `json:"summary"`
+	DeniedBy string `json:"denied_by"`
+}
+
+// EIPCaseComment represents a comment on an EIP case
+type EIPCaseComment struct {
+	ID         string `json:"id"`
+	CaseID     string `json:"case_id"`
+	Author     string `json:"author"`
+	Body       string `json:"body"`
+	Visibility string `json:"visibility"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// EIPAttachment describes an evidence file stored against an EIP case
+type EIPAttachment struct {
+	ID          string `json:"id"`
+	CaseID      string `json:"case_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+	SHA256      string `json:"sha256"`
+	UploadedBy  string `json:"uploaded_by,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// EIPAttachmentDownload is an attachment being streamed from the EIP service; the caller
+// closes Body
+type EIPAttachmentDownload struct {
+	ContentType        string
+	ContentLength      string
+	ContentDisposition string
+	SHA256             string
+	Body               io.ReadCloser
+}
+
+// CreateEIPCaseCommentRequest represents request to comment on an EIP case
+type CreateEIPCaseCommentRequest struct {
+	Author     string `json:"author"`
+	Body       string `json:"body"`
+	Visibility string `json:"visibility,omitempty"`
 }
 
 // CreateEIPCaseRequest represents request to create EIP case
 type CreateEIPCaseRequest struct {
 	Side        string `json:"side"`
+	EntryID     string `json:"entry_id,omitempty"`
 	TraceNumber string `json:"trace_number"`
 	Type        string `json:"type"`
 	Notes       string `json:"notes"`
+	Queue       string `json:"queue,omitempty"`
+	Assignee    string `json:"assignee,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	OnDuplicate string `json:"on_duplicate,omitempty"`
+}
+
+// MergeEIPCasesRequest represents request to merge duplicate cases into a surviving case
+type MergeEIPCasesRequest struct {
+	CaseIDs  []string `json:"case_ids"`
+	MergedBy string   `json:"merged_by"`
 }
 
-// UpdateEIPCaseStatusRequest represents request to update case status
+// UpdateEIPCaseStatusRequest represents request to update case status; RESOLVED requires
+// a resolution code and summary
 type UpdateEIPCaseStatusRequest struct {
+	Status            string `json:"status"`
+	ResolutionCode    string `json:"resolution_code,omitempty"`
+	ResolutionSummary string `json:"resolution_summary,omitempty"`
+	ResolvedBy        string `json:"resolved_by,omitempty"`
+}
+
+// EIPResolutionCode is an entry in the EIP resolution catalog
+type EIPResolutionCode struct {
+	Code        string   `json:"code"`
+	Description string   `json:"description"`
+	CaseTypes   []string `json:"case_types"`
+}
+
+// EIPResolutionStats counts resolved EIP cases per period, type and resolution code
+type EIPResolutionStats struct {
+	From     string               `json:"from"`
+	To       string               `json:"to"`
+	Interval string               `json:"interval"`
+	Total    int64                `json:"total"`
+	Periods  []EIPResolutionCount `json:"periods"`
+}
+
+// EIPResolutionCount is one period/type/code bucket of resolution stats
+type EIPResolutionCount struct {
+	PeriodStart        string  `json:"period_start"`
+	Type               string  `json:"type"`
+	ResolutionCode     string  `json:"resolution_code"`
+	Count              int64   `json:"count"`
+	MeanHoursToResolve float64 `json:"mean_hours_to_resolve"`
+}
+
+// EIPCaseMetrics is the EIP case workload: open counts and aging as of today, resolution time
+// and weekly throughput over from/to
+type EIPCaseMetrics struct {
+	AsOf       string                `json:"as_of"`
+	From       string                `json:"from"`
+	To         string                `json:"to"`
+	Side       string                `json:"side,omitempty"`
+	Type       string                `json:"type,omitempty"`
+	Open       int64                 `json:"open"`
+	OpenCounts []EIPCaseStatusCount  `json:"open_counts"`
+	Aging      []EIPAgingBucket      `json:"aging"`
+	Resolution EIPResolutionTime     `json:"resolution"`
+	Throughput []EIPWeeklyThroughput `json:"throughput"`
+}
+
+// EIPCaseStatusCount is the number of unresolved cases of one type in one status
+type EIPCaseStatusCount struct {
+	Type   string `json:"type"`
 	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+// EIPAgingBucket counts unresolved cases by age in calendar days
+type EIPAgingBucket struct {
+	Label   string `json:"label"`
+	MinDays int    `json:"min_days"`
+	MaxDays *int   `json:"max_days,omitempty"`
+	Count   int64  `json:"count"`
+}
+
+// EIPResolutionTime summarizes how long resolved cases took
+type EIPResolutionTime struct {
+	Resolved             int64    `json:"resolved"`
+	MeanHoursToResolve   *float64 `json:"mean_hours_to_resolve,omitempty"`
+	MedianHoursToResolve *float64 `json:"median_hours_to_resolve,omitempty"`
+}
+
+// EIPWeeklyThroughput counts cases opened and resolved in one week
+type EIPWeeklyThroughput struct {
+	WeekStart string `json:"week_start"`
+	Opened    int64  `json:"opened"`
+	Resolved  int64  `json:"resolved"`
+}
+
+// AssignEIPCaseRequest represents request to assign a case
+type AssignEIPCaseRequest struct {
+	Assignee string `json:"assignee"`
+}
+
+// ClaimEIPCaseRequest represents request to claim the next unassigned case in a queue
+type ClaimEIPCaseRequest struct {
+	Queue    string `json:"queue"`
+	Assignee string `json:"assignee"`
 }
 
+// EIPCaseFilter holds the EIP case list filters passed through to the EIP service
+type EIPCaseFilter struct {
+	Status      string
+	Side        string
+	EntryID     string
+	TraceNumber string
+	Assignee    string
+	Queue       string
+	Overdue     string
+	From        string
+	To          string
+	Page        PageQuery
+}
+
+// LedgerPostingFilter holds the ledger posting list filters passed through to the ledger service
+type LedgerPostingFilter struct {
+	AchSide     string
+	TraceNumber string
+	From        string
+	To          string
+	Page        PageQuery
+}
+
+// PageQuery holds the paging parameters passed through to a service's list endpoint;
+// the service validates them
+type PageQuery struct {
+	Limit        string
+	Cursor       string
+	SortBy       string
+	SortOrder    string
+	IncludeTotal string
+}
+
+// EIPCasePage is a page of EIP cases. NextCursor fetches the following page.
+type EIPCasePage struct {
+	Data       []*EIPCase `json:"data"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	TotalCount *int64     `json:"total_count,omitempty"`
+}
+
+// LedgerPostingPage is a page of ledger postings. NextCursor fetches the following page.
+type LedgerPostingPage struct {
+	Data       []*LedgerEntry `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	TotalCount *int64         `json:"total_count,omitempty"`
+}
+
+// EIPSLASummary counts unresolved EIP cases by SLA standing
+type EIPSLASummary struct {
+	AsOf     string                  `json:"as_of"`
+	Open     int64                   `json:"open"`
+	OnTrack  int64                   `json:"on_track"`
+	AtRisk   int64                   `json:"at_risk"`
+	Overdue  int64                   `json:"overdue"`
+	DueToday int64                   `json:"due_today"`
+	ByType   []EIPSLATypeSummary     `json:"by_type"`
+	Policies map[string]EIPSLAPolicy `json:"policies"`
+}
+
+// EIPSLATypeSummary counts unresolved cases of one type by SLA standing
+type EIPSLATypeSummary struct {
+	Type     string `json:"type"`
+	Open     int64  `json:"open"`
+	OnTrack  int64  `json:"on_track"`
+	AtRisk   int64  `json:"at_risk"`
+	Overdue  int64  `json:"overdue"`
+	DueToday int64  `json:"due_today"`
+}
+
+// EIPSLAPolicy is the business-day deadline for a case type
+type EIPSLAPolicy struct {
+	BusinessDays int `json:"business_days"`
+	AtRiskDays   int `json:"at_risk_days"`
+}
@@ -0,0 +1,88 @@
+package console
+
+import (
+	"net/http"
+
+	"ach-concourse/internal/common/auth"
+)
+
+// Console permissions, each guarding a group of routes in RegisterRoutes
+const (
+	PermAchItemsRead = "ach-items:read"
+
+	PermODFIRead   = "odfi:read"
+	PermODFICreate = "odfi:create"
+	PermODFIStatus = "odfi:status"
+
+	PermRDFIRead   = "rdfi:read"
+	PermRDFICreate = "rdfi:create"
+	PermRDFIReturn = "rdfi:return"
+
+	PermLedgerRead = "ledger:read"
+	PermLedgerPost = "ledger:post"
+	// PermLedgerSettle covers closing and reopening periods and transferring settlements
+	PermLedgerSettle = "ledger:settle"
+
+	PermEIPRead = "eip:read"
+	// PermEIPWork covers creating and working cases: status, assignment, comments,
+	// attachments, merges and dispute statements
+	PermEIPWork = "eip:work"
+	// PermEIPDispute covers approving and denying disputes; an approval returns the entry
+	PermEIPDispute = "eip:dispute"
+)
+
+// Permissions lists every console permission
+var Permissions = []string{
+	PermAchItemsRead,
+	PermODFIRead, PermODFICreate, PermODFIStatus,
+	PermRDFIRead, PermRDFICreate, PermRDFIReturn,
+	PermLedgerRead, PermLedgerPost, PermLedgerSettle,
+	PermEIPRead, PermEIPWork, PermEIPDispute,
+}
+
+// Console roles, each granting everything the one before it does
+const (
+	RoleViewer   = "viewer"
+	RoleAnalyst  = "analyst"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+var (
+	viewerPermissions = []string{
+		PermAchItemsRead, PermODFIRead, PermRDFIRead, PermLedgerRead, PermEIPRead,
+	}
+	analystPermissions  = append(append([]string{}, viewerPermissions...), PermEIPWork)
+	operatorPermissions = append(append([]string{}, analystPermissions...),
+		PermODFICreate, PermODFIStatus, PermRDFICreate, PermRDFIReturn, PermLedgerPost, PermEIPDispute)
+)
+
+// DefaultPolicy is used when AUTH_POLICY_FILE is not set. Viewers read everything, analysts
+// also work EIP cases, operators also move money (entries, returns, postings and dispute
+// decisions), and admins may do anything, including closing periods and settling.
+var DefaultPolicy = &auth.Policy{
+	RoleClaim: auth.DefaultRoleClaim,
+	Roles: map[string][]string{
+		RoleViewer:   viewerPermissions,
+		RoleAnalyst:  analystPermissions,
+		RoleOperator: operatorPermissions,
+		RoleAdmin:    {auth.AllPermissions},
+	},
+}
+
+// MyPermissions is what the authenticated caller may do in the console
+type MyPermissions struct {
+	Subject       string   `json:"subject,omitempty"`
+	Authenticated bool     `json:"authenticated"`
+	Roles         []string `json:"roles"`
+	Permissions   []string `json:"permissions"`
+}
+
+// require guards a route with permission. Without a policy, when the console runs
+// unauthenticated, every route is open.
+func (h *Handler) require(permission string) func(http.Handler) http.Handler {
+	if h.policy == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return auth.Require(h.policy, permission)
+}
@@ -14,6 +14,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"ach-concourse/internal/common/idempotency"
 )
 
 // Service handles business logic for console operations
@@ -38,6 +40,39 @@ func NewService() *Service {
 	}
 }
 
+// Errors returned by CreateEIPCase when checking the entry a case links to
+var (
+	ErrLinkedEntryNotFound    = errors.New("linked entry not found")
+	ErrLinkedEntryMismatch    = errors.New("trace_number does not match the linked entry")
+	ErrLinkedEntryUnavailable = errors.New("linked entry could not be checked")
+)
+
+// UpstreamError is returned when an upstream service rejects a request. It keeps the
+// raw response body so handlers can relay structured validation errors unchanged.
+type UpstreamError struct {
+	Service    string
+	StatusCode int
+	Body       []byte
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("%s service returned status %d: %s", e.Service, e.StatusCode, string(e.Body))
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context carrying the Idempotency-Key to forward upstream
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// setIdempotencyKey forwards the context's Idempotency-Key, if any, on an upstream request
+func setIdempotencyKey(ctx context.Context, req *http.Request) {
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" {
+		req.Header.Set(idempotency.HeaderKey, key)
+	}
+}
+
 // ========== ODFI Operations ==========
 
 // CreateODFIEntry creates an ODFI entry via the ODFI service
@@ -52,6 +87,7 @@ func (s *Service) CreateODFIEntry(ctx context.Context, req *CreateODFIEntryReque
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	setIdempotencyKey(ctx, httpReq)
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
@@ -61,7 +97,7 @@ func (s *Service) CreateODFIEntry(ctx context.Context, req *CreateODFIEntryReque
 
 	if resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("ODFI service returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &UpstreamError{Service: "ODFI", StatusCode: resp.StatusCode, Body: body}
 	}
 
 	var entry ODFIEntry
@@ -73,7 +109,7 @@ func (s *Service) CreateODFIEntry(ctx context.Context, req *CreateODFIEntryReque
 }
 
 // ListODFIEntries lists ODFI entries with optional filters
-func (s *Service) ListODFIEntries(ctx context.Context, status, traceNumber string) ([]*ODFIEntry, error) {
+func (s *Service) ListODFIEntries(ctx context.Context, status, traceNumber, settlementFrom, settlementTo string) ([]*ODFIEntry, error) {
 	queryParams := url.Values{}
 	if status != "" {
 		queryParams.Add("status", status)
@@ -81,6 +117,12 @@ func (s *Service) ListODFIEntries(ctx context.Context, status, traceNumber strin
 	if traceNumber != "" {
 		queryParams.Add("trace_number", traceNumber)
 	}
+	if settlementFrom != "" {
+		queryParams.Add("settlement_date_from", settlementFrom)
+	}
+	if settlementTo != "" {
+		queryParams.Add("settlement_date_to", settlementTo)
+	}
 
 	url := fmt.Sprintf("%s/api/v1/entries?%s", s.odfiBaseURL, queryParams.Encode())
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -172,6 +214,176 @@ func (s *Service) UpdateODFIEntryStatus(ctx context.Context, id, status string)
 	return &entry, nil
 }
 
+// CreateODFIBatch opens a batch via the ODFI service
+func (s *Service) CreateODFIBatch(ctx context.Context, req *CreateODFIBatchRequest) (*ODFIBatch, error) {
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.odfiBaseURL+"/api/v1/batches", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	setIdempotencyKey(ctx, httpReq)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "ODFI", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var batch ODFIBatch
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+// ListODFIBatches lists ODFI batches with their summed totals
+func (s *Service) ListODFIBatches(ctx context.Context, status string) ([]*ODFIBatch, error) {
+	queryParams := url.Values{}
+	if status != "" {
+		queryParams.Add("status", status)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/batches?%s", s.odfiBaseURL, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ODFI service returned status %d", resp.StatusCode)
+	}
+
+	var batches []*ODFIBatch
+	if err := json.NewDecoder(resp.Body).Decode(&batches); err != nil {
+		return nil, err
+	}
+
+	return batches, nil
+}
+
+// GetODFIBatch gets a single ODFI batch with its entries
+func (s *Service) GetODFIBatch(ctx context.Context, id string) (*ODFIBatch, error) {
+	url := fmt.Sprintf("%s/api/v1/batches/%s", s.odfiBaseURL, id)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ODFI service returned status %d", resp.StatusCode)
+	}
+
+	var batch ODFIBatch
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+// AddODFIBatchEntry adds an entry to an open ODFI batch
+func (s *Service) AddODFIBatchEntry(ctx context.Context, batchID string, req *CreateODFIEntryRequest) (*ODFIEntry, error) {
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/batches/%s/entries", s.odfiBaseURL, batchID)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	setIdempotencyKey(ctx, httpReq)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "ODFI", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var entry ODFIEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// CloseODFIBatch closes an ODFI batch
+func (s *Service) CloseODFIBatch(ctx context.Context, id string) (*ODFIBatch, error) {
+	return s.odfiBatchAction(ctx, id, "close")
+}
+
+// CancelODFIBatch cancels an ODFI batch and its pending entries
+func (s *Service) CancelODFIBatch(ctx context.Context, id string) (*ODFIBatch, error) {
+	return s.odfiBatchAction(ctx, id, "cancel")
+}
+
+// odfiBatchAction posts to /api/v1/batches/{id}/{action} on the ODFI service
+func (s *Service) odfiBatchAction(ctx context.Context, id, action string) (*ODFIBatch, error) {
+	url := fmt.Sprintf("%s/api/v1/batches/%s/%s", s.odfiBaseURL, id, action)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ODFI service returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var batch ODFIBatch
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
 // ========== RDFI Operations ==========
 
 // CreateRDFIEntry creates an RDFI entry via the RDFI service
@@ -186,6 +398,7 @@ func (s *Service) CreateRDFIEntry(ctx context.Context, req *CreateRDFIEntryReque
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	setIdempotencyKey(ctx, httpReq)
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
@@ -195,7 +408,7 @@ func (s *Service) CreateRDFIEntry(ctx context.Context, req *CreateRDFIEntryReque
 
 	if resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("RDFI service returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &UpstreamError{Service: "RDFI", StatusCode: resp.StatusCode, Body: body}
 	}
 
 	var entry RDFIEntry
@@ -306,6 +519,36 @@ func (s *Service) ReturnEntry(ctx context.Context, id, reason string) (*RDFIEntr
 	return &entry, nil
 }
 
+// PostRDFIEntry marks a received RDFI entry as posted to the receiver's account
+func (s *Service) PostRDFIEntry(ctx context.Context, id string) (*RDFIEntry, error) {
+	url := fmt.Sprintf("%s/api/v1/entries/%s/post", s.rdfiBaseURL, id)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("RDFI service returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var entry RDFIEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
 // ========== Ledger Operations ==========
 
 // CreateLedgerPosting creates a ledger posting
@@ -320,6 +563,7 @@ func (s *Service) CreateLedgerPosting(ctx context.Context, req *CreateLedgerPost
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	setIdempotencyKey(ctx, httpReq)
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
@@ -329,7 +573,7 @@ func (s *Service) CreateLedgerPosting(ctx context.Context, req *CreateLedgerPost
 
 	if resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Ledger service returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
 	}
 
 	var entry LedgerEntry
@@ -340,15 +584,22 @@ func (s *Service) CreateLedgerPosting(ctx context.Context, req *CreateLedgerPost
 	return &entry, nil
 }
 
-// ListLedgerPostings lists ledger postings with optional filters
-func (s *Service) ListLedgerPostings(ctx context.Context, achSide, traceNumber string) ([]*LedgerEntry, error) {
+// ListLedgerPostings lists a page of ledger postings with optional filters
+func (s *Service) ListLedgerPostings(ctx context.Context, filter LedgerPostingFilter) (*LedgerPostingPage, error) {
 	queryParams := url.Values{}
-	if achSide != "" {
-		queryParams.Add("ach_side", achSide)
+	if filter.AchSide != "" {
+		queryParams.Add("ach_side", filter.AchSide)
 	}
-	if traceNumber != "" {
-		queryParams.Add("trace_number", traceNumber)
+	if filter.TraceNumber != "" {
+		queryParams.Add("trace_number", filter.TraceNumber)
+	}
+	if filter.From != "" {
+		queryParams.Add("from", filter.From)
+	}
+	if filter.To != "" {
+		queryParams.Add("to", filter.To)
 	}
+	filter.Page.addTo(queryParams)
 
 	url := fmt.Sprintf("%s/api/v1/postings?%s", s.ledgerBaseURL, queryParams.Encode())
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -363,56 +614,84 @@ func (s *Service) ListLedgerPostings(ctx context.Context, achSide, traceNumber s
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Ledger service returned status %d", resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
 	}
 
-	var entries []*LedgerEntry
-	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+	var page LedgerPostingPage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
 		return nil, err
 	}
 
-	return entries, nil
+	return &page, nil
 }
 
-// GetBalances gets ledger balances
-func (s *Service) GetBalances(ctx context.Context) (*BalanceResponse, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", s.ledgerBaseURL+"/api/v1/balances", nil)
+// addTo adds the paging parameters that are set to params
+func (q PageQuery) addTo(params url.Values) {
+	for name, value := range map[string]string{
+		"limit":         q.Limit,
+		"cursor":        q.Cursor,
+		"sort_by":       q.SortBy,
+		"sort_order":    q.SortOrder,
+		"include_total": q.IncludeTotal,
+	} {
+		if value != "" {
+			params.Add(name, value)
+		}
+	}
+}
+
+// ReverseLedgerPosting reverses a ledger posting, returning the linked reversing journal
+// entry, or nil if the posting does not exist
+func (s *Service) ReverseLedgerPosting(ctx context.Context, id, reason string) (*LedgerJournalEntry, error) {
+	bodyBytes, err := json.Marshal(ReverseLedgerPostingRequest{Reason: reason})
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := s.httpClient.Do(req)
+	url := fmt.Sprintf("%s/api/v1/postings/%s/reverse", s.ledgerBaseURL, id)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Ledger service returned status %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
 	}
 
-	var balances BalanceResponse
-	if err := json.NewDecoder(resp.Body).Decode(&balances); err != nil {
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var journal LedgerJournalEntry
+	if err := json.NewDecoder(resp.Body).Decode(&journal); err != nil {
 		return nil, err
 	}
 
-	return &balances, nil
+	return &journal, nil
 }
 
-// ========== EIP Operations ==========
-
-// CreateEIPCase creates an EIP case
-func (s *Service) CreateEIPCase(ctx context.Context, req *CreateEIPCaseRequest) (*EIPCase, error) {
+// CreateLedgerJournalEntry posts a balanced journal entry
+func (s *Service) CreateLedgerJournalEntry(ctx context.Context, req *CreateLedgerJournalEntryRequest) (*LedgerJournalEntry, error) {
 	bodyBytes, err := json.Marshal(req)
 	if err != nil {
 		return nil, err
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.eipBaseURL+"/api/v1/cases", bytes.NewReader(bodyBytes))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.ledgerBaseURL+"/api/v1/journal-entries", bytes.NewReader(bodyBytes))
 	if err != nil {
 		return nil, err
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	setIdempotencyKey(ctx, httpReq)
 
 	resp, err := s.httpClient.Do(httpReq)
 	if err != nil {
@@ -422,31 +701,28 @@ func (s *Service) CreateEIPCase(ctx context.Context, req *CreateEIPCaseRequest)
 
 	if resp.StatusCode != http.StatusCreated {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("EIP service returned status %d: %s", resp.StatusCode, string(body))
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
 	}
 
-	var eipCase EIPCase
-	if err := json.NewDecoder(resp.Body).Decode(&eipCase); err != nil {
+	var journal LedgerJournalEntry
+	if err := json.NewDecoder(resp.Body).Decode(&journal); err != nil {
 		return nil, err
 	}
 
-	return &eipCase, nil
+	return &journal, nil
 }
 
-// ListEIPCases lists EIP cases with optional filters
-func (s *Service) ListEIPCases(ctx context.Context, status, side, traceNumber string) ([]*EIPCase, error) {
+// ListLedgerJournalEntries lists journal entries with optional filters
+func (s *Service) ListLedgerJournalEntries(ctx context.Context, achSide, traceNumber string) ([]*LedgerJournalEntry, error) {
 	queryParams := url.Values{}
-	if status != "" {
-		queryParams.Add("status", status)
-	}
-	if side != "" {
-		queryParams.Add("side", side)
+	if achSide != "" {
+		queryParams.Add("ach_side", achSide)
 	}
 	if traceNumber != "" {
 		queryParams.Add("trace_number", traceNumber)
 	}
 
-	url := fmt.Sprintf("%s/api/v1/cases?%s", s.eipBaseURL, queryParams.Encode())
+	url := fmt.Sprintf("%s/api/v1/journal-entries?%s", s.ledgerBaseURL, queryParams.Encode())
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
@@ -459,21 +735,20 @@ func (s *Service) ListEIPCases(ctx context.Context, status, side, traceNumber st
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("EIP service returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("Ledger service returned status %d", resp.StatusCode)
 	}
 
-	var cases []*EIPCase
-	if err := json.NewDecoder(resp.Body).Decode(&cases); err != nil {
+	var journals []*LedgerJournalEntry
+	if err := json.NewDecoder(resp.Body).Decode(&journals); err != nil {
 		return nil, err
 	}
 
-	return cases, nil
+	return journals, nil
 }
 
-// GetEIPCase gets a single EIP case by ID
-func (s *Service) GetEIPCase(ctx context.Context, id string) (*EIPCase, error) {
-	url := fmt.Sprintf("%s/api/v1/cases/%s", s.eipBaseURL, id)
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// GetLedgerJournalEntry gets a journal entry with its legs
+func (s *Service) GetLedgerJournalEntry(ctx context.Context, id string) (*LedgerJournalEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.ledgerBaseURL+"/api/v1/journal-entries/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -487,58 +762,1045 @@ func (s *Service) GetEIPCase(ctx context.Context, id string) (*EIPCase, error) {
 	if resp.StatusCode == http.StatusNotFound {
 		return nil, nil
 	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("EIP service returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("Ledger service returned status %d", resp.StatusCode)
 	}
 
-	var eipCase EIPCase
-	if err := json.NewDecoder(resp.Body).Decode(&eipCase); err != nil {
+	var journal LedgerJournalEntry
+	if err := json.NewDecoder(resp.Body).Decode(&journal); err != nil {
 		return nil, err
 	}
 
-	return &eipCase, nil
+	return &journal, nil
 }
 
-// UpdateEIPCaseStatus updates an EIP case status
-func (s *Service) UpdateEIPCaseStatus(ctx context.Context, id, status string) (*EIPCase, error) {
-	reqBody := UpdateEIPCaseStatusRequest{Status: status}
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, err
+// ListLedgerEvents gets the entry status changes the ledger has processed
+func (s *Service) ListLedgerEvents(ctx context.Context, entryID, traceNumber string) ([]*LedgerProcessedEvent, error) {
+	queryParams := url.Values{}
+	if entryID != "" {
+		queryParams.Add("entry_id", entryID)
+	}
+	if traceNumber != "" {
+		queryParams.Add("trace_number", traceNumber)
 	}
 
-	url := fmt.Sprintf("%s/api/v1/cases/%s/status", s.eipBaseURL, id)
-	httpReq, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(bodyBytes))
+	url := fmt.Sprintf("%s/api/v1/events?%s", s.ledgerBaseURL, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
 
-	resp, err := s.httpClient.Do(httpReq)
+	resp, err := s.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, nil
-	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("EIP service returned status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("Ledger service returned status %d", resp.StatusCode)
 	}
 
-	var eipCase EIPCase
-	if err := json.NewDecoder(resp.Body).Decode(&eipCase); err != nil {
+	var processed []*LedgerProcessedEvent
+	if err := json.NewDecoder(resp.Body).Decode(&processed); err != nil {
 		return nil, err
 	}
 
-	return &eipCase, nil
+	return processed, nil
 }
 
-// ========== Unified ACH Items (Fan-Out/Fan-In with Graceful Degradation) ==========
-
-// serviceResult holds the result from a single service call
+// ListLedgerAccounts gets the ledger's chart of accounts
+func (s *Service) ListLedgerAccounts(ctx context.Context) ([]*LedgerAccount, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.ledgerBaseURL+"/api/v1/accounts", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ledger service returned status %d", resp.StatusCode)
+	}
+
+	var accounts []*LedgerAccount
+	if err := json.NewDecoder(resp.Body).Decode(&accounts); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+// ListLedgerPeriods gets the ledger's closed and reopened periods
+func (s *Service) ListLedgerPeriods(ctx context.Context) ([]*LedgerPeriod, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.ledgerBaseURL+"/api/v1/periods", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ledger service returned status %d", resp.StatusCode)
+	}
+
+	var periods []*LedgerPeriod
+	if err := json.NewDecoder(resp.Body).Decode(&periods); err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+// GetLedgerPeriod gets a period with its balance snapshot and history, or nil if the day
+// has never been closed
+func (s *Service) GetLedgerPeriod(ctx context.Context, date string) (*LedgerPeriod, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.ledgerBaseURL+"/api/v1/periods/"+date, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ledger service returned status %d", resp.StatusCode)
+	}
+
+	var period LedgerPeriod
+	if err := json.NewDecoder(resp.Body).Decode(&period); err != nil {
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+// CloseLedgerPeriod closes an accounting day
+func (s *Service) CloseLedgerPeriod(ctx context.Context, date string) (*LedgerPeriod, error) {
+	return s.ledgerPeriodAction(ctx, date, "close", nil)
+}
+
+// ReopenLedgerPeriod reopens the most recently closed accounting day
+func (s *Service) ReopenLedgerPeriod(ctx context.Context, date, reason string) (*LedgerPeriod, error) {
+	return s.ledgerPeriodAction(ctx, date, "reopen", ReopenLedgerPeriodRequest{Reason: reason})
+}
+
+func (s *Service) ledgerPeriodAction(ctx context.Context, date, action string, body any) (*LedgerPeriod, error) {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/periods/%s/%s", s.ledgerBaseURL, date, action)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var period LedgerPeriod
+	if err := json.NewDecoder(resp.Body).Decode(&period); err != nil {
+		return nil, err
+	}
+
+	return &period, nil
+}
+
+// GetLedgerSettlement gets the settlement positions for a date (today if empty)
+func (s *Service) GetLedgerSettlement(ctx context.Context, date, achSide string) (*LedgerSettlement, error) {
+	queryParams := url.Values{}
+	if date != "" {
+		queryParams.Add("date", date)
+	}
+	if achSide != "" {
+		queryParams.Add("ach_side", achSide)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/settlements?%s", s.ledgerBaseURL, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var settlement LedgerSettlement
+	if err := json.NewDecoder(resp.Body).Decode(&settlement); err != nil {
+		return nil, err
+	}
+
+	return &settlement, nil
+}
+
+// TransferLedgerSettlement posts the settlement transfer journal entries for a date
+func (s *Service) TransferLedgerSettlement(ctx context.Context, date string) (*LedgerSettlement, error) {
+	url := fmt.Sprintf("%s/api/v1/settlements/%s/transfer", s.ledgerBaseURL, date)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var settlement LedgerSettlement
+	if err := json.NewDecoder(resp.Body).Decode(&settlement); err != nil {
+		return nil, err
+	}
+
+	return &settlement, nil
+}
+
+// DownloadLedgerStatement fetches a BAI2 or camt.053 statement, passing through the
+// ledger's account, from, to and format parameters
+func (s *Service) DownloadLedgerStatement(ctx context.Context, account, from, to, format string) (*LedgerStatementFile, error) {
+	queryParams := url.Values{}
+	if account != "" {
+		queryParams.Add("account", account)
+	}
+	if from != "" {
+		queryParams.Add("from", from)
+	}
+	if to != "" {
+		queryParams.Add("to", to)
+	}
+	if format != "" {
+		queryParams.Add("format", format)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/statements?%s", s.ledgerBaseURL, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	return &LedgerStatementFile{
+		ContentType:        resp.Header.Get("Content-Type"),
+		ContentDisposition: resp.Header.Get("Content-Disposition"),
+		Body:               body,
+	}, nil
+}
+
+// ListLedgerHolds gets holds, passing through the ledger's account, status, entry and
+// trace number filters
+func (s *Service) ListLedgerHolds(ctx context.Context, account, status, entryID, traceNumber string) ([]*LedgerHold, error) {
+	queryParams := url.Values{}
+	if account != "" {
+		queryParams.Add("account", account)
+	}
+	if status != "" {
+		queryParams.Add("status", status)
+	}
+	if entryID != "" {
+		queryParams.Add("entry_id", entryID)
+	}
+	if traceNumber != "" {
+		queryParams.Add("trace_number", traceNumber)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/holds?%s", s.ledgerBaseURL, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Ledger service returned status %d", resp.StatusCode)
+	}
+
+	var holds []*LedgerHold
+	if err := json.NewDecoder(resp.Body).Decode(&holds); err != nil {
+		return nil, err
+	}
+
+	return holds, nil
+}
+
+// ReleaseLedgerHold releases an active hold, returning nil if it does not exist
+func (s *Service) ReleaseLedgerHold(ctx context.Context, id string) (*LedgerHold, error) {
+	url := fmt.Sprintf("%s/api/v1/holds/%s/release", s.ledgerBaseURL, id)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var hold LedgerHold
+	if err := json.NewDecoder(resp.Body).Decode(&hold); err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// GetBalances gets ledger balances, passing through the ledger's side, account,
+// trace number and as_of filters
+func (s *Service) GetBalances(ctx context.Context, achSide, account, traceNumber, asOf string) (*BalanceResponse, error) {
+	queryParams := url.Values{}
+	if achSide != "" {
+		queryParams.Add("ach_side", achSide)
+	}
+	if account != "" {
+		queryParams.Add("account", account)
+	}
+	if traceNumber != "" {
+		queryParams.Add("trace_number", traceNumber)
+	}
+	if asOf != "" {
+		queryParams.Add("as_of", asOf)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/balances?%s", s.ledgerBaseURL, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var balances BalanceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&balances); err != nil {
+		return nil, err
+	}
+
+	return &balances, nil
+}
+
+// GetBalanceSeries gets per-account running balances by period from the ledger
+func (s *Service) GetBalanceSeries(ctx context.Context, achSide, account, traceNumber, interval, from, to string) (*BalanceSeriesResponse, error) {
+	queryParams := url.Values{}
+	if achSide != "" {
+		queryParams.Add("ach_side", achSide)
+	}
+	if account != "" {
+		queryParams.Add("account", account)
+	}
+	if traceNumber != "" {
+		queryParams.Add("trace_number", traceNumber)
+	}
+	if interval != "" {
+		queryParams.Add("interval", interval)
+	}
+	if from != "" {
+		queryParams.Add("from", from)
+	}
+	if to != "" {
+		queryParams.Add("to", to)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/balances/series?%s", s.ledgerBaseURL, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "Ledger", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var series BalanceSeriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&series); err != nil {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+// ========== EIP Operations ==========
+
+// CreateEIPCase creates an EIP case. A case that names an entry_id is only created if the
+// entry exists on its side; its trace number is filled in from the entry when omitted.
+// created is false when EIP attached the request to an existing duplicate case.
+func (s *Service) CreateEIPCase(ctx context.Context, req *CreateEIPCaseRequest) (*EIPCase, bool, error) {
+	if err := s.checkLinkedEntry(ctx, req); err != nil {
+		return nil, false, err
+	}
+
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.eipBaseURL+"/api/v1/cases", bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, false, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	setIdempotencyKey(ctx, httpReq)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, false, &UpstreamError{Service: "EIP", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var eipCase EIPCase
+	if err := json.NewDecoder(resp.Body).Decode(&eipCase); err != nil {
+		return nil, false, err
+	}
+
+	return &eipCase, resp.StatusCode == http.StatusCreated, nil
+}
+
+// checkLinkedEntry confirms the entry a new case links to exists on the case's side
+func (s *Service) checkLinkedEntry(ctx context.Context, req *CreateEIPCaseRequest) error {
+	if req.EntryID == "" {
+		return nil
+	}
+
+	side := strings.ToUpper(req.Side)
+	if side != "ODFI" && side != "RDFI" {
+		return errors.New("side must be ODFI or RDFI")
+	}
+
+	item, err := s.fetchEntry(ctx, side, req.EntryID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrLinkedEntryUnavailable, err)
+	}
+	if item == nil {
+		return fmt.Errorf("%w: %s entry %s", ErrLinkedEntryNotFound, side, req.EntryID)
+	}
+
+	if req.TraceNumber == "" {
+		req.TraceNumber = item.TraceNumber
+	} else if req.TraceNumber != item.TraceNumber {
+		return fmt.Errorf("%w (entry has %s)", ErrLinkedEntryMismatch, item.TraceNumber)
+	}
+
+	return nil
+}
+
+// ListEIPCases lists a page of EIP cases with optional filters
+func (s *Service) ListEIPCases(ctx context.Context, filter EIPCaseFilter) (*EIPCasePage, error) {
+	queryParams := url.Values{}
+	if filter.Status != "" {
+		queryParams.Add("status", filter.Status)
+	}
+	if filter.Side != "" {
+		queryParams.Add("side", filter.Side)
+	}
+	if filter.EntryID != "" {
+		queryParams.Add("entry_id", filter.EntryID)
+	}
+	if filter.TraceNumber != "" {
+		queryParams.Add("trace_number", filter.TraceNumber)
+	}
+	if filter.Assignee != "" {
+		queryParams.Add("assignee", filter.Assignee)
+	}
+	if filter.Queue != "" {
+		queryParams.Add("queue", filter.Queue)
+	}
+	if filter.Overdue != "" {
+		queryParams.Add("overdue", filter.Overdue)
+	}
+	if filter.From != "" {
+		queryParams.Add("from", filter.From)
+	}
+	if filter.To != "" {
+		queryParams.Add("to", filter.To)
+	}
+	filter.Page.addTo(queryParams)
+
+	url := fmt.Sprintf("%s/api/v1/cases?%s", s.eipBaseURL, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "EIP", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var page EIPCasePage
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// GetEIPCase gets a single EIP case by ID, with its linked entry embedded
+func (s *Service) GetEIPCase(ctx context.Context, id string) (*EIPCase, error) {
+	url := fmt.Sprintf("%s/api/v1/cases/%s", s.eipBaseURL, id)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("EIP service returned status %d", resp.StatusCode)
+	}
+
+	var eipCase EIPCase
+	if err := json.NewDecoder(resp.Body).Decode(&eipCase); err != nil {
+		return nil, err
+	}
+
+	if eipCase.EntryID != "" {
+		item, err := s.fetchEntry(ctx, eipCase.Side, eipCase.EntryID)
+		switch {
+		case err != nil:
+			eipCase.EntryError = err.Error()
+		case item == nil:
+			eipCase.EntryError = ErrLinkedEntryNotFound.Error()
+		default:
+			eipCase.Entry = item
+		}
+	}
+
+	return &eipCase, nil
+}
+
+// UpdateEIPCaseStatus updates an EIP case status
+func (s *Service) UpdateEIPCaseStatus(ctx context.Context, id string, req *UpdateEIPCaseStatusRequest) (*EIPCase, error) {
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/cases/%s/status", s.eipBaseURL, id)
+	httpReq, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "EIP", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var eipCase EIPCase
+	if err := json.NewDecoder(resp.Body).Decode(&eipCase); err != nil {
+		return nil, err
+	}
+
+	return &eipCase, nil
+}
+
+// GetEIPSLASummary gets the EIP SLA summary
+func (s *Service) GetEIPSLASummary(ctx context.Context) (*EIPSLASummary, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.eipBaseURL+"/api/v1/cases/sla", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("EIP service returned status %d", resp.StatusCode)
+	}
+
+	var summary EIPSLASummary
+	if err := json.NewDecoder(resp.Body).Decode(&summary); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// ListEIPResolutionCodes lists the EIP resolution catalog
+func (s *Service) ListEIPResolutionCodes(ctx context.Context) ([]*EIPResolutionCode, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.eipBaseURL+"/api/v1/resolutions", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("EIP service returned status %d", resp.StatusCode)
+	}
+
+	var codes []*EIPResolutionCode
+	if err := json.NewDecoder(resp.Body).Decode(&codes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// GetEIPResolutionStats gets EIP resolution statistics, passing through the interval, side
+// and from/to filters
+func (s *Service) GetEIPResolutionStats(ctx context.Context, interval, side, from, to string) (*EIPResolutionStats, error) {
+	queryParams := url.Values{}
+	if interval != "" {
+		queryParams.Add("interval", interval)
+	}
+	if side != "" {
+		queryParams.Add("side", side)
+	}
+	if from != "" {
+		queryParams.Add("from", from)
+	}
+	if to != "" {
+		queryParams.Add("to", to)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/resolutions/stats?%s", s.eipBaseURL, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "EIP", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var stats EIPResolutionStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// GetEIPCaseMetrics gets EIP case metrics, passing through the side, type and from/to filters
+func (s *Service) GetEIPCaseMetrics(ctx context.Context, side, caseType, from, to string) (*EIPCaseMetrics, error) {
+	queryParams := url.Values{}
+	if side != "" {
+		queryParams.Add("side", side)
+	}
+	if caseType != "" {
+		queryParams.Add("type", caseType)
+	}
+	if from != "" {
+		queryParams.Add("from", from)
+	}
+	if to != "" {
+		queryParams.Add("to", to)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/cases/metrics?%s", s.eipBaseURL, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "EIP", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var metrics EIPCaseMetrics
+	if err := json.NewDecoder(resp.Body).Decode(&metrics); err != nil {
+		return nil, err
+	}
+
+	return &metrics, nil
+}
+
+// AssignEIPCase assigns an EIP case, returning nil if it does not exist
+func (s *Service) AssignEIPCase(ctx context.Context, id, assignee string) (*EIPCase, error) {
+	bodyBytes, err := json.Marshal(AssignEIPCaseRequest{Assignee: assignee})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postEIPCaseAction(ctx, fmt.Sprintf("%s/api/v1/cases/%s/assign", s.eipBaseURL, id), bodyBytes)
+}
+
+// UnassignEIPCase unassigns an EIP case, returning nil if it does not exist
+func (s *Service) UnassignEIPCase(ctx context.Context, id string) (*EIPCase, error) {
+	return s.postEIPCaseAction(ctx, fmt.Sprintf("%s/api/v1/cases/%s/unassign", s.eipBaseURL, id), nil)
+}
+
+// ClaimNextEIPCase claims the oldest unassigned open case in a queue, returning nil if
+// the queue is empty
+func (s *Service) ClaimNextEIPCase(ctx context.Context, req *ClaimEIPCaseRequest) (*EIPCase, error) {
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postEIPCaseAction(ctx, s.eipBaseURL+"/api/v1/cases/claim", bodyBytes)
+}
+
+// postEIPCaseAction POSTs to an EIP case action endpoint that answers with the case
+func (s *Service) postEIPCaseAction(ctx context.Context, url string, body []byte) (*EIPCase, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "EIP", StatusCode: resp.StatusCode, Body: respBody}
+	}
+
+	var eipCase EIPCase
+	if err := json.NewDecoder(resp.Body).Decode(&eipCase); err != nil {
+		return nil, err
+	}
+
+	return &eipCase, nil
+}
+
+// RecordEIPDisputeStatement records the written statement on an EIP dispute case,
+// returning nil if the case does not exist
+func (s *Service) RecordEIPDisputeStatement(ctx context.Context, id string, req *EIPDisputeStatementRequest) (*EIPCase, error) {
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postEIPCaseAction(ctx, fmt.Sprintf("%s/api/v1/cases/%s/dispute/statement", s.eipBaseURL, id), bodyBytes)
+}
+
+// ApproveEIPDispute approves an EIP dispute case, which returns the RDFI entry, returning
+// nil if the case does not exist
+func (s *Service) ApproveEIPDispute(ctx context.Context, id string, req *ApproveEIPDisputeRequest) (*EIPCase, error) {
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postEIPCaseAction(ctx, fmt.Sprintf("%s/api/v1/cases/%s/dispute/approve", s.eipBaseURL, id), bodyBytes)
+}
+
+// DenyEIPDispute denies an EIP dispute case, returning nil if the case does not exist
+func (s *Service) DenyEIPDispute(ctx context.Context, id string, req *DenyEIPDisputeRequest) (*EIPCase, error) {
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postEIPCaseAction(ctx, fmt.Sprintf("%s/api/v1/cases/%s/dispute/deny", s.eipBaseURL, id), bodyBytes)
+}
+
+// MergeEIPCases merges duplicate EIP cases into a surviving case, returning nil if the
+// surviving case does not exist
+func (s *Service) MergeEIPCases(ctx context.Context, id string, req *MergeEIPCasesRequest) (*EIPCase, error) {
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postEIPCaseAction(ctx, fmt.Sprintf("%s/api/v1/cases/%s/merge", s.eipBaseURL, id), bodyBytes)
+}
+
+// AddEIPCaseComment comments on an EIP case, returning nil if the case does not exist
+func (s *Service) AddEIPCaseComment(ctx context.Context, id string, req *CreateEIPCaseCommentRequest) (*EIPCaseComment, error) {
+	bodyBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/cases/%s/comments", s.eipBaseURL, id)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "EIP", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var comment EIPCaseComment
+	if err := json.NewDecoder(resp.Body).Decode(&comment); err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// ListEIPCaseComments lists an EIP case's comments, returning nil if the case does not exist
+func (s *Service) ListEIPCaseComments(ctx context.Context, id, visibility string) ([]*EIPCaseComment, error) {
+	queryParams := url.Values{}
+	if visibility != "" {
+		queryParams.Add("visibility", visibility)
+	}
+
+	url := fmt.Sprintf("%s/api/v1/cases/%s/comments?%s", s.eipBaseURL, id, queryParams.Encode())
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("EIP service returned status %d", resp.StatusCode)
+	}
+
+	comments := []*EIPCaseComment{}
+	if err := json.NewDecoder(resp.Body).Decode(&comments); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// UploadEIPAttachment streams a multipart/form-data upload to the EIP service unchanged,
+// returning nil if the case does not exist
+func (s *Service) UploadEIPAttachment(ctx context.Context, id, contentType string, body io.Reader) (*EIPAttachment, error) {
+	url := fmt.Sprintf("%s/api/v1/cases/%s/attachments", s.eipBaseURL, id)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, body)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, &UpstreamError{Service: "EIP", StatusCode: resp.StatusCode, Body: respBody}
+	}
+
+	var attachment EIPAttachment
+	if err := json.NewDecoder(resp.Body).Decode(&attachment); err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// ListEIPAttachments lists an EIP case's attachments, returning nil if the case does not exist
+func (s *Service) ListEIPAttachments(ctx context.Context, id string) ([]*EIPAttachment, error) {
+	url := fmt.Sprintf("%s/api/v1/cases/%s/attachments", s.eipBaseURL, id)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("EIP service returned status %d", resp.StatusCode)
+	}
+
+	attachments := []*EIPAttachment{}
+	if err := json.NewDecoder(resp.Body).Decode(&attachments); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// OpenEIPAttachment starts streaming an attachment from the EIP service, returning nil if
+// it does not exist. The caller closes the download's Body.
+func (s *Service) OpenEIPAttachment(ctx context.Context, id, attachmentID string) (*EIPAttachmentDownload, error) {
+	url := fmt.Sprintf("%s/api/v1/cases/%s/attachments/%s", s.eipBaseURL, id, attachmentID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, &UpstreamError{Service: "EIP", StatusCode: resp.StatusCode, Body: body}
+	}
+
+	return &EIPAttachmentDownload{
+		ContentType:        resp.Header.Get("Content-Type"),
+		ContentLength:      resp.Header.Get("Content-Length"),
+		ContentDisposition: resp.Header.Get("Content-Disposition"),
+		SHA256:             resp.Header.Get("X-Content-SHA256"),
+		Body:               resp.Body,
+	}, nil
+}
+
+// ========== Unified ACH Items (Fan-Out/Fan-In with Graceful Degradation) ==========
+
+// serviceResult holds the result from a single service call
 type serviceResult struct {
 	serviceName string
 	items       []*UnifiedAchItem
@@ -548,10 +1810,12 @@ type serviceResult struct {
 
 // GetAchItems fetches and unifies entries from ODFI and RDFI services using fan-out/fan-in.
 // If a service is unavailable, returns partial results from healthy services with degradation info.
-func (s *Service) GetAchItems(ctx context.Context, side, status, traceNumber, sortBy, sortOrder string, limit, offset int) (*UnifiedAchResponse, error) {
+// A settlement date range limits results to ODFI entries, since only they carry settlement dates.
+func (s *Service) GetAchItems(ctx context.Context, side, status, traceNumber, settlementFrom, settlementTo, sortBy, sortOrder string, limit, offset int) (*UnifiedAchResponse, error) {
 	// Determine which services to query
+	settlementFilter := settlementFrom != "" || settlementTo != ""
 	queryODFI := side == "" || strings.ToUpper(side) == "ODFI"
-	queryRDFI := side == "" || strings.ToUpper(side) == "RDFI"
+	queryRDFI := (side == "" || strings.ToUpper(side) == "RDFI") && !settlementFilter
 
 	// Channel to collect results - buffer for max expected services
 	resultsChan := make(chan serviceResult, 2)
@@ -565,7 +1829,7 @@ func (s *Service) GetAchItems(ctx context.Context, side, status, traceNumber, so
 		go func() {
 			defer wg.Done()
 			start := time.Now()
-			items, err := s.fetchODFIEntries(ctx, status, traceNumber)
+			items, err := s.fetchODFIEntries(ctx, status, traceNumber, settlementFrom, settlementTo)
 			resultsChan <- serviceResult{
 				serviceName: "ODFI",
 				items:       items,
@@ -650,15 +1914,35 @@ func (s *Service) GetAchItems(ctx context.Context, side, status, traceNumber, so
 
 // GetAchItemsLegacy is the old synchronous version (deprecated)
 func (s *Service) GetAchItemsLegacy(ctx context.Context, side, status, traceNumber, sortBy, sortOrder string, limit, offset int) ([]*UnifiedAchItem, error) {
-	resp, err := s.GetAchItems(ctx, side, status, traceNumber, sortBy, sortOrder, limit, offset)
+	resp, err := s.GetAchItems(ctx, side, status, traceNumber, "", "", sortBy, sortOrder, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	return resp.Items, nil
 }
 
-// GetAchItem fetches a single entry from the specified service
+// GetAchItem fetches a single entry from the specified service, with the unresolved EIP
+// cases linked to it. The cases are left out if the EIP service is unavailable.
 func (s *Service) GetAchItem(ctx context.Context, side, id string) (*UnifiedAchItem, error) {
+	item, err := s.fetchEntry(ctx, side, id)
+	if err != nil || item == nil {
+		return item, err
+	}
+
+	cases, err := s.ListEIPCases(ctx, EIPCaseFilter{Side: item.Side, EntryID: item.EntryID})
+	if err == nil {
+		for _, eipCase := range cases.Data {
+			if eipCase.Status != "RESOLVED" {
+				item.OpenCases = append(item.OpenCases, eipCase)
+			}
+		}
+	}
+
+	return item, nil
+}
+
+// fetchEntry fetches a single entry from the service that owns side
+func (s *Service) fetchEntry(ctx context.Context, side, id string) (*UnifiedAchItem, error) {
 	side = strings.ToUpper(side)
 
 	switch side {
@@ -672,27 +1956,15 @@ func (s *Service) GetAchItem(ctx context.Context, side, id string) (*UnifiedAchI
 }
 
 // fetchODFIEntries fetches entries from the ODFI service
-func (s *Service) fetchODFIEntries(ctx context.Context, status, traceNumber string) ([]*UnifiedAchItem, error) {
-	entries, err := s.ListODFIEntries(ctx, status, traceNumber)
+func (s *Service) fetchODFIEntries(ctx context.Context, status, traceNumber, settlementFrom, settlementTo string) ([]*UnifiedAchItem, error) {
+	entries, err := s.ListODFIEntries(ctx, status, traceNumber, settlementFrom, settlementTo)
 	if err != nil {
 		return nil, err
 	}
 
 	var items []*UnifiedAchItem
 	for _, entry := range entries {
-		items = append(items, &UnifiedAchItem{
-			Side:        "ODFI",
-			Source:      "odfi",
-			EntryID:     entry.ID,
-			TraceNumber: entry.TraceNumber,
-			AmountCents: entry.AmountCents,
-			Status:      entry.Status,
-			CreatedAt:   entry.CreatedAt,
-			Extra: map[string]interface{}{
-				"company_name": entry.CompanyName,
-				"sec_code":     entry.SecCode,
-			},
-		})
+		items = append(items, odfiToUnified(entry))
 	}
 
 	return items, nil
@@ -707,23 +1979,7 @@ func (s *Service) fetchRDFIEntries(ctx context.Context, status, traceNumber stri
 
 	var items []*UnifiedAchItem
 	for _, entry := range entries {
-		extra := map[string]interface{}{
-			"receiver_name": entry.ReceiverName,
-		}
-		if entry.ReturnReason != "" {
-			extra["return_reason"] = entry.ReturnReason
-		}
-
-		items = append(items, &UnifiedAchItem{
-			Side:        "RDFI",
-			Source:      "rdfi",
-			EntryID:     entry.ID,
-			TraceNumber: entry.TraceNumber,
-			AmountCents: entry.AmountCents,
-			Status:      entry.Status,
-			CreatedAt:   entry.CreatedAt,
-			Extra:       extra,
-		})
+		items = append(items, rdfiToUnified(entry))
 	}
 
 	return items, nil
@@ -739,19 +1995,7 @@ func (s *Service) fetchODFIEntry(ctx context.Context, id string) (*UnifiedAchIte
 		return nil, nil
 	}
 
-	return &UnifiedAchItem{
-		Side:        "ODFI",
-		Source:      "odfi",
-		EntryID:     entry.ID,
-		TraceNumber: entry.TraceNumber,
-		AmountCents: entry.AmountCents,
-		Status:      entry.Status,
-		CreatedAt:   entry.CreatedAt,
-		Extra: map[string]interface{}{
-			"company_name": entry.CompanyName,
-			"sec_code":     entry.SecCode,
-		},
-	}, nil
+	return odfiToUnified(entry), nil
 }
 
 // fetchRDFIEntry fetches a single entry from RDFI service
@@ -764,8 +2008,47 @@ func (s *Service) fetchRDFIEntry(ctx context.Context, id string) (*UnifiedAchIte
 		return nil, nil
 	}
 
+	return rdfiToUnified(entry), nil
+}
+
+// odfiToUnified maps an ODFI entry to the unified view
+func odfiToUnified(entry *ODFIEntry) *UnifiedAchItem {
+	return &UnifiedAchItem{
+		Side:           "ODFI",
+		Source:         "odfi",
+		EntryID:        entry.ID,
+		TraceNumber:    entry.TraceNumber,
+		AmountCents:    entry.AmountCents,
+		Status:         entry.Status,
+		CreatedAt:      entry.CreatedAt,
+		SettlementDate: entry.SettlementDate,
+		Extra: map[string]interface{}{
+			"batch_id":             entry.BatchID,
+			"company_name":         entry.CompanyName,
+			"sec_code":             entry.SecCode,
+			"routing_number":       entry.RoutingNumber,
+			"account_number":       entry.AccountNumber,
+			"account_type":         entry.AccountType,
+			"transaction_code":     entry.TransactionCode,
+			"direction":            entry.Direction,
+			"receiver_name":        entry.ReceiverName,
+			"receiver_id":          entry.ReceiverID,
+			"authorization_type":   entry.AuthorizationType,
+			"addenda":              entry.Addenda,
+			"effective_entry_date": entry.EffectiveEntryDate,
+		},
+	}
+}
+
+// rdfiToUnified maps an RDFI entry to the unified view
+func rdfiToUnified(entry *RDFIEntry) *UnifiedAchItem {
 	extra := map[string]interface{}{
-		"receiver_name": entry.ReceiverName,
+		"receiver_name":    entry.ReceiverName,
+		"routing_number":   entry.RoutingNumber,
+		"account_number":   entry.AccountNumber,
+		"account_type":     entry.AccountType,
+		"transaction_code": entry.TransactionCode,
+		"direction":        entry.Direction,
 	}
 	if entry.ReturnReason != "" {
 		extra["return_reason"] = entry.ReturnReason
@@ -780,7 +2063,7 @@ func (s *Service) fetchRDFIEntry(ctx context.Context, id string) (*UnifiedAchIte
 		Status:      entry.Status,
 		CreatedAt:   entry.CreatedAt,
 		Extra:       extra,
-	}, nil
+	}
 }
 
 // sortUnifiedAchItemsOptimized sorts unified ACH items using sort.Slice (O(n log n))
@@ -809,6 +2092,8 @@ func sortUnifiedAchItemsOptimized(items []*UnifiedAchItem, sortBy, sortOrder str
 			less = items[i].TraceNumber < items[j].TraceNumber
 		case "side":
 			less = items[i].Side < items[j].Side
+		case "settlement_date":
+			less = items[i].SettlementDate < items[j].SettlementDate
 		default:
 			less = items[i].CreatedAt < items[j].CreatedAt
 		}
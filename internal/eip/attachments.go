@@ -0,0 +1,71 @@
+package eip
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultMaxAttachmentBytes caps an attachment when ATTACHMENT_MAX_BYTES is not set (10 MiB)
+const DefaultMaxAttachmentBytes int64 = 10 << 20
+
+// AllowedAttachmentTypes are the content types evidence may be uploaded as. The type is
+// sniffed from the file's first bytes; the type the client declares is ignored.
+var AllowedAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/gif":       true,
+	"text/plain":      true,
+}
+
+// Attachment errors
+var (
+	ErrAttachmentEmpty    = errors.New("attachment is empty")
+	ErrAttachmentTooLarge = errors.New("attachment exceeds the size limit")
+	ErrAttachmentType     = errors.New("attachment content type is not allowed")
+)
+
+// MaxAttachmentBytesFromEnv reads ATTACHMENT_MAX_BYTES, falling back to DefaultMaxAttachmentBytes
+func MaxAttachmentBytesFromEnv() int64 {
+	if value := os.Getenv("ATTACHMENT_MAX_BYTES"); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return DefaultMaxAttachmentBytes
+}
+
+// sniffContentType detects the media type of head, dropping parameters such as charset
+func sniffContentType(head []byte) string {
+	mediaType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	return strings.TrimSpace(mediaType)
+}
+
+// cleanFilename reduces a client-supplied filename to a safe base name
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		name = "attachment"
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
+
+// attachmentKey is where an attachment's bytes live in the blob store
+func attachmentKey(caseID, attachmentID string) string {
+	return "cases/" + caseID + "/attachments/" + attachmentID
+}
@@ -0,0 +1,99 @@
+package eip
+
+import (
+	"errors"
+	"time"
+
+	"ach-concourse/internal/common/calendar"
+)
+
+// Dispute tracks a CUSTOMER_DISPUTE case through the unauthorized-debit workflow: the
+// customer's written statement is captured, then the dispute is either approved, which
+// returns the RDFI entry, or denied.
+type Dispute struct {
+	State               string     `json:"state"`
+	CustomerName        string     `json:"customer_name"`
+	Statement           string     `json:"statement"`
+	SignedOn            string     `json:"signed_on"`
+	StatementReceivedAt *time.Time `json:"statement_received_at,omitempty"`
+	EntryDate           string     `json:"entry_date"`
+	WindowEndsOn        string     `json:"window_ends_on"`
+	ReturnReason        string     `json:"return_reason,omitempty"`
+	ReturnedAt          *time.Time `json:"returned_at,omitempty"`
+	// LastError is why the most recent return attempt failed; it clears on the next attempt
+	LastError string `json:"last_error,omitempty"`
+}
+
+// DisputeStatementRequest records the customer's written statement of unauthorized debit
+type DisputeStatementRequest struct {
+	CustomerName string `json:"customer_name"`
+	Statement    string `json:"statement"`
+	// SignedOn is the YYYY-MM-DD date the customer signed the statement
+	SignedOn   string `json:"signed_on"`
+	ReceivedBy string `json:"received_by"`
+}
+
+// ApproveDisputeRequest approves a dispute and returns the entry with ReturnReason, R10 by
+// default. R05 is for a consumer account debited under a corporate SEC code.
+type ApproveDisputeRequest struct {
+	ReturnReason string `json:"return_reason,omitempty"`
+	ApprovedBy   string `json:"approved_by"`
+}
+
+// DenyDisputeRequest denies a dispute, resolving the case without returning the entry
+type DenyDisputeRequest struct {
+	Summary  string `json:"summary"`
+	DeniedBy string `json:"denied_by"`
+}
+
+// Dispute state constants. A case enters STATEMENT_RECEIVED when the statement is captured
+// and RETURN_PENDING while the RDFI return is in flight. A failed return is compensated back
+// to STATEMENT_RECEIVED, so RETURNED and DENIED are the only final states.
+const (
+	DisputeStatementReceived = "STATEMENT_RECEIVED"
+	DisputeReturnPending     = "RETURN_PENDING"
+	DisputeReturned          = "RETURNED"
+	DisputeDenied            = "DENIED"
+)
+
+// Unauthorized-debit return reason codes
+const (
+	ReturnReasonUnauthorized          = "R10"
+	ReturnReasonCorporateUnauthorized = "R05"
+)
+
+// DisputeWindowDays is how many calendar days after the entry a consumer may report an
+// unauthorized debit and the RDFI may still return it
+const DisputeWindowDays = 60
+
+// ErrDisputeState is returned when a dispute action does not apply to the case's current
+// dispute state
+var ErrDisputeState = errors.New("dispute action not allowed in current state")
+
+// ErrDisputeWindow is returned once the dispute window for the entry has closed
+var ErrDisputeWindow = errors.New("dispute window has closed")
+
+// ErrRDFIUnavailable is returned when the RDFI service is not configured or cannot be reached
+var ErrRDFIUnavailable = errors.New("RDFI service unavailable")
+
+// ErrDisputeReturnFailed is returned when RDFI refused the return; the case was compensated
+// back to STATEMENT_RECEIVED
+var ErrDisputeReturnFailed = errors.New("RDFI return failed")
+
+// disputeReturnTimeout is how long a return may stay RETURN_PENDING before another approval
+// may take it over. It is well beyond the RDFI calls of one attempt, so only an approval
+// that was interrupted is taken over.
+const disputeReturnTimeout = 2 * time.Minute
+
+// disputeWorkflowAuthor signs the comments the workflow leaves when compensating a failed return
+const disputeWorkflowAuthor = "dispute-workflow"
+
+// disputeWindow returns the entry's settlement date and the last day it can be disputed.
+// Entries recorded before RDFI kept settlement dates fall back to the day they arrived.
+func disputeWindow(entry *RDFIEntry) (entryDate, windowEndsOn time.Time) {
+	entryDate, err := calendar.ParseDate(entry.SettlementDate)
+	if err != nil {
+		entryDate, _ = calendar.ParseDate(calendar.FormatDate(entry.CreatedAt))
+	}
+	return entryDate, entryDate.AddDate(0, 0, DisputeWindowDays)
+}
@@ -0,0 +1,48 @@
+package eip
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Duplicate policy constants, chosen per create with CreateCaseRequest.OnDuplicate. A case
+// duplicates another when both are unresolved and share side, trace number and type.
+const (
+	// DuplicateReject refuses the new case with a 409 carrying the existing one (the default)
+	DuplicateReject = "REJECT"
+	// DuplicateAttach records the new case's notes as a comment on the existing case and
+	// returns it instead of creating a new one
+	DuplicateAttach = "ATTACH"
+)
+
+// duplicateDetectionAuthor signs the comments left when a create attaches to an existing case
+const duplicateDetectionAuthor = "duplicate-detection"
+
+// DuplicateCaseError is returned by CreateCase when an unresolved case already covers the
+// same side, trace number and type
+type DuplicateCaseError struct {
+	Existing *EIPCase
+}
+
+func (e *DuplicateCaseError) Error() string {
+	return fmt.Sprintf("an unresolved %s case for %s trace %s already exists: %s",
+		e.Existing.Type, e.Existing.Side, e.Existing.TraceNumber, e.Existing.ID)
+}
+
+// DuplicateCaseResponse is the 409 body for a rejected duplicate
+type DuplicateCaseResponse struct {
+	Error        string   `json:"error"`
+	ExistingCase *EIPCase `json:"existing_case"`
+}
+
+// MergeRequest represents the request to merge duplicate cases into a surviving case
+type MergeRequest struct {
+	CaseIDs  []string `json:"case_ids"`
+	MergedBy string   `json:"merged_by"`
+}
+
+// ErrMergeConflict is returned when a case cannot take part in a merge in its current state
+var ErrMergeConflict = errors.New("case cannot be merged")
+
+// maxMergeCases bounds how many duplicates one merge consolidates
+const maxMergeCases = 50
@@ -2,30 +2,57 @@ package eip
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"ach-concourse/internal/common/calendar"
 	commonhttp "ach-concourse/internal/common/http"
+	"ach-concourse/internal/common/idempotency"
+	"ach-concourse/internal/common/pagination"
 )
 
 // Handler handles HTTP requests for EIP
 type Handler struct {
 	service *Service
+	keys    *idempotency.Store
 }
 
 // NewHandler creates a new EIP handler
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, keys *idempotency.Store) *Handler {
+	return &Handler{service: service, keys: keys}
 }
 
 // RegisterRoutes registers all EIP routes
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/api/v1/cases", func(r chi.Router) {
-		r.Post("/", h.CreateCase)
+		r.With(idempotency.Middleware(h.keys)).Post("/", h.CreateCase)
 		r.Get("/", h.ListCases)
+		r.Post("/claim", h.ClaimNextCase)
+		r.Get("/sla", h.GetSLASummary)
+		r.Get("/metrics", h.GetCaseMetrics)
 		r.Get("/{id}", h.GetCase)
 		r.Patch("/{id}/status", h.UpdateStatus)
+		r.Post("/{id}/assign", h.AssignCase)
+		r.Post("/{id}/unassign", h.UnassignCase)
+		r.Post("/{id}/merge", h.MergeCases)
+		r.Post("/{id}/comments", h.AddComment)
+		r.Get("/{id}/comments", h.ListComments)
+		r.Post("/{id}/attachments", h.UploadAttachment)
+		r.Get("/{id}/attachments", h.ListAttachments)
+		r.Get("/{id}/attachments/{attachmentID}", h.DownloadAttachment)
+		r.Post("/{id}/dispute/statement", h.RecordDisputeStatement)
+		r.Post("/{id}/dispute/approve", h.ApproveDispute)
+		r.Post("/{id}/dispute/deny", h.DenyDispute)
+	})
+	r.Route("/api/v1/resolutions", func(r chi.Router) {
+		r.Get("/", h.ListResolutionCodes)
+		r.Get("/stats", h.GetResolutionStats)
 	})
 	r.Get("/healthz", h.Health)
 }
@@ -38,29 +65,98 @@ func (h *Handler) CreateCase(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eipCase, err := h.service.CreateCase(r.Context(), &req)
+	eipCase, created, err := h.service.CreateCase(r.Context(), &req)
+	var duplicate *DuplicateCaseError
+	if errors.As(err, &duplicate) {
+		commonhttp.JSON(w, http.StatusConflict, DuplicateCaseResponse{Error: err.Error(), ExistingCase: duplicate.Existing})
+		return
+	}
 	if err != nil {
 		commonhttp.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	// A create attached to an existing case answers 200 with that case
+	if !created {
+		commonhttp.JSON(w, http.StatusOK, eipCase)
+		return
+	}
+
 	commonhttp.JSON(w, http.StatusCreated, eipCase)
 }
 
-// ListCases handles GET /api/v1/cases
+// MergeCases handles POST /api/v1/cases/{id}/merge
+func (h *Handler) MergeCases(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req MergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.MergeCases(r.Context(), id, &req)
+	if errors.Is(err, ErrMergeConflict) {
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// ListCases handles GET /api/v1/cases. Results are paged with limit, cursor, sort_by and
+// sort_order; from and to bound the creation date, both inclusive.
 func (h *Handler) ListCases(w http.ResponseWriter, r *http.Request) {
-	status := r.URL.Query().Get("status")
-	side := r.URL.Query().Get("side")
-	traceNumber := r.URL.Query().Get("trace_number")
+	overdue := false
+	if value := r.URL.Query().Get("overdue"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			commonhttp.Error(w, http.StatusBadRequest, "overdue must be true or false")
+			return
+		}
+		overdue = parsed
+	}
 
-	cases, err := h.service.ListCases(r.Context(), status, side, traceNumber)
+	from, to, err := parseDateRange(r)
 	if err != nil {
-		commonhttp.Error(w, http.StatusInternalServerError, "failed to list cases")
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
 
-	if cases == nil {
-		cases = []*EIPCase{}
+	page, err := pagination.FromQuery(r.URL.Query(), CaseSortFields, "created_at")
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := CaseFilter{
+		Status:      r.URL.Query().Get("status"),
+		Side:        r.URL.Query().Get("side"),
+		EntryID:     r.URL.Query().Get("entry_id"),
+		TraceNumber: r.URL.Query().Get("trace_number"),
+		Assignee:    r.URL.Query().Get("assignee"),
+		Queue:       r.URL.Query().Get("queue"),
+		Overdue:     overdue,
+		CreatedFrom: from,
+		CreatedTo:   to,
+	}
+
+	cases, err := h.service.ListCases(r.Context(), filter, page)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list cases")
+		return
 	}
 
 	commonhttp.JSON(w, http.StatusOK, cases)
@@ -94,8 +190,322 @@ func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	eipCase, err := h.service.UpdateCaseStatus(r.Context(), id, req.Status)
+	eipCase, err := h.service.UpdateCaseStatus(r.Context(), id, &req)
+	if errors.Is(err, ErrDisputeState) || errors.Is(err, ErrMergeConflict) {
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// AssignCase handles POST /api/v1/cases/{id}/assign
+func (h *Handler) AssignCase(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req AssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.AssignCase(r.Context(), id, req.Assignee)
+	h.writeAssignment(w, eipCase, err)
+}
+
+// UnassignCase handles POST /api/v1/cases/{id}/unassign
+func (h *Handler) UnassignCase(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	eipCase, err := h.service.UnassignCase(r.Context(), id)
+	h.writeAssignment(w, eipCase, err)
+}
+
+// writeAssignment writes the result of an assign or unassign
+func (h *Handler) writeAssignment(w http.ResponseWriter, eipCase *EIPCase, err error) {
+	if errors.Is(err, ErrCaseResolved) {
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// ClaimNextCase handles POST /api/v1/cases/claim
+func (h *Handler) ClaimNextCase(w http.ResponseWriter, r *http.Request) {
+	var req ClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.ClaimNextCase(r.Context(), &req)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if eipCase == nil {
+		commonhttp.Error(w, http.StatusNotFound, "no unassigned open cases in queue")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, eipCase)
+}
+
+// AddComment handles POST /api/v1/cases/{id}/comments
+func (h *Handler) AddComment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	comment, err := h.service.AddComment(r.Context(), id, &req)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if comment == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, comment)
+}
+
+// ListComments handles GET /api/v1/cases/{id}/comments
+func (h *Handler) ListComments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	visibility := r.URL.Query().Get("visibility")
+
+	comments, err := h.service.ListComments(r.Context(), id, visibility)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list comments")
+		return
+	}
+
+	if comments == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, comments)
+}
+
+// GetSLASummary handles GET /api/v1/cases/sla
+func (h *Handler) GetSLASummary(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.service.GetSLASummary(r.Context())
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get SLA summary")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, summary)
+}
+
+// multipartMemory is how much of an upload ParseMultipartForm keeps in memory before
+// spilling to a temporary file
+const multipartMemory = 1 << 20
+
+// multipartOverhead allows for the multipart framing and form fields around the file
+const multipartOverhead = 64 << 10
+
+// GetCaseMetrics handles GET /api/v1/cases/metrics
+func (h *Handler) GetCaseMetrics(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := MetricsFilter{
+		Side: r.URL.Query().Get("side"),
+		Type: r.URL.Query().Get("type"),
+		From: from,
+		To:   to,
+	}
+	if err := ValidateMetricsFilter(filter); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	metrics, err := h.service.GetCaseMetrics(r.Context(), filter)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get case metrics")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, metrics)
+}
+
+// UploadAttachment handles POST /api/v1/cases/{id}/attachments, a multipart/form-data upload
+// with a "file" part and an optional "uploaded_by" field
+func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.service.MaxAttachmentBytes()+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			commonhttp.Error(w, http.StatusRequestEntityTooLarge, ErrAttachmentTooLarge.Error())
+			return
+		}
+		commonhttp.Error(w, http.StatusBadRequest, "request must be multipart/form-data")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.AddAttachment(r.Context(), id, file, header.Size, header.Filename, r.FormValue("uploaded_by"))
+	switch {
+	case errors.Is(err, ErrAttachmentTooLarge):
+		commonhttp.Error(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	case errors.Is(err, ErrAttachmentType):
+		commonhttp.Error(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	case errors.Is(err, ErrAttachmentEmpty):
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to store attachment")
+		return
+	}
+
+	if attachment == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, attachment)
+}
+
+// ListAttachments handles GET /api/v1/cases/{id}/attachments
+func (h *Handler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	attachments, err := h.service.ListAttachments(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list attachments")
+		return
+	}
+
+	if attachments == nil {
+		commonhttp.Error(w, http.StatusNotFound, "case not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, attachments)
+}
+
+// DownloadAttachment handles GET /api/v1/cases/{id}/attachments/{attachmentID}, streaming the
+// stored bytes with their SHA-256 in X-Content-SHA256
+func (h *Handler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	attachmentID := chi.URLParam(r, "attachmentID")
+
+	attachment, body, err := h.service.OpenAttachment(r.Context(), id, attachmentID)
 	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to open attachment")
+		return
+	}
+
+	if attachment == nil {
+		commonhttp.Error(w, http.StatusNotFound, "attachment not found")
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-SHA256", attachment.SHA256)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, body)
+}
+
+// RecordDisputeStatement handles POST /api/v1/cases/{id}/dispute/statement
+func (h *Handler) RecordDisputeStatement(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req DisputeStatementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.RecordDisputeStatement(r.Context(), id, &req)
+	h.writeDispute(w, eipCase, err)
+}
+
+// ApproveDispute handles POST /api/v1/cases/{id}/dispute/approve
+func (h *Handler) ApproveDispute(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req ApproveDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.ApproveDispute(r.Context(), id, &req)
+	h.writeDispute(w, eipCase, err)
+}
+
+// DenyDispute handles POST /api/v1/cases/{id}/dispute/deny
+func (h *Handler) DenyDispute(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req DenyDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	eipCase, err := h.service.DenyDispute(r.Context(), id, &req)
+	h.writeDispute(w, eipCase, err)
+}
+
+// writeDispute writes the result of a dispute workflow action. RDFI failures answer 502;
+// the case has already been compensated when the return itself failed.
+func (h *Handler) writeDispute(w http.ResponseWriter, eipCase *EIPCase, err error) {
+	switch {
+	case errors.Is(err, ErrDisputeState):
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, ErrRDFIUnavailable), errors.Is(err, ErrDisputeReturnFailed):
+		commonhttp.Error(w, http.StatusBadGateway, err.Error())
+		return
+	case err != nil:
 		commonhttp.Error(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -108,8 +518,58 @@ func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 	commonhttp.JSON(w, http.StatusOK, eipCase)
 }
 
+// ListResolutionCodes handles GET /api/v1/resolutions
+func (h *Handler) ListResolutionCodes(w http.ResponseWriter, r *http.Request) {
+	commonhttp.JSON(w, http.StatusOK, ResolutionCatalog)
+}
+
+// GetResolutionStats handles GET /api/v1/resolutions/stats
+func (h *Handler) GetResolutionStats(w http.ResponseWriter, r *http.Request) {
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = IntervalWeek
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := ValidateStatsQuery(interval, from, to); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := h.service.GetResolutionStats(r.Context(), interval, r.URL.Query().Get("side"), from, to)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get resolution stats")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, stats)
+}
+
+// parseDateRange reads the optional YYYY-MM-DD from and to query parameters
+func parseDateRange(r *http.Request) (from, to time.Time, err error) {
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		value := r.URL.Query().Get(p.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := calendar.ParseDate(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New(p.name + " must be YYYY-MM-DD")
+		}
+		*p.dst = parsed
+	}
+	return from, to, nil
+}
+
 // Health handles GET /healthz
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	commonhttp.Health(w)
 }
-
@@ -0,0 +1,97 @@
+package eip
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CaseMetrics is the operational picture of the case workload. Open counts and aging are a
+// snapshot as of today; resolution time and throughput cover the from/to range.
+type CaseMetrics struct {
+	AsOf       string             `json:"as_of"`
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	Side       string             `json:"side,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Open       int64              `json:"open"`
+	OpenCounts []CaseStatusCount  `json:"open_counts"`
+	Aging      []AgingBucket      `json:"aging"`
+	Resolution ResolutionTime     `json:"resolution"`
+	Throughput []WeeklyThroughput `json:"throughput"`
+}
+
+// CaseStatusCount is the number of unresolved cases of one type in one status
+type CaseStatusCount struct {
+	Type   string `json:"type"`
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+// AgingBucket counts unresolved cases whose age in calendar days falls within the bucket.
+// MaxDays is omitted for the last, open-ended bucket.
+type AgingBucket struct {
+	Label   string `json:"label"`
+	MinDays int    `json:"min_days"`
+	MaxDays *int   `json:"max_days,omitempty"`
+	Count   int64  `json:"count"`
+}
+
+// ResolutionTime summarizes how long cases resolved in the range took. Merged duplicates are
+// left out, since they resolve when merged rather than when the work is done. The hours are
+// omitted when nothing was resolved.
+type ResolutionTime struct {
+	Resolved             int64    `json:"resolved"`
+	MeanHoursToResolve   *float64 `json:"mean_hours_to_resolve,omitempty"`
+	MedianHoursToResolve *float64 `json:"median_hours_to_resolve,omitempty"`
+}
+
+// WeeklyThroughput counts the cases opened and resolved in the week starting WeekStart
+// (a Monday). The first and last weeks only count days inside the range.
+type WeeklyThroughput struct {
+	WeekStart string `json:"week_start"`
+	Opened    int64  `json:"opened"`
+	Resolved  int64  `json:"resolved"`
+}
+
+// MetricsFilter narrows the cases the metrics are computed over. Zero From/To default to the
+// last 90 days through today.
+type MetricsFilter struct {
+	Side string
+	Type string
+	From time.Time
+	To   time.Time
+}
+
+// agingBuckets are the age ranges, in calendar days since creation, unresolved cases are
+// counted in. The last bucket has no upper bound.
+var agingBuckets = []struct {
+	label   string
+	minDays int
+	maxDays int
+}{
+	{"0-1", 0, 1},
+	{"2-3", 2, 3},
+	{"4-7", 4, 7},
+	{"8-14", 8, 14},
+	{"15-30", 15, 30},
+	{"31+", 31, -1},
+}
+
+// ValidateMetricsFilter checks the side, type and date range of a metrics request
+func ValidateMetricsFilter(filter MetricsFilter) error {
+	if filter.Side != "" && filter.Side != SideODFI && filter.Side != SideRDFI {
+		return errors.New("side must be ODFI or RDFI")
+	}
+	if filter.Type != "" {
+		known := false
+		for _, t := range allCaseTypes {
+			known = known || t == filter.Type
+		}
+		if !known {
+			return fmt.Errorf("type must be one of: %s", strings.Join(allCaseTypes, ", "))
+		}
+	}
+	return ValidateStatsQuery(IntervalWeek, filter.From, filter.To)
+}
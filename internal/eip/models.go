@@ -6,29 +6,122 @@ import (
 
 // EIPCase represents an exception/investigation case
 type EIPCase struct {
+	ID          string     `json:"id"`
+	Side        string     `json:"side"`
+	EntryID     string     `json:"entry_id,omitempty"`
+	TraceNumber string     `json:"trace_number"`
+	Status      string     `json:"status"`
+	Type        string     `json:"type"`
+	Notes       string     `json:"notes"`
+	Queue       string     `json:"queue"`
+	Assignee    string     `json:"assignee,omitempty"`
+	AssignedAt  *time.Time `json:"assigned_at,omitempty"`
+	Priority    string     `json:"priority"`
+	DueDate     string     `json:"due_date,omitempty"`
+	AtRiskDate  string     `json:"at_risk_date,omitempty"`
+	SLAStatus   string     `json:"sla_status"`
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+
+	ResolutionCode    string     `json:"resolution_code,omitempty"`
+	ResolutionSummary string     `json:"resolution_summary,omitempty"`
+	ResolvedBy        string     `json:"resolved_by,omitempty"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+
+	// MergedInto is the surviving case a duplicate was merged into
+	MergedInto string `json:"merged_into,omitempty"`
+
+	// Dispute is set once a CUSTOMER_DISPUTE case enters the dispute workflow
+	Dispute *Dispute `json:"dispute,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// LatestComment is filled in case listings only
+	LatestComment *Comment `json:"latest_comment,omitempty"`
+}
+
+// Comment is an append-only remark on a case
+type Comment struct {
+	ID         string    `json:"id"`
+	CaseID     string    `json:"case_id"`
+	Author     string    `json:"author"`
+	Body       string    `json:"body"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Attachment describes an evidence file stored against a case
+type Attachment struct {
 	ID          string    `json:"id"`
-	Side        string    `json:"side"`
-	TraceNumber string    `json:"trace_number"`
-	Status      string    `json:"status"`
-	Type        string    `json:"type"`
-	Notes       string    `json:"notes"`
+	CaseID      string    `json:"case_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	SHA256      string    `json:"sha256"`
+	UploadedBy  string    `json:"uploaded_by,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	StorageKey  string    `json:"-"`
+}
+
+// CreateCommentRequest represents the request to comment on a case
+type CreateCommentRequest struct {
+	Author     string `json:"author"`
+	Body       string `json:"body"`
+	Visibility string `json:"visibility,omitempty"`
 }
 
 // CreateCaseRequest represents the request to create a case
 type CreateCaseRequest struct {
 	Side        string `json:"side"`
+	EntryID     string `json:"entry_id,omitempty"`
 	TraceNumber string `json:"trace_number"`
 	Type        string `json:"type"`
 	Notes       string `json:"notes"`
+	Queue       string `json:"queue,omitempty"`
+	Assignee    string `json:"assignee,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	// OnDuplicate is REJECT (the default) or ATTACH, for when an unresolved case with the
+	// same side, trace number and type already exists
+	OnDuplicate string `json:"on_duplicate,omitempty"`
 }
 
-// UpdateStatusRequest represents the request to update case status
+// UpdateStatusRequest represents the request to update case status. Moving a case to
+// RESOLVED requires a resolution code from the catalog and a summary.
 type UpdateStatusRequest struct {
-	Status string `json:"status"`
+	Status            string `json:"status"`
+	ResolutionCode    string `json:"resolution_code,omitempty"`
+	ResolutionSummary string `json:"resolution_summary,omitempty"`
+	ResolvedBy        string `json:"resolved_by,omitempty"`
 }
 
+// AssignRequest represents the request to assign a case to an analyst
+type AssignRequest struct {
+	Assignee string `json:"assignee"`
+}
+
+// ClaimRequest represents the request to claim the next unassigned case in a queue
+type ClaimRequest struct {
+	Queue    string `json:"queue"`
+	Assignee string `json:"assignee"`
+}
+
+// CaseFilter narrows a case listing; empty fields match every case
+type CaseFilter struct {
+	Status      string
+	Side        string
+	EntryID     string
+	TraceNumber string
+	Assignee    string
+	Queue       string
+	Overdue     bool
+	// CreatedFrom and CreatedTo bound created_at to [CreatedFrom, CreatedTo) when non-zero
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
+// DefaultQueue is the work queue a case lands in when none is given
+const DefaultQueue = "GENERAL"
+
 // Status constants
 const (
 	StatusOpen       = "OPEN"
@@ -38,9 +131,16 @@ const (
 
 // Type constants
 const (
-	TypeReturnReview     = "RETURN_REVIEW"
-	TypeNOCReview        = "NOC_REVIEW"
-	TypeCustomerDispute  = "CUSTOMER_DISPUTE"
+	TypeReturnReview    = "RETURN_REVIEW"
+	TypeNOCReview       = "NOC_REVIEW"
+	TypeCustomerDispute = "CUSTOMER_DISPUTE"
+)
+
+// Comment visibility constants. INTERNAL comments are for operations staff only; EXTERNAL
+// comments may be shared with the customer or counterparty.
+const (
+	VisibilityInternal = "INTERNAL"
+	VisibilityExternal = "EXTERNAL"
 )
 
 // Side constants
@@ -48,4 +148,3 @@ const (
 	SideODFI = "ODFI"
 	SideRDFI = "RDFI"
 )
-
@@ -0,0 +1,110 @@
+package eip
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"ach-concourse/internal/common/idempotency"
+)
+
+// RDFIEntry is the part of an RDFI entry the dispute workflow reads
+type RDFIEntry struct {
+	ID           string `json:"id"`
+	TraceNumber  string `json:"trace_number"`
+	Direction    string `json:"direction"`
+	Status       string `json:"status"`
+	ReturnReason string `json:"return_reason,omitempty"`
+	// SettlementDate is YYYY-MM-DD; entries from before RDFI recorded it have none
+	SettlementDate string    `json:"settlement_date,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// rdfiStatusReturned is the RDFI status of a returned entry
+const rdfiStatusReturned = "RETURNED"
+
+// RDFIClient reads and returns entries through the RDFI service
+type RDFIClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewRDFIClient creates a client for the RDFI service at baseURL
+func NewRDFIClient(baseURL string) *RDFIClient {
+	return &RDFIClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewRDFIClientFromEnv creates a client using RDFI_BASE_URL. It returns nil when
+// RDFI_BASE_URL is not set, which disables the dispute workflow.
+func NewRDFIClientFromEnv() *RDFIClient {
+	baseURL := os.Getenv("RDFI_BASE_URL")
+	if baseURL == "" {
+		return nil
+	}
+	return NewRDFIClient(baseURL)
+}
+
+// GetEntry fetches an entry, returning nil if RDFI has no such entry
+func (c *RDFIClient) GetEntry(ctx context.Context, id string) (*RDFIEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/entries/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(req)
+}
+
+// ReturnEntry returns an entry with reason. key is sent as the Idempotency-Key, so a retry
+// after a lost response is answered with the original result instead of returning twice.
+func (c *RDFIClient) ReturnEntry(ctx context.Context, id, reason, key string) (*RDFIEntry, error) {
+	body, err := json.Marshal(map[string]string{"reason": reason})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/entries/"+id+"/return", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotency.HeaderKey, key)
+
+	entry, err := c.do(req)
+	if err == nil && entry == nil {
+		return nil, fmt.Errorf("RDFI entry %s not found", id)
+	}
+	return entry, err
+}
+
+// do sends req and decodes the entry in the response. A 404 yields (nil, nil).
+func (c *RDFIClient) do(req *http.Request) (*RDFIEntry, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("RDFI returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var entry RDFIEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -3,12 +3,21 @@ package eip
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"ach-concourse/internal/common/calendar"
+	"ach-concourse/internal/common/pagination"
 )
 
+// ErrCaseResolved is returned when assigning or unassigning a case that is already resolved
+var ErrCaseResolved = errors.New("case is resolved")
+
 // Repository handles database operations for EIP cases
 type Repository struct {
 	db *sql.DB
@@ -35,46 +44,324 @@ CREATE INDEX IF NOT EXISTS idx_eip_cases_side ON eip_cases(side);
 CREATE INDEX IF NOT EXISTS idx_eip_cases_status ON eip_cases(status);
 CREATE INDEX IF NOT EXISTS idx_eip_cases_trace_number ON eip_cases(trace_number);
 CREATE INDEX IF NOT EXISTS idx_eip_cases_type ON eip_cases(type);
+
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS queue TEXT NOT NULL DEFAULT 'GENERAL';
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS assignee TEXT;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_eip_cases_assignee ON eip_cases(assignee);
+CREATE INDEX IF NOT EXISTS idx_eip_cases_queue_unassigned ON eip_cases(queue, created_at) WHERE assignee IS NULL;
+
+CREATE TABLE IF NOT EXISTS eip_case_comments (
+	id UUID PRIMARY KEY,
+	case_id UUID NOT NULL REFERENCES eip_cases(id),
+	author TEXT NOT NULL,
+	body TEXT NOT NULL,
+	visibility TEXT NOT NULL DEFAULT 'INTERNAL',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_eip_case_comments_case ON eip_case_comments(case_id, created_at);
+
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'NORMAL';
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS due_date DATE;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS at_risk_date DATE;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS sla_status TEXT NOT NULL DEFAULT 'ON_TRACK';
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_eip_cases_due_date ON eip_cases(due_date) WHERE status <> 'RESOLVED';
+
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS entry_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_eip_cases_entry_id ON eip_cases(entry_id);
+
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS resolution_code TEXT;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS resolution_summary TEXT;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS resolved_by TEXT;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_eip_cases_resolved_at ON eip_cases(resolved_at) WHERE resolved_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS eip_case_attachments (
+	id UUID PRIMARY KEY,
+	case_id UUID NOT NULL REFERENCES eip_cases(id),
+	filename TEXT NOT NULL,
+	content_type TEXT NOT NULL,
+	size_bytes BIGINT NOT NULL,
+	sha256 TEXT NOT NULL,
+	storage_key TEXT NOT NULL,
+	uploaded_by TEXT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_eip_case_attachments_case ON eip_case_attachments(case_id, created_at);
+
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_state TEXT;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_customer_name TEXT;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_statement TEXT;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_signed_on DATE;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_statement_received_at TIMESTAMPTZ;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_entry_date DATE;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_window_ends_on DATE;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_return_reason TEXT;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_returned_at TIMESTAMPTZ;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_last_error TEXT;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_return_attempt UUID;
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS dispute_return_started_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_eip_cases_dispute_state ON eip_cases(dispute_state) WHERE dispute_state IS NOT NULL;
+
+ALTER TABLE eip_cases ADD COLUMN IF NOT EXISTS merged_into UUID;
+
+CREATE INDEX IF NOT EXISTS idx_eip_cases_merged_into ON eip_cases(merged_into) WHERE merged_into IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_eip_cases_duplicates ON eip_cases(side, trace_number, type) WHERE status <> 'RESOLVED';
+
+-- Every status a case enters, including its first. A reopened case loses its resolved_at,
+-- so throughput and resolution times are counted from here.
+CREATE TABLE IF NOT EXISTS eip_case_status_history (
+	id UUID PRIMARY KEY,
+	case_id UUID NOT NULL REFERENCES eip_cases(id),
+	status TEXT NOT NULL,
+	previous_status TEXT,
+	resolution_code TEXT,
+	changed_by TEXT,
+	changed_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_eip_case_status_history_case ON eip_case_status_history(case_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_eip_case_status_history_resolved ON eip_case_status_history(changed_at) WHERE status = 'RESOLVED';
+
+CREATE OR REPLACE FUNCTION eip_reject_modification() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS eip_case_status_history_append_only ON eip_case_status_history;
+CREATE TRIGGER eip_case_status_history_append_only
+	BEFORE UPDATE OR DELETE ON eip_case_status_history
+	FOR EACH ROW EXECUTE FUNCTION eip_reject_modification();
+
+DROP TRIGGER IF EXISTS eip_case_comments_append_only ON eip_case_comments;
+CREATE TRIGGER eip_case_comments_append_only
+	BEFORE UPDATE OR DELETE ON eip_case_comments
+	FOR EACH ROW EXECUTE FUNCTION eip_reject_modification();
+
+-- Cases from before the history start it with their creation and, if resolved, their
+-- current resolution
+INSERT INTO eip_case_status_history (id, case_id, status, resolution_code, changed_by, changed_at)
+SELECT gen_random_uuid(), c.id, v.status, v.resolution_code, v.changed_by, v.changed_at
+FROM eip_cases c
+CROSS JOIN LATERAL (VALUES
+	('OPEN', NULL, NULL, c.created_at),
+	('RESOLVED', c.resolution_code, c.resolved_by, c.resolved_at)
+) AS v(status, resolution_code, changed_by, changed_at)
+WHERE v.changed_at IS NOT NULL
+	AND NOT EXISTS (SELECT 1 FROM eip_case_status_history h WHERE h.case_id = c.id);
 `
 
+// caseColumns is the column list shared by every case query, in scanCase order
+const caseColumns = `id, side, entry_id::TEXT, trace_number, status, type, notes, queue, assignee, assigned_at,
+	priority, to_char(due_date, 'YYYY-MM-DD'), to_char(at_risk_date, 'YYYY-MM-DD'), sla_status, escalated_at,
+	resolution_code, resolution_summary, resolved_by, resolved_at,
+	dispute_state, dispute_customer_name, dispute_statement, to_char(dispute_signed_on, 'YYYY-MM-DD'),
+	dispute_statement_received_at, to_char(dispute_entry_date, 'YYYY-MM-DD'), to_char(dispute_window_ends_on, 'YYYY-MM-DD'),
+	dispute_return_reason, dispute_returned_at, dispute_last_error, merged_into::TEXT,
+	created_at, updated_at`
+
+// threadCaseIDs selects the given case and every case merged into it, directly or through
+// an earlier merge, so a survivor's comment thread includes its duplicates' comments
+func threadCaseIDs(caseRef string) string {
+	return `WITH RECURSIVE thread(id) AS (
+		SELECT ` + caseRef + `
+		UNION
+		SELECT m.id FROM eip_cases m JOIN thread ON m.merged_into = thread.id
+	) SELECT id FROM thread`
+}
+
 // GetSchema returns the SQL schema for EIP tables
 func GetSchema() string {
 	return schema
 }
 
-// Create creates a new EIP case
-func (r *Repository) Create(ctx context.Context, eipCase *EIPCase) error {
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanCase scans a row selected with caseColumns into an EIPCase. Columns selected after
+// caseColumns are scanned into extra.
+func scanCase(row rowScanner, extra ...any) (*EIPCase, error) {
+	eipCase := &EIPCase{}
+	var entryID, traceNumber, notes, assignee, dueDate, atRiskDate sql.NullString
+	var resolutionCode, resolutionSummary, resolvedBy sql.NullString
+	var assignedAt, escalatedAt, resolvedAt sql.NullTime
+	var disputeState, customerName, statement, signedOn, entryDate, windowEndsOn sql.NullString
+	var returnReason, lastError, mergedInto sql.NullString
+	var statementReceivedAt, returnedAt sql.NullTime
+
+	dest := []any{
+		&eipCase.ID, &eipCase.Side, &entryID, &traceNumber,
+		&eipCase.Status, &eipCase.Type, &notes,
+		&eipCase.Queue, &assignee, &assignedAt,
+		&eipCase.Priority, &dueDate, &atRiskDate, &eipCase.SLAStatus, &escalatedAt,
+		&resolutionCode, &resolutionSummary, &resolvedBy, &resolvedAt,
+		&disputeState, &customerName, &statement, &signedOn,
+		&statementReceivedAt, &entryDate, &windowEndsOn,
+		&returnReason, &returnedAt, &lastError, &mergedInto,
+		&eipCase.CreatedAt, &eipCase.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	eipCase.EntryID = entryID.String
+	eipCase.TraceNumber = traceNumber.String
+	eipCase.Notes = notes.String
+	eipCase.Assignee = assignee.String
+	if assignedAt.Valid {
+		eipCase.AssignedAt = &assignedAt.Time
+	}
+	eipCase.DueDate = dueDate.String
+	eipCase.AtRiskDate = atRiskDate.String
+	if escalatedAt.Valid {
+		eipCase.EscalatedAt = &escalatedAt.Time
+	}
+	eipCase.ResolutionCode = resolutionCode.String
+	eipCase.ResolutionSummary = resolutionSummary.String
+	eipCase.ResolvedBy = resolvedBy.String
+	if resolvedAt.Valid {
+		eipCase.ResolvedAt = &resolvedAt.Time
+	}
+	eipCase.MergedInto = mergedInto.String
+	if disputeState.Valid {
+		eipCase.Dispute = &Dispute{
+			State:        disputeState.String,
+			CustomerName: customerName.String,
+			Statement:    statement.String,
+			SignedOn:     signedOn.String,
+			EntryDate:    entryDate.String,
+			WindowEndsOn: windowEndsOn.String,
+			ReturnReason: returnReason.String,
+			LastError:    lastError.String,
+		}
+		if statementReceivedAt.Valid {
+			eipCase.Dispute.StatementReceivedAt = &statementReceivedAt.Time
+		}
+		if returnedAt.Valid {
+			eipCase.Dispute.ReturnedAt = &returnedAt.Time
+		}
+	}
+
+	return eipCase, nil
+}
+
+// nullString maps an empty string to NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// Create creates a new EIP case unless an unresolved case with the same side, trace number
+// and type already exists, in which case nothing is inserted and the existing case is
+// returned. Concurrent creates of the same case are serialized by an advisory lock, so only
+// one of them inserts.
+func (r *Repository) Create(ctx context.Context, eipCase *EIPCase) (*EIPCase, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if eipCase.TraceNumber != "" {
+		key := eipCase.Side + "|" + eipCase.TraceNumber + "|" + eipCase.Type
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, key); err != nil {
+			return nil, err
+		}
+
+		existing, err := scanCase(tx.QueryRowContext(ctx, `
+			SELECT `+caseColumns+` FROM eip_cases
+			WHERE side = $1 AND trace_number = $2 AND type = $3 AND status <> 'RESOLVED'
+			ORDER BY created_at, id
+			LIMIT 1
+		`, eipCase.Side, eipCase.TraceNumber, eipCase.Type))
+		if err == nil {
+			return existing, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+	}
+
 	eipCase.ID = uuid.New().String()
 	eipCase.CreatedAt = time.Now()
 	eipCase.UpdatedAt = time.Now()
+	if eipCase.Assignee != "" {
+		assignedAt := eipCase.CreatedAt
+		eipCase.AssignedAt = &assignedAt
+	}
 
 	query := `
-		INSERT INTO eip_cases (id, side, trace_number, status, type, notes, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO eip_cases (id, side, entry_id, trace_number, status, type, notes, queue, assignee, assigned_at,
+			priority, due_date, at_risk_date, sla_status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		eipCase.ID, eipCase.Side, eipCase.TraceNumber,
+	_, err = tx.ExecContext(ctx, query,
+		eipCase.ID, eipCase.Side, nullString(eipCase.EntryID), eipCase.TraceNumber,
 		eipCase.Status, eipCase.Type, eipCase.Notes,
+		eipCase.Queue, nullString(eipCase.Assignee), eipCase.AssignedAt,
+		eipCase.Priority, nullString(eipCase.DueDate), nullString(eipCase.AtRiskDate), eipCase.SLAStatus,
 		eipCase.CreatedAt, eipCase.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordStatusChange(ctx, tx, eipCase, "", eipCase.Assignee); err != nil {
+		return nil, err
+	}
+
+	return nil, tx.Commit()
+}
+
+// recordStatusChange appends the status eipCase has just entered to its history, unless it
+// is the previous status. A new case has no previous status.
+func recordStatusChange(ctx context.Context, tx *sql.Tx, eipCase *EIPCase, previous, changedBy string) error {
+	if eipCase.Status == previous {
+		return nil
+	}
+
+	var resolutionCode string
+	if eipCase.Status == StatusResolved {
+		resolutionCode = eipCase.ResolutionCode
+	}
 
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO eip_case_status_history (id, case_id, status, previous_status, resolution_code, changed_by, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, uuid.New().String(), eipCase.ID, eipCase.Status, nullString(previous),
+		nullString(resolutionCode), nullString(changedBy), eipCase.UpdatedAt)
 	return err
 }
 
+// lockCaseStatus locks a case for a status change and returns its current status and
+// whether it was merged into another case. found is false when the case does not exist.
+func lockCaseStatus(ctx context.Context, tx *sql.Tx, id string) (status string, merged, found bool, err error) {
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, merged_into IS NOT NULL FROM eip_cases WHERE id = $1 FOR UPDATE`, id).Scan(&status, &merged)
+	if err == sql.ErrNoRows {
+		return "", false, false, nil
+	}
+	if err != nil {
+		return "", false, false, err
+	}
+	return status, merged, true, nil
+}
+
 // GetByID retrieves an EIP case by ID
 func (r *Repository) GetByID(ctx context.Context, id string) (*EIPCase, error) {
-	query := `
-		SELECT id, side, trace_number, status, type, notes, created_at, updated_at
-		FROM eip_cases
-		WHERE id = $1
-	`
-
-	eipCase := &EIPCase{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&eipCase.ID, &eipCase.Side, &eipCase.TraceNumber,
-		&eipCase.Status, &eipCase.Type, &eipCase.Notes,
-		&eipCase.CreatedAt, &eipCase.UpdatedAt)
+	query := `SELECT ` + caseColumns + ` FROM eip_cases WHERE id = $1`
 
+	eipCase, err := scanCase(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -85,73 +372,678 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*EIPCase, error) {
 	return eipCase, nil
 }
 
-// List retrieves EIP cases with optional filters
-func (r *Repository) List(ctx context.Context, status, side, traceNumber string) ([]*EIPCase, error) {
-	query := `
-		SELECT id, side, trace_number, status, type, notes, created_at, updated_at
-		FROM eip_cases
-		WHERE 1=1
-	`
+// CaseSortFields are the columns a case listing can be sorted by. Cases without a due date
+// sort after every dated case.
+var CaseSortFields = map[string]pagination.Field{
+	"created_at": {Column: "c.created_at", Type: "TIMESTAMPTZ"},
+	"updated_at": {Column: "c.updated_at", Type: "TIMESTAMPTZ"},
+	"due_date":   {Column: "COALESCE(c.due_date, 'infinity'::DATE)", Type: "DATE"},
+}
+
+// caseFilters builds the optional conditions of a case listing on eip_cases c, numbering
+// placeholders from argNum
+func caseFilters(filter CaseFilter, argNum int) (string, []interface{}, int) {
+	clause := ""
 	args := []interface{}{}
-	argNum := 1
 
-	if status != "" {
-		query += fmt.Sprintf(" AND status = $%d", argNum)
-		args = append(args, status)
+	if filter.Status != "" {
+		clause += fmt.Sprintf(" AND c.status = $%d", argNum)
+		args = append(args, filter.Status)
 		argNum++
 	}
 
-	if side != "" {
-		query += fmt.Sprintf(" AND side = $%d", argNum)
-		args = append(args, side)
+	if filter.Side != "" {
+		clause += fmt.Sprintf(" AND c.side = $%d", argNum)
+		args = append(args, filter.Side)
 		argNum++
 	}
 
-	if traceNumber != "" {
-		query += fmt.Sprintf(" AND trace_number = $%d", argNum)
-		args = append(args, traceNumber)
+	if filter.EntryID != "" {
+		clause += fmt.Sprintf(" AND c.entry_id::TEXT = $%d", argNum)
+		args = append(args, filter.EntryID)
 		argNum++
 	}
 
-	query += " ORDER BY created_at DESC"
+	if filter.TraceNumber != "" {
+		clause += fmt.Sprintf(" AND c.trace_number = $%d", argNum)
+		args = append(args, filter.TraceNumber)
+		argNum++
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	if filter.Assignee != "" {
+		clause += fmt.Sprintf(" AND c.assignee = $%d", argNum)
+		args = append(args, filter.Assignee)
+		argNum++
+	}
+
+	if filter.Queue != "" {
+		clause += fmt.Sprintf(" AND c.queue = $%d", argNum)
+		args = append(args, filter.Queue)
+		argNum++
+	}
+
+	// Overdue is judged against today rather than the last sweep, so the list is never stale
+	if filter.Overdue {
+		clause += fmt.Sprintf(" AND c.status <> 'RESOLVED' AND c.due_date < $%d", argNum)
+		args = append(args, calendar.FormatDate(calendar.Today()))
+		argNum++
+	}
+
+	if !filter.CreatedFrom.IsZero() {
+		clause += fmt.Sprintf(" AND c.created_at >= $%d", argNum)
+		args = append(args, filter.CreatedFrom)
+		argNum++
+	}
+
+	if !filter.CreatedTo.IsZero() {
+		clause += fmt.Sprintf(" AND c.created_at < $%d", argNum)
+		args = append(args, filter.CreatedTo)
+		argNum++
+	}
+
+	return clause, args, argNum
+}
+
+// List retrieves a page of EIP cases with optional filters, each with its most recent
+// comment. The total across all pages is counted when page.IncludeTotal is set.
+func (r *Repository) List(ctx context.Context, filter CaseFilter, page pagination.Params) (*pagination.Page[*EIPCase], error) {
+	clause, args, argNum := caseFilters(filter, 1)
+
+	query, pageArgs := page.Query(`
+		SELECT `+caseColumns+`,
+			lc.comment_id, lc.comment_author, lc.comment_body, lc.comment_visibility, lc.comment_created_at,
+			`+page.SortKey(CaseSortFields)+`
+		FROM eip_cases c
+		LEFT JOIN LATERAL (
+			SELECT cc.id AS comment_id, cc.author AS comment_author, cc.body AS comment_body,
+				cc.visibility AS comment_visibility, cc.created_at AS comment_created_at
+			FROM eip_case_comments cc
+			WHERE cc.case_id IN (`+threadCaseIDs("c.id")+`)
+			ORDER BY cc.created_at DESC, cc.id DESC
+			LIMIT 1
+		) lc ON TRUE
+		WHERE 1=1`+clause, CaseSortFields, "c.id", argNum)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, pageArgs...)...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	var cases []*EIPCase
+	var keys, ids []string
 	for rows.Next() {
-		eipCase := &EIPCase{}
-		err := rows.Scan(
-			&eipCase.ID, &eipCase.Side, &eipCase.TraceNumber,
-			&eipCase.Status, &eipCase.Type, &eipCase.Notes,
-			&eipCase.CreatedAt, &eipCase.UpdatedAt)
+		var commentID, author, body, visibility sql.NullString
+		var commentedAt sql.NullTime
+		var key string
+		eipCase, err := scanCase(rows, &commentID, &author, &body, &visibility, &commentedAt, &key)
 		if err != nil {
 			return nil, err
 		}
+		if commentID.Valid {
+			eipCase.LatestComment = &Comment{
+				ID:         commentID.String,
+				CaseID:     eipCase.ID,
+				Author:     author.String,
+				Body:       body.String,
+				Visibility: visibility.String,
+				CreatedAt:  commentedAt.Time,
+			}
+		}
 		cases = append(cases, eipCase)
+		keys = append(keys, key)
+		ids = append(ids, eipCase.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return cases, rows.Err()
+	result := &pagination.Page[*EIPCase]{}
+	result.Data, result.NextCursor = pagination.Trim(page, cases, keys, ids)
+	if result.Data == nil {
+		result.Data = []*EIPCase{}
+	}
+
+	if page.IncludeTotal {
+		var total int64
+		if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM eip_cases c WHERE 1=1`+clause, args...).Scan(&total); err != nil {
+			return nil, err
+		}
+		result.TotalCount = &total
+	}
+
+	return result, nil
 }
 
-// UpdateStatus updates the status of an EIP case
-func (r *Repository) UpdateStatus(ctx context.Context, id, status string) (*EIPCase, error) {
+// UpdateStatus updates the status of an EIP case. The resolution is recorded when the case
+// is resolved; any other status clears a previous resolution. It returns (nil, nil) when the
+// case does not exist and ErrMergeConflict when it was merged into another case.
+func (r *Repository) UpdateStatus(ctx context.Context, id, status string, resolution *Resolution) (*EIPCase, error) {
+	if resolution == nil {
+		resolution = &Resolution{}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	previous, merged, found, err := lockCaseStatus(ctx, tx, id)
+	if err != nil || !found {
+		return nil, err
+	}
+	if merged {
+		return nil, fmt.Errorf("%w: case %s was merged into another case", ErrMergeConflict, id)
+	}
+
 	query := `
 		UPDATE eip_cases
-		SET status = $1, updated_at = $2
+		SET status = $1, updated_at = $2,
+			resolution_code = $4, resolution_summary = $5, resolved_by = $6,
+			resolved_at = CASE WHEN $4::TEXT IS NULL THEN NULL ELSE $2 END
 		WHERE id = $3
-		RETURNING id, side, trace_number, status, type, notes, created_at, updated_at
+		RETURNING ` + caseColumns
+
+	eipCase, err := scanCase(tx.QueryRowContext(ctx, query, status, time.Now(), id,
+		nullString(resolution.Code), nullString(resolution.Summary), nullString(resolution.ResolvedBy)))
+	if err != nil {
+		return nil, err
+	}
+	if err := recordStatusChange(ctx, tx, eipCase, previous, resolution.ResolvedBy); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return eipCase, nil
+}
+
+// SetAssignee assigns an unresolved case to assignee, or unassigns it when assignee is empty.
+// It returns (nil, nil) when the case does not exist and ErrCaseResolved when it is resolved.
+func (r *Repository) SetAssignee(ctx context.Context, id, assignee string) (*EIPCase, error) {
+	query := `
+		UPDATE eip_cases
+		SET assignee = $2,
+			assigned_at = CASE WHEN $2::TEXT IS NULL THEN NULL ELSE NOW() END,
+			updated_at = NOW()
+		WHERE id = $1 AND status <> 'RESOLVED'
+		RETURNING ` + caseColumns
+
+	eipCase, err := scanCase(r.db.QueryRowContext(ctx, query, id, nullString(assignee)))
+	if err != sql.ErrNoRows {
+		return eipCase, err
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM eip_cases WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrCaseResolved
+	}
+	return nil, nil
+}
+
+// ClaimNext assigns the oldest open, unassigned case in queue to assignee and moves it to
+// IN_PROGRESS. Rows locked by a concurrent claim are skipped, so two analysts claiming at
+// once never receive the same case. It returns (nil, nil) when the queue has nothing to claim.
+func (r *Repository) ClaimNext(ctx context.Context, queue, assignee string) (*EIPCase, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		SELECT id FROM eip_cases
+		WHERE queue = $1 AND assignee IS NULL AND status = 'OPEN'
+		ORDER BY created_at, id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`, queue).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	eipCase, err := scanCase(tx.QueryRowContext(ctx, `
+		UPDATE eip_cases
+		SET assignee = $2, assigned_at = NOW(), status = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+caseColumns, id, assignee, StatusInProgress))
+	if err != nil {
+		return nil, err
+	}
+	if err := recordStatusChange(ctx, tx, eipCase, StatusOpen, assignee); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return eipCase, nil
+}
+
+// AddComment appends a comment to a case and touches the case's updated_at. It returns
+// false when the case does not exist.
+func (r *Repository) AddComment(ctx context.Context, comment *Comment) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	comment.ID = uuid.New().String()
+	comment.CreatedAt = time.Now()
+
+	result, err := tx.ExecContext(ctx, `UPDATE eip_cases SET updated_at = $2 WHERE id = $1`, comment.CaseID, comment.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO eip_case_comments (id, case_id, author, body, visibility, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, comment.ID, comment.CaseID, comment.Author, comment.Body, comment.Visibility, comment.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// ListComments returns a case's comments oldest first, optionally narrowed to one visibility.
+// Comments on cases merged into it are included; each keeps the case_id it was written on.
+func (r *Repository) ListComments(ctx context.Context, caseID, visibility string) ([]*Comment, error) {
+	query := `
+		SELECT id, case_id, author, body, visibility, created_at
+		FROM eip_case_comments
+		WHERE case_id IN (` + threadCaseIDs("$1::UUID") + `)
 	`
+	args := []interface{}{caseID}
+	if visibility != "" {
+		query += " AND visibility = $2"
+		args = append(args, visibility)
+	}
+	query += " ORDER BY created_at, id"
 
-	eipCase := &EIPCase{}
-	err := r.db.QueryRowContext(ctx, query, status, time.Now(), id).Scan(
-		&eipCase.ID, &eipCase.Side, &eipCase.TraceNumber,
-		&eipCase.Status, &eipCase.Type, &eipCase.Notes,
-		&eipCase.CreatedAt, &eipCase.UpdatedAt)
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*Comment
+	for rows.Next() {
+		comment := &Comment{}
+		err := rows.Scan(&comment.ID, &comment.CaseID, &comment.Author, &comment.Body, &comment.Visibility, &comment.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// ListMissingDueDates returns unresolved cases that predate SLA tracking and have no due date
+func (r *Repository) ListMissingDueDates(ctx context.Context) ([]*EIPCase, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+caseColumns+` FROM eip_cases
+		WHERE due_date IS NULL AND status <> 'RESOLVED'
+		ORDER BY created_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cases []*EIPCase
+	for rows.Next() {
+		eipCase, err := scanCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, eipCase)
+	}
+
+	return cases, rows.Err()
+}
+
+// SetDueDates stores a case's SLA due date and at-risk date unless it already has them
+func (r *Repository) SetDueDates(ctx context.Context, id, dueDate, atRiskDate string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE eip_cases SET due_date = $2, at_risk_date = $3
+		WHERE id = $1 AND due_date IS NULL
+	`, id, dueDate, atRiskDate)
+	return err
+}
+
+// EscalateSLA re-evaluates every unresolved case against today and escalates those whose
+// standing worsened: at-risk cases are raised to HIGH priority, overdue cases to URGENT and
+// moved to EscalationQueue. Each escalation leaves an internal comment. Concurrent sweeps
+// are safe: a row already escalated by another sweep no longer matches the update.
+func (r *Repository) EscalateSLA(ctx context.Context, today string) ([]Escalation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		WITH evaluated AS (
+			SELECT id,
+				CASE WHEN due_date < $1 THEN 'OVERDUE'
+					WHEN at_risk_date <= $1 THEN 'AT_RISK'
+					ELSE 'ON_TRACK' END AS next_status
+			FROM eip_cases
+			WHERE status <> 'RESOLVED' AND due_date IS NOT NULL
+		)
+		UPDATE eip_cases c
+		SET sla_status = e.next_status,
+			priority = CASE WHEN e.next_status = 'OVERDUE' THEN $2
+				WHEN c.priority = $3 THEN $4
+				ELSE c.priority END,
+			queue = CASE WHEN e.next_status = 'OVERDUE' THEN $5 ELSE c.queue END,
+			escalated_at = NOW(),
+			updated_at = NOW()
+		FROM evaluated e
+		WHERE c.id = e.id
+			AND c.sla_status <> e.next_status
+			AND (e.next_status = 'OVERDUE' OR (e.next_status = 'AT_RISK' AND c.sla_status = 'ON_TRACK'))
+		RETURNING c.id, e.next_status, to_char(c.due_date, 'YYYY-MM-DD')
+	`, today, PriorityUrgent, PriorityNormal, PriorityHigh, EscalationQueue)
+	if err != nil {
+		return nil, err
+	}
+
+	var escalations []Escalation
+	for rows.Next() {
+		var e Escalation
+		if err := rows.Scan(&e.CaseID, &e.SLAStatus, &e.DueDate); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		escalations = append(escalations, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, e := range escalations {
+		body := fmt.Sprintf("Case is at risk of missing its SLA due date %s; priority raised.", e.DueDate)
+		if e.SLAStatus == SLAOverdue {
+			body = fmt.Sprintf("Case missed its SLA due date %s; priority set to %s and moved to %s.", e.DueDate, PriorityUrgent, EscalationQueue)
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO eip_case_comments (id, case_id, author, body, visibility, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, uuid.New().String(), e.CaseID, slaSweeperAuthor, body, VisibilityInternal, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return escalations, tx.Commit()
+}
+
+// GetSLASummary counts unresolved cases by type and SLA standing as of today. Cases without a
+// due date count as on track.
+func (r *Repository) GetSLASummary(ctx context.Context, today string) ([]SLATypeSummary, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT type,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE due_date IS NULL OR (due_date >= $1 AND at_risk_date > $1)),
+			COUNT(*) FILTER (WHERE due_date >= $1 AND at_risk_date <= $1),
+			COUNT(*) FILTER (WHERE due_date < $1),
+			COUNT(*) FILTER (WHERE due_date = $1)
+		FROM eip_cases
+		WHERE status <> 'RESOLVED'
+		GROUP BY type
+		ORDER BY type
+	`, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []SLATypeSummary
+	for rows.Next() {
+		var t SLATypeSummary
+		if err := rows.Scan(&t.Type, &t.Open, &t.OnTrack, &t.AtRisk, &t.Overdue, &t.DueToday); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, t)
+	}
+
+	return summaries, rows.Err()
+}
+
+// GetResolutionStats counts the resolutions in [from, to) recorded in the status history by
+// period, type and resolution code; a case reopened and resolved again counts each time.
+// Periods are truncated in Eastern time; side narrows the cases when given.
+func (r *Repository) GetResolutionStats(ctx context.Context, from, to time.Time, interval, side string) ([]ResolutionCount, error) {
+	query := `
+		SELECT to_char(date_trunc($3, h.changed_at AT TIME ZONE 'America/New_York'), 'YYYY-MM-DD') AS period_start,
+			type, h.resolution_code, COUNT(*),
+			AVG(EXTRACT(EPOCH FROM h.changed_at - c.created_at)) / 3600
+		FROM eip_case_status_history h
+		JOIN eip_cases c ON c.id = h.case_id
+		WHERE h.status = 'RESOLVED' AND h.resolution_code IS NOT NULL
+			AND h.changed_at >= $1 AND h.changed_at < $2
+	`
+	args := []interface{}{from, to, interval}
+	if side != "" {
+		query += " AND side = $4"
+		args = append(args, side)
+	}
+	query += " GROUP BY 1, 2, 3 ORDER BY 1, 2, 3"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []ResolutionCount
+	for rows.Next() {
+		var c ResolutionCount
+		if err := rows.Scan(&c.PeriodStart, &c.Type, &c.ResolutionCode, &c.Count, &c.MeanHoursToResolve); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// metricsFilters builds the side and type conditions of a metrics query, numbering
+// placeholders from argNum
+func metricsFilters(side, caseType string, argNum int) (string, []interface{}) {
+	clause := ""
+	args := []interface{}{}
+
+	if side != "" {
+		clause += fmt.Sprintf(" AND side = $%d", argNum)
+		args = append(args, side)
+		argNum++
+	}
+
+	if caseType != "" {
+		clause += fmt.Sprintf(" AND type = $%d", argNum)
+		args = append(args, caseType)
+	}
+
+	return clause, args
+}
+
+// GetCaseMetrics computes the open counts and aging of unresolved cases as of today, and the
+// resolution time and weekly throughput of cases in [from, to). Resolutions are read from the
+// status history, so a case reopened and resolved again counts each time. Ages and weeks are
+// reckoned in Eastern time; side and caseType narrow the cases when given.
+func (r *Repository) GetCaseMetrics(ctx context.Context, today string, from, to time.Time, side, caseType string) (*CaseMetrics, error) {
+	metrics := &CaseMetrics{}
+
+	clause, args := metricsFilters(side, caseType, 1)
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT type, status, COUNT(*)
+		FROM eip_cases
+		WHERE status <> 'RESOLVED'`+clause+`
+		GROUP BY type, status
+		ORDER BY type, status
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c CaseStatusCount
+		if err := rows.Scan(&c.Type, &c.Status, &c.Count); err != nil {
+			return nil, err
+		}
+		metrics.OpenCounts = append(metrics.OpenCounts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	buckets := make([]string, len(agingBuckets))
+	counts := make([]interface{}, len(agingBuckets))
+	metrics.Aging = make([]AgingBucket, len(agingBuckets))
+	for i, b := range agingBuckets {
+		metrics.Aging[i] = AgingBucket{Label: b.label, MinDays: b.minDays}
+		if b.maxDays < 0 {
+			buckets[i] = fmt.Sprintf("COUNT(*) FILTER (WHERE age >= %d)", b.minDays)
+		} else {
+			maxDays := b.maxDays
+			metrics.Aging[i].MaxDays = &maxDays
+			buckets[i] = fmt.Sprintf("COUNT(*) FILTER (WHERE age BETWEEN %d AND %d)", b.minDays, b.maxDays)
+		}
+		counts[i] = &metrics.Aging[i].Count
+	}
+
+	clause, args = metricsFilters(side, caseType, 2)
+	err = r.db.QueryRowContext(ctx, `
+		SELECT `+strings.Join(buckets, ", ")+`
+		FROM (
+			SELECT $1::DATE - (created_at AT TIME ZONE 'America/New_York')::DATE AS age
+			FROM eip_cases
+			WHERE status <> 'RESOLVED'`+clause+`
+		) aged
+	`, append([]interface{}{today}, args...)...).Scan(counts...)
+	if err != nil {
+		return nil, err
+	}
+
+	clause, args = metricsFilters(side, caseType, 3)
+	var mean, median sql.NullFloat64
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), AVG(hours), percentile_cont(0.5) WITHIN GROUP (ORDER BY hours)
+		FROM (
+			SELECT (EXTRACT(EPOCH FROM h.changed_at - c.created_at) / 3600)::DOUBLE PRECISION AS hours
+			FROM eip_case_status_history h
+			JOIN eip_cases c ON c.id = h.case_id
+			WHERE h.status = 'RESOLVED' AND h.resolution_code IS DISTINCT FROM 'DUPLICATE'
+				AND h.changed_at >= $1 AND h.changed_at < $2`+clause+`
+		) resolved
+	`, append([]interface{}{from, to}, args...)...).Scan(&metrics.Resolution.Resolved, &mean, &median)
+	if err != nil {
+		return nil, err
+	}
+	if mean.Valid {
+		metrics.Resolution.MeanHoursToResolve = &mean.Float64
+	}
+	if median.Valid {
+		metrics.Resolution.MedianHoursToResolve = &median.Float64
+	}
+
+	rows, err = r.db.QueryContext(ctx, `
+		WITH weeks AS (
+			SELECT generate_series(
+				date_trunc('week', $1::TIMESTAMPTZ AT TIME ZONE 'America/New_York'),
+				date_trunc('week', ($2::TIMESTAMPTZ - INTERVAL '1 day') AT TIME ZONE 'America/New_York'),
+				INTERVAL '1 week'
+			) AS week_start
+		), opened AS (
+			SELECT date_trunc('week', created_at AT TIME ZONE 'America/New_York') AS week_start, COUNT(*) AS count
+			FROM eip_cases
+			WHERE created_at >= $1 AND created_at < $2`+clause+`
+			GROUP BY 1
+		), resolved AS (
+			SELECT date_trunc('week', h.changed_at AT TIME ZONE 'America/New_York') AS week_start, COUNT(*) AS count
+			FROM eip_case_status_history h
+			JOIN eip_cases c ON c.id = h.case_id
+			WHERE h.status = 'RESOLVED' AND h.changed_at >= $1 AND h.changed_at < $2`+clause+`
+			GROUP BY 1
+		)
+		SELECT to_char(w.week_start, 'YYYY-MM-DD'), COALESCE(o.count, 0), COALESCE(r.count, 0)
+		FROM weeks w
+		LEFT JOIN opened o ON o.week_start = w.week_start
+		LEFT JOIN resolved r ON r.week_start = w.week_start
+		ORDER BY w.week_start
+	`, append([]interface{}{from, to}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t WeeklyThroughput
+		if err := rows.Scan(&t.WeekStart, &t.Opened, &t.Resolved); err != nil {
+			return nil, err
+		}
+		metrics.Throughput = append(metrics.Throughput, t)
+	}
+
+	return metrics, rows.Err()
+}
+
+// attachmentColumns is the column list shared by every attachment query, in scanAttachment order
+const attachmentColumns = `id, case_id, filename, content_type, size_bytes, sha256, storage_key, uploaded_by, created_at`
+
+// scanAttachment scans a row selected with attachmentColumns into an Attachment
+func scanAttachment(row rowScanner) (*Attachment, error) {
+	attachment := &Attachment{}
+	var uploadedBy sql.NullString
+
+	err := row.Scan(
+		&attachment.ID, &attachment.CaseID, &attachment.Filename, &attachment.ContentType,
+		&attachment.SizeBytes, &attachment.SHA256, &attachment.StorageKey, &uploadedBy, &attachment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.UploadedBy = uploadedBy.String
+	return attachment, nil
+}
+
+// CreateAttachment records an attachment whose bytes are already in the blob store
+func (r *Repository) CreateAttachment(ctx context.Context, attachment *Attachment) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO eip_case_attachments (`+attachmentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, attachment.ID, attachment.CaseID, attachment.Filename, attachment.ContentType,
+		attachment.SizeBytes, attachment.SHA256, attachment.StorageKey,
+		nullString(attachment.UploadedBy), attachment.CreatedAt)
+	return err
+}
 
+// GetAttachment retrieves one of a case's attachments
+func (r *Repository) GetAttachment(ctx context.Context, caseID, id string) (*Attachment, error) {
+	attachment, err := scanAttachment(r.db.QueryRowContext(ctx, `
+		SELECT `+attachmentColumns+` FROM eip_case_attachments WHERE case_id = $1 AND id = $2
+	`, caseID, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -159,6 +1051,252 @@ func (r *Repository) UpdateStatus(ctx context.Context, id, status string) (*EIPC
 		return nil, err
 	}
 
+	return attachment, nil
+}
+
+// ListAttachments returns a case's attachments oldest first
+func (r *Repository) ListAttachments(ctx context.Context, caseID string) ([]*Attachment, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+attachmentColumns+` FROM eip_case_attachments WHERE case_id = $1 ORDER BY created_at, id
+	`, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// updateDispute runs a dispute UPDATE ... RETURNING caseColumns guarded on the case's
+// dispute state and, when it matches, appends an internal comment by author, all in one
+// transaction. It returns (nil, nil) when the case does not exist and ErrDisputeState when
+// the guard does not match.
+func (r *Repository) updateDispute(ctx context.Context, id, author, comment, query string, args ...any) (*EIPCase, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	previous, _, found, err := lockCaseStatus(ctx, tx, id)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	eipCase, err := scanCase(tx.QueryRowContext(ctx, query, append([]any{id}, args...)...))
+	if err == sql.ErrNoRows {
+		return nil, ErrDisputeState
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := recordStatusChange(ctx, tx, eipCase, previous, author); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO eip_case_comments (id, case_id, author, body, visibility, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, uuid.New().String(), id, author, comment, VisibilityInternal, eipCase.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return eipCase, nil
 }
 
+// RecordDisputeStatement stores the customer's written statement and moves the case to
+// STATEMENT_RECEIVED and IN_PROGRESS. A statement can be replaced until a return is attempted.
+func (r *Repository) RecordDisputeStatement(ctx context.Context, id string, dispute *Dispute, receivedBy, comment string) (*EIPCase, error) {
+	return r.updateDispute(ctx, id, receivedBy, comment, `
+		UPDATE eip_cases
+		SET dispute_state = $2, dispute_customer_name = $3, dispute_statement = $4, dispute_signed_on = $5,
+			dispute_statement_received_at = NOW(), dispute_entry_date = $6, dispute_window_ends_on = $7,
+			status = $8, updated_at = NOW()
+		WHERE id = $1 AND status <> 'RESOLVED'
+			AND (dispute_state IS NULL OR dispute_state = $2)
+		RETURNING `+caseColumns,
+		DisputeStatementReceived, dispute.CustomerName, dispute.Statement, dispute.SignedOn,
+		dispute.EntryDate, dispute.WindowEndsOn, StatusInProgress)
+}
+
+// BeginDisputeReturn moves a case with a statement to RETURN_PENDING under a new attempt
+// before the RDFI return is sent. A case left RETURN_PENDING for longer than
+// disputeReturnTimeout, by an approval that was interrupted, may be taken over; one whose
+// attempt may still be running cannot.
+func (r *Repository) BeginDisputeReturn(ctx context.Context, id, attempt, reason, approvedBy, comment string) (*EIPCase, error) {
+	return r.updateDispute(ctx, id, approvedBy, comment, `
+		UPDATE eip_cases
+		SET dispute_state = $2, dispute_return_reason = $3, dispute_last_error = NULL,
+			dispute_return_attempt = $4, dispute_return_started_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status <> 'RESOLVED'
+			AND (dispute_state = $5 OR (dispute_state = $2 AND
+				(dispute_return_started_at IS NULL OR dispute_return_started_at < $6)))
+		RETURNING `+caseColumns,
+		DisputeReturnPending, reason, attempt, DisputeStatementReceived, time.Now().Add(-disputeReturnTimeout))
+}
+
+// CompleteDisputeReturn links the case to the RDFI return and resolves it, provided attempt
+// still owns the return
+func (r *Repository) CompleteDisputeReturn(ctx context.Context, id, attempt string, returnedAt time.Time, resolution *Resolution) (*EIPCase, error) {
+	return r.updateDispute(ctx, id, resolution.ResolvedBy, resolution.Summary, `
+		UPDATE eip_cases
+		SET dispute_state = $2, dispute_returned_at = $3, dispute_last_error = NULL,
+			status = $4, resolution_code = $5, resolution_summary = $6, resolved_by = $7,
+			resolved_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND dispute_state = $8 AND dispute_return_attempt = $9
+		RETURNING `+caseColumns,
+		DisputeReturned, returnedAt, StatusResolved,
+		resolution.Code, resolution.Summary, resolution.ResolvedBy, DisputeReturnPending, attempt)
+}
+
+// FailDisputeReturn compensates a failed RDFI return, moving the case from RETURN_PENDING
+// back to STATEMENT_RECEIVED with the failure recorded. An attempt that was taken over
+// leaves the case to its successor.
+func (r *Repository) FailDisputeReturn(ctx context.Context, id, attempt, cause string) (*EIPCase, error) {
+	return r.updateDispute(ctx, id, disputeWorkflowAuthor, "Return of the disputed entry failed and was rolled back: "+cause, `
+		UPDATE eip_cases
+		SET dispute_state = $2, dispute_last_error = $3, updated_at = NOW()
+		WHERE id = $1 AND dispute_state = $4 AND dispute_return_attempt = $5
+		RETURNING `+caseColumns,
+		DisputeStatementReceived, cause, DisputeReturnPending, attempt)
+}
+
+// DenyDispute resolves a dispute case without returning the entry
+func (r *Repository) DenyDispute(ctx context.Context, id string, resolution *Resolution) (*EIPCase, error) {
+	return r.updateDispute(ctx, id, resolution.ResolvedBy, "Dispute denied: "+resolution.Summary, `
+		UPDATE eip_cases
+		SET dispute_state = $2, status = $3,
+			resolution_code = $4, resolution_summary = $5, resolved_by = $6,
+			resolved_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status <> 'RESOLVED'
+			AND (dispute_state IS NULL OR dispute_state = $7)
+		RETURNING `+caseColumns,
+		DisputeDenied, StatusResolved,
+		resolution.Code, resolution.Summary, resolution.ResolvedBy, DisputeStatementReceived)
+}
+
+// MergeCases consolidates duplicate cases into a surviving case in one transaction. The
+// duplicates' attachments move to the survivor, their comments stay where they were
+// written and are listed with the survivor's, their notes are appended to its notes, and the survivor takes the first duplicate's entry_id if it has none. Each
+// duplicate is resolved as DUPLICATE with merged_into pointing at the survivor. Every case is
+// locked in ID order so concurrent merges cannot deadlock. It returns (nil, nil) when the
+// surviving case does not exist.
+func (r *Repository) MergeCases(ctx context.Context, targetID string, sourceIDs []string, mergedBy string) (*EIPCase, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids := append([]string{targetID}, sourceIDs...)
+	sort.Strings(ids)
+	locked := make(map[string]*EIPCase, len(ids))
+	for _, id := range ids {
+		eipCase, err := scanCase(tx.QueryRowContext(ctx, `SELECT `+caseColumns+` FROM eip_cases WHERE id = $1 FOR UPDATE`, id))
+		if err == sql.ErrNoRows {
+			if id == targetID {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("case %s not found", id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		locked[id] = eipCase
+	}
+
+	target := locked[targetID]
+	if target.Status == StatusResolved {
+		return nil, fmt.Errorf("%w: case %s is resolved", ErrMergeConflict, target.ID)
+	}
+	for _, id := range sourceIDs {
+		source := locked[id]
+		if source.Status == StatusResolved {
+			return nil, fmt.Errorf("%w: case %s is resolved", ErrMergeConflict, id)
+		}
+		if source.Dispute != nil {
+			return nil, fmt.Errorf("%w: case %s is in the dispute workflow; merge into it instead", ErrMergeConflict, id)
+		}
+		if source.Side != target.Side || source.TraceNumber != target.TraceNumber || source.Type != target.Type {
+			return nil, fmt.Errorf("case %s is not a duplicate: side, trace_number and type must match", id)
+		}
+	}
+
+	now := time.Now()
+	notes := target.Notes
+	entryID := target.EntryID
+	for _, id := range sourceIDs {
+		source := locked[id]
+
+		if _, err := tx.ExecContext(ctx, `UPDATE eip_case_attachments SET case_id = $1 WHERE case_id = $2`, targetID, id); err != nil {
+			return nil, err
+		}
+
+		if source.Notes != "" {
+			if notes != "" {
+				notes += "\n\n"
+			}
+			notes += fmt.Sprintf("[Merged from case %s] %s", id, source.Notes)
+		}
+		if entryID == "" {
+			entryID = source.EntryID
+		}
+
+		summary := fmt.Sprintf("Merged into case %s.", targetID)
+		resolved, err := scanCase(tx.QueryRowContext(ctx, `
+			UPDATE eip_cases
+			SET status = $2, resolution_code = $3, resolution_summary = $4, resolved_by = $5,
+				resolved_at = $6, merged_into = $7, updated_at = $6
+			WHERE id = $1
+			RETURNING `+caseColumns, id, StatusResolved, ResolutionDuplicate, summary, mergedBy, now, targetID))
+		if err != nil {
+			return nil, err
+		}
+		if err := recordStatusChange(ctx, tx, resolved, source.Status, mergedBy); err != nil {
+			return nil, err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO eip_case_comments (id, case_id, author, body, visibility, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, uuid.New().String(), id, mergedBy, summary+" Its attachments moved with it; its comments stay here and are listed on that case.", VisibilityInternal, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO eip_case_comments (id, case_id, author, body, visibility, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, uuid.New().String(), targetID, mergedBy,
+		fmt.Sprintf("Merged duplicate cases %s into this case.", strings.Join(sourceIDs, ", ")), VisibilityInternal, now)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := scanCase(tx.QueryRowContext(ctx, `
+		UPDATE eip_cases SET notes = $2, entry_id = $3, updated_at = $4
+		WHERE id = $1
+		RETURNING `+caseColumns, targetID, notes, nullString(entryID), now))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
@@ -0,0 +1,113 @@
+package eip
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Resolution codes a case may be closed with
+const (
+	ResolutionCustomerRefunded = "CUSTOMER_REFUNDED"
+	ResolutionReturnAccepted   = "RETURN_ACCEPTED"
+	ResolutionReturnDishonored = "RETURN_DISHONORED"
+	ResolutionDisputeApproved  = "DISPUTE_APPROVED"
+	ResolutionDisputeDenied    = "DISPUTE_DENIED"
+	ResolutionNOCApplied       = "NOC_APPLIED"
+	ResolutionNOCRefused       = "NOC_REFUSED"
+	ResolutionDuplicate        = "DUPLICATE"
+	ResolutionNoActionRequired = "NO_ACTION_REQUIRED"
+)
+
+// ResolutionCode is a catalog entry describing how a case was closed
+type ResolutionCode struct {
+	Code        string   `json:"code"`
+	Description string   `json:"description"`
+	CaseTypes   []string `json:"case_types"`
+}
+
+var allCaseTypes = []string{TypeReturnReview, TypeNOCReview, TypeCustomerDispute}
+
+// ResolutionCatalog lists every resolution code and the case types it applies to
+var ResolutionCatalog = []ResolutionCode{
+	{ResolutionCustomerRefunded, "The customer was refunded", []string{TypeReturnReview, TypeCustomerDispute}},
+	{ResolutionReturnAccepted, "The return was accepted and booked", []string{TypeReturnReview}},
+	{ResolutionReturnDishonored, "The return was dishonored back to the RDFI", []string{TypeReturnReview}},
+	{ResolutionDisputeApproved, "The dispute was upheld and the entry returned", []string{TypeCustomerDispute}},
+	{ResolutionDisputeDenied, "The dispute was denied", []string{TypeCustomerDispute}},
+	{ResolutionNOCApplied, "The notification of change was applied", []string{TypeNOCReview}},
+	{ResolutionNOCRefused, "The notification of change was refused", []string{TypeNOCReview}},
+	{ResolutionDuplicate, "The case duplicated another case", allCaseTypes},
+	{ResolutionNoActionRequired, "No action was required", allCaseTypes},
+}
+
+// resolutionApplies reports whether code is in the catalog and applies to caseType
+func resolutionApplies(code, caseType string) (known, applies bool) {
+	for _, entry := range ResolutionCatalog {
+		if entry.Code != code {
+			continue
+		}
+		for _, t := range entry.CaseTypes {
+			if t == caseType {
+				return true, true
+			}
+		}
+		return true, false
+	}
+	return false, false
+}
+
+// Resolution is the outcome recorded when a case enters RESOLVED
+type Resolution struct {
+	Code       string
+	Summary    string
+	ResolvedBy string
+}
+
+// ResolutionStats counts resolved cases per period, case type and resolution code
+type ResolutionStats struct {
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Interval string            `json:"interval"`
+	Total    int64             `json:"total"`
+	Periods  []ResolutionCount `json:"periods"`
+}
+
+// ResolutionCount is the number of cases of one type resolved with one code in a period
+type ResolutionCount struct {
+	PeriodStart        string  `json:"period_start"`
+	Type               string  `json:"type"`
+	ResolutionCode     string  `json:"resolution_code"`
+	Count              int64   `json:"count"`
+	MeanHoursToResolve float64 `json:"mean_hours_to_resolve"`
+}
+
+// Stats intervals
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// maxStatsDays bounds the range a single statistics request can span
+const maxStatsDays = 366
+
+// defaultStatsDays is how far back statistics reach when from is not given
+const defaultStatsDays = 90
+
+// ValidateStatsQuery checks a statistics interval and date range
+func ValidateStatsQuery(interval string, from, to time.Time) error {
+	if interval != IntervalDay && interval != IntervalWeek && interval != IntervalMonth {
+		return errors.New("interval must be day, week or month")
+	}
+	if from.IsZero() || to.IsZero() {
+		return nil
+	}
+	if from.After(to) {
+		return errors.New("from must not be after to")
+	}
+	if from.AddDate(0, 0, maxStatsDays).Before(to) {
+		return fmt.Errorf("statistics may span at most %d days", maxStatsDays)
+	}
+	return nil
+}
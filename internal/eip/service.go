@@ -2,32 +2,55 @@ package eip
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ach-concourse/internal/common/ach"
+	"ach-concourse/internal/common/blob"
+	"ach-concourse/internal/common/calendar"
+	"ach-concourse/internal/common/pagination"
 )
 
 // Service handles business logic for EIP cases
 type Service struct {
-	repo *Repository
+	repo               *Repository
+	calendar           *calendar.Calendar
+	blobs              blob.Store
+	maxAttachmentBytes int64
+	rdfi               *RDFIClient
 }
 
-// NewService creates a new EIP service
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+// NewService creates a new EIP service. cal supplies the business days SLA due dates count;
+// blobs holds attachment bytes, each at most maxAttachmentBytes long; rdfi returns disputed
+// entries and may be nil, which disables the dispute workflow.
+func NewService(repo *Repository, cal *calendar.Calendar, blobs blob.Store, maxAttachmentBytes int64, rdfi *RDFIClient) *Service {
+	return &Service{repo: repo, calendar: cal, blobs: blobs, maxAttachmentBytes: maxAttachmentBytes, rdfi: rdfi}
 }
 
-// CreateCase creates a new EIP case
-func (s *Service) CreateCase(ctx context.Context, req *CreateCaseRequest) (*EIPCase, error) {
+// CreateCase creates a new EIP case. When an unresolved case with the same side, trace
+// number and type exists, the request's OnDuplicate policy applies: REJECT returns a
+// *DuplicateCaseError and ATTACH comments the new notes onto the existing case and returns
+// it. created reports whether a new case was made.
+func (s *Service) CreateCase(ctx context.Context, req *CreateCaseRequest) (eipCase *EIPCase, created bool, err error) {
 	// Validate required fields
 	if req.Side == "" {
-		return nil, errors.New("side is required")
+		return nil, false, errors.New("side is required")
 	}
 	if req.Type == "" {
-		return nil, errors.New("type is required")
+		return nil, false, errors.New("type is required")
 	}
 
 	// Validate side
 	if req.Side != SideODFI && req.Side != SideRDFI {
-		return nil, errors.New("side must be ODFI or RDFI")
+		return nil, false, errors.New("side must be ODFI or RDFI")
 	}
 
 	// Validate type
@@ -37,22 +60,78 @@ func (s *Service) CreateCase(ctx context.Context, req *CreateCaseRequest) (*EIPC
 		TypeCustomerDispute: true,
 	}
 	if !validTypes[req.Type] {
-		return nil, errors.New("invalid case type")
+		return nil, false, errors.New("invalid case type")
+	}
+
+	if req.EntryID != "" {
+		if _, err := uuid.Parse(req.EntryID); err != nil {
+			return nil, false, errors.New("entry_id must be a UUID")
+		}
 	}
 
-	eipCase := &EIPCase{
+	priority := req.Priority
+	if priority == "" {
+		priority = PriorityNormal
+	}
+	if priority != PriorityNormal && priority != PriorityHigh && priority != PriorityUrgent {
+		return nil, false, errors.New("priority must be NORMAL, HIGH or URGENT")
+	}
+
+	queue := strings.TrimSpace(req.Queue)
+	if queue == "" {
+		queue = DefaultQueue
+	}
+
+	onDuplicate := req.OnDuplicate
+	if onDuplicate == "" {
+		onDuplicate = DuplicateReject
+	}
+	if onDuplicate != DuplicateReject && onDuplicate != DuplicateAttach {
+		return nil, false, errors.New("on_duplicate must be REJECT or ATTACH")
+	}
+
+	dueDate, atRiskDate, err := s.dueDates(req.Type, time.Now())
+	if err != nil {
+		return nil, false, err
+	}
+
+	eipCase = &EIPCase{
 		Side:        req.Side,
+		EntryID:     req.EntryID,
 		TraceNumber: req.TraceNumber,
 		Status:      StatusOpen,
 		Type:        req.Type,
 		Notes:       req.Notes,
+		Queue:       queue,
+		Assignee:    strings.TrimSpace(req.Assignee),
+		Priority:    priority,
+		DueDate:     dueDate,
+		AtRiskDate:  atRiskDate,
+		SLAStatus:   SLAOnTrack,
 	}
 
-	if err := s.repo.Create(ctx, eipCase); err != nil {
-		return nil, err
+	existing, err := s.repo.Create(ctx, eipCase)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing == nil {
+		return eipCase, true, nil
 	}
 
-	return eipCase, nil
+	if onDuplicate == DuplicateReject {
+		return nil, false, &DuplicateCaseError{Existing: existing}
+	}
+
+	body := "Duplicate case creation attached to this case."
+	if strings.TrimSpace(req.Notes) != "" {
+		body += " Notes: " + req.Notes
+	}
+	comment := &Comment{CaseID: existing.ID, Author: duplicateDetectionAuthor, Body: body, Visibility: VisibilityInternal}
+	if _, err := s.repo.AddComment(ctx, comment); err != nil {
+		return nil, false, err
+	}
+
+	return existing, false, nil
 }
 
 // GetCase retrieves an EIP case by ID
@@ -60,13 +139,15 @@ func (s *Service) GetCase(ctx context.Context, id string) (*EIPCase, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
-// ListCases retrieves EIP cases with optional filters
-func (s *Service) ListCases(ctx context.Context, status, side, traceNumber string) ([]*EIPCase, error) {
-	return s.repo.List(ctx, status, side, traceNumber)
+// ListCases retrieves a page of EIP cases with optional filters
+func (s *Service) ListCases(ctx context.Context, filter CaseFilter, page pagination.Params) (*pagination.Page[*EIPCase], error) {
+	return s.repo.List(ctx, filter, page)
 }
 
-// UpdateCaseStatus updates the status of an EIP case
-func (s *Service) UpdateCaseStatus(ctx context.Context, id, status string) (*EIPCase, error) {
+// UpdateCaseStatus updates the status of an EIP case. Entering RESOLVED requires a
+// resolution code that applies to the case's type and a summary. A case merged into another
+// keeps its DUPLICATE resolution, so its status cannot change.
+func (s *Service) UpdateCaseStatus(ctx context.Context, id string, req *UpdateStatusRequest) (*EIPCase, error) {
 	// Validate status
 	validStatuses := map[string]bool{
 		StatusOpen:       true,
@@ -74,10 +155,599 @@ func (s *Service) UpdateCaseStatus(ctx context.Context, id, status string) (*EIP
 		StatusResolved:   true,
 	}
 
-	if !validStatuses[status] {
+	if !validStatuses[req.Status] {
 		return nil, errors.New("invalid status")
 	}
 
-	return s.repo.UpdateStatus(ctx, id, status)
+	if req.Status != StatusResolved && (req.ResolutionCode != "" || req.ResolutionSummary != "") {
+		return nil, errors.New("resolution_code and resolution_summary are only accepted with status RESOLVED")
+	}
+
+	eipCase, err := s.repo.GetByID(ctx, id)
+	if err != nil || eipCase == nil {
+		return nil, err
+	}
+	if eipCase.MergedInto != "" {
+		return nil, fmt.Errorf("%w: case was merged into %s; work that case instead", ErrMergeConflict, eipCase.MergedInto)
+	}
+	if eipCase.Dispute != nil && eipCase.Dispute.State == DisputeReturnPending {
+		return nil, fmt.Errorf("%w: the RDFI return is in flight", ErrDisputeState)
+	}
+
+	if req.Status != StatusResolved {
+		return s.repo.UpdateStatus(ctx, id, req.Status, nil)
+	}
+
+	resolution, err := validateResolution(eipCase.Type, req)
+	if err != nil {
+		return nil, err
+	}
+	if eipCase.Dispute != nil && resolution.Code == ResolutionDisputeApproved {
+		return nil, fmt.Errorf("%w: approve the dispute through the dispute workflow so the entry is returned", ErrDisputeState)
+	}
+
+	return s.repo.UpdateStatus(ctx, id, req.Status, resolution)
+}
+
+// validateResolution checks the resolution given with a move to RESOLVED
+func validateResolution(caseType string, req *UpdateStatusRequest) (*Resolution, error) {
+	if req.ResolutionCode == "" {
+		return nil, errors.New("resolution_code is required to resolve a case")
+	}
+	summary := strings.TrimSpace(req.ResolutionSummary)
+	if summary == "" {
+		return nil, errors.New("resolution_summary is required to resolve a case")
+	}
+
+	known, applies := resolutionApplies(req.ResolutionCode, caseType)
+	if !known {
+		return nil, fmt.Errorf("unknown resolution_code %q", req.ResolutionCode)
+	}
+	if !applies {
+		return nil, fmt.Errorf("resolution_code %s does not apply to %s cases", req.ResolutionCode, caseType)
+	}
+
+	return &Resolution{
+		Code:       req.ResolutionCode,
+		Summary:    summary,
+		ResolvedBy: strings.TrimSpace(req.ResolvedBy),
+	}, nil
+}
+
+// MergeCases merges duplicate cases into the case id, keeping their notes, comments and
+// attachments. It returns nil when the surviving case does not exist.
+func (s *Service) MergeCases(ctx context.Context, id string, req *MergeRequest) (*EIPCase, error) {
+	mergedBy := strings.TrimSpace(req.MergedBy)
+	if mergedBy == "" {
+		return nil, errors.New("merged_by is required")
+	}
+	if len(req.CaseIDs) == 0 {
+		return nil, errors.New("case_ids is required")
+	}
+	if len(req.CaseIDs) > maxMergeCases {
+		return nil, fmt.Errorf("at most %d cases can be merged at once", maxMergeCases)
+	}
+
+	seen := map[string]bool{id: true}
+	for _, caseID := range req.CaseIDs {
+		if _, err := uuid.Parse(caseID); err != nil {
+			return nil, fmt.Errorf("case_ids must be UUIDs: %q", caseID)
+		}
+		if caseID == id {
+			return nil, errors.New("a case cannot be merged into itself")
+		}
+		if seen[caseID] {
+			return nil, fmt.Errorf("case %s is listed more than once", caseID)
+		}
+		seen[caseID] = true
+	}
+
+	return s.repo.MergeCases(ctx, id, req.CaseIDs, mergedBy)
+}
+
+// AssignCase assigns a case to an analyst, replacing any current assignee
+func (s *Service) AssignCase(ctx context.Context, id, assignee string) (*EIPCase, error) {
+	assignee = strings.TrimSpace(assignee)
+	if assignee == "" {
+		return nil, errors.New("assignee is required")
+	}
+
+	return s.repo.SetAssignee(ctx, id, assignee)
+}
+
+// UnassignCase returns a case to its queue's unassigned pool
+func (s *Service) UnassignCase(ctx context.Context, id string) (*EIPCase, error) {
+	return s.repo.SetAssignee(ctx, id, "")
+}
+
+// ClaimNextCase hands the oldest open, unassigned case in a queue to an analyst
+func (s *Service) ClaimNextCase(ctx context.Context, req *ClaimRequest) (*EIPCase, error) {
+	assignee := strings.TrimSpace(req.Assignee)
+	if assignee == "" {
+		return nil, errors.New("assignee is required")
+	}
+	queue := strings.TrimSpace(req.Queue)
+	if queue == "" {
+		queue = DefaultQueue
+	}
+
+	return s.repo.ClaimNext(ctx, queue, assignee)
+}
+
+// AddComment appends a comment to a case. It returns nil when the case does not exist.
+func (s *Service) AddComment(ctx context.Context, caseID string, req *CreateCommentRequest) (*Comment, error) {
+	author := strings.TrimSpace(req.Author)
+	if author == "" {
+		return nil, errors.New("author is required")
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, errors.New("body is required")
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = VisibilityInternal
+	}
+	if visibility != VisibilityInternal && visibility != VisibilityExternal {
+		return nil, errors.New("visibility must be INTERNAL or EXTERNAL")
+	}
+
+	comment := &Comment{
+		CaseID:     caseID,
+		Author:     author,
+		Body:       req.Body,
+		Visibility: visibility,
+	}
+
+	found, err := s.repo.AddComment(ctx, comment)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// ListComments returns a case's comments oldest first. It returns nil when the case does not exist.
+func (s *Service) ListComments(ctx context.Context, caseID, visibility string) ([]*Comment, error) {
+	eipCase, err := s.repo.GetByID(ctx, caseID)
+	if err != nil || eipCase == nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.ListComments(ctx, caseID, visibility)
+	if err != nil {
+		return nil, err
+	}
+	if comments == nil {
+		comments = []*Comment{}
+	}
+
+	return comments, nil
+}
+
+// dueDates computes a case's SLA due date and the date it becomes at risk from its type's
+// policy, counting business days from the day it was opened
+func (s *Service) dueDates(caseType string, openedAt time.Time) (string, string, error) {
+	policy := SLAPolicies[caseType]
+	due, err := s.calendar.AddBusinessDays(openedAt, policy.BusinessDays)
+	if err != nil {
+		return "", "", err
+	}
+	atRisk, err := s.calendar.AddBusinessDays(due, -policy.AtRiskDays)
+	if err != nil {
+		return "", "", err
+	}
+	return calendar.FormatDate(due), calendar.FormatDate(atRisk), nil
+}
+
+// SweepSLA gives due dates to unresolved cases opened before SLA tracking, then escalates
+// cases that have become at risk or overdue
+func (s *Service) SweepSLA(ctx context.Context) ([]Escalation, error) {
+	missing, err := s.repo.ListMissingDueDates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, eipCase := range missing {
+		dueDate, atRiskDate, err := s.dueDates(eipCase.Type, eipCase.CreatedAt)
+		if err != nil {
+			// A case the calendar cannot date keeps no due date; it must not stop the sweep
+			log.Printf("sla sweep: case %s: %v", eipCase.ID, err)
+			continue
+		}
+		if err := s.repo.SetDueDates(ctx, eipCase.ID, dueDate, atRiskDate); err != nil {
+			return nil, fmt.Errorf("case %s: %w", eipCase.ID, err)
+		}
+	}
+
+	return s.repo.EscalateSLA(ctx, calendar.FormatDate(calendar.Today()))
+}
+
+// GetSLASummary counts unresolved cases by SLA standing, overall and per type
+func (s *Service) GetSLASummary(ctx context.Context) (*SLASummary, error) {
+	today := calendar.FormatDate(calendar.Today())
+	byType, err := s.repo.GetSLASummary(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &SLASummary{AsOf: today, ByType: byType, Policies: SLAPolicies}
+	if summary.ByType == nil {
+		summary.ByType = []SLATypeSummary{}
+	}
+	for _, t := range byType {
+		summary.Open += t.Open
+		summary.OnTrack += t.OnTrack
+		summary.AtRisk += t.AtRisk
+		summary.Overdue += t.Overdue
+		summary.DueToday += t.DueToday
+	}
+
+	return summary, nil
+}
+
+// GetResolutionStats counts resolved cases by period, type and resolution code. Zero from/to
+// default to the last 90 days through today.
+func (s *Service) GetResolutionStats(ctx context.Context, interval, side string, from, to time.Time) (*ResolutionStats, error) {
+	if to.IsZero() {
+		to = calendar.Today()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, 1-defaultStatsDays)
+	}
+
+	counts, err := s.repo.GetResolutionStats(ctx, from, to.AddDate(0, 0, 1), interval, side)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ResolutionStats{
+		From:     calendar.FormatDate(from),
+		To:       calendar.FormatDate(to),
+		Interval: interval,
+		Periods:  counts,
+	}
+	if stats.Periods == nil {
+		stats.Periods = []ResolutionCount{}
+	}
+	for _, c := range counts {
+		stats.Total += c.Count
+	}
+
+	return stats, nil
+}
+
+// GetCaseMetrics computes open counts, aging, resolution time and weekly throughput for the
+// cases matching filter
+func (s *Service) GetCaseMetrics(ctx context.Context, filter MetricsFilter) (*CaseMetrics, error) {
+	today := calendar.Today()
+	to := filter.To
+	if to.IsZero() {
+		to = today
+	}
+	from := filter.From
+	if from.IsZero() {
+		from = to.AddDate(0, 0, 1-defaultStatsDays)
+	}
+
+	metrics, err := s.repo.GetCaseMetrics(ctx, calendar.FormatDate(today), from, to.AddDate(0, 0, 1), filter.Side, filter.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.AsOf = calendar.FormatDate(today)
+	metrics.From = calendar.FormatDate(from)
+	metrics.To = calendar.FormatDate(to)
+	metrics.Side = filter.Side
+	metrics.Type = filter.Type
+	if metrics.OpenCounts == nil {
+		metrics.OpenCounts = []CaseStatusCount{}
+	}
+	if metrics.Throughput == nil {
+		metrics.Throughput = []WeeklyThroughput{}
+	}
+	for _, c := range metrics.OpenCounts {
+		metrics.Open += c.Count
+	}
+
+	return metrics, nil
+}
+
+// MaxAttachmentBytes is the largest attachment AddAttachment accepts
+func (s *Service) MaxAttachmentBytes() int64 {
+	return s.maxAttachmentBytes
+}
+
+// AddAttachment stores an uploaded file against a case. file is read twice, once to hash
+// and sniff it and once to store it. It returns nil when the case does not exist.
+func (s *Service) AddAttachment(ctx context.Context, caseID string, file io.ReadSeeker, size int64, filename, uploadedBy string) (*Attachment, error) {
+	if size <= 0 {
+		return nil, ErrAttachmentEmpty
+	}
+	if size > s.maxAttachmentBytes {
+		return nil, fmt.Errorf("%w of %d bytes", ErrAttachmentTooLarge, s.maxAttachmentBytes)
+	}
+
+	eipCase, err := s.repo.GetByID(ctx, caseID)
+	if err != nil || eipCase == nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	contentType := sniffContentType(head[:n])
+	if !AllowedAttachmentTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentType, contentType)
+	}
+
+	hash := sha256.New()
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	attachment := &Attachment{
+		ID:          uuid.New().String(),
+		CaseID:      caseID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		SizeBytes:   size,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+		UploadedBy:  strings.TrimSpace(uploadedBy),
+		CreatedAt:   time.Now(),
+	}
+	attachment.StorageKey = attachmentKey(caseID, attachment.ID)
+
+	if err := s.blobs.Put(ctx, attachment.StorageKey, file, size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if err := s.repo.CreateAttachment(ctx, attachment); err != nil {
+		s.blobs.Delete(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// ListAttachments returns a case's attachments. It returns nil when the case does not exist.
+func (s *Service) ListAttachments(ctx context.Context, caseID string) ([]*Attachment, error) {
+	eipCase, err := s.repo.GetByID(ctx, caseID)
+	if err != nil || eipCase == nil {
+		return nil, err
+	}
+
+	attachments, err := s.repo.ListAttachments(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if attachments == nil {
+		attachments = []*Attachment{}
+	}
+
+	return attachments, nil
+}
+
+// OpenAttachment returns an attachment and a reader over its bytes, which the caller closes.
+// It returns a nil attachment when the case has no such attachment.
+func (s *Service) OpenAttachment(ctx context.Context, caseID, id string) (*Attachment, io.ReadCloser, error) {
+	attachment, err := s.repo.GetAttachment(ctx, caseID, id)
+	if err != nil || attachment == nil {
+		return nil, nil, err
+	}
+
+	body, err := s.blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	return attachment, body, nil
+}
+
+// RecordDisputeStatement captures the customer's written statement of unauthorized debit on
+// a CUSTOMER_DISPUTE case. The linked RDFI entry must be an unreturned debit still inside its
+// dispute window, and the statement must be signed between the entry date and today. It
+// returns nil when the case does not exist.
+func (s *Service) RecordDisputeStatement(ctx context.Context, id string, req *DisputeStatementRequest) (*EIPCase, error) {
+	customerName := strings.TrimSpace(req.CustomerName)
+	if customerName == "" {
+		return nil, errors.New("customer_name is required")
+	}
+	statement := strings.TrimSpace(req.Statement)
+	if statement == "" {
+		return nil, errors.New("statement is required")
+	}
+	receivedBy := strings.TrimSpace(req.ReceivedBy)
+	if receivedBy == "" {
+		return nil, errors.New("received_by is required")
+	}
+	signedOn, err := calendar.ParseDate(req.SignedOn)
+	if err != nil {
+		return nil, errors.New("signed_on must be YYYY-MM-DD")
+	}
+
+	eipCase, err := s.disputeCase(ctx, id)
+	if err != nil || eipCase == nil {
+		return nil, err
+	}
+
+	entry, err := s.disputedEntry(ctx, eipCase)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status == rdfiStatusReturned {
+		return nil, fmt.Errorf("entry %s was already returned with %s", entry.ID, entry.ReturnReason)
+	}
+
+	entryDate, windowEndsOn := disputeWindow(entry)
+	today := calendar.Today()
+	if today.After(windowEndsOn) {
+		return nil, fmt.Errorf("%w on %s, %d days after the entry date %s",
+			ErrDisputeWindow, calendar.FormatDate(windowEndsOn), DisputeWindowDays, calendar.FormatDate(entryDate))
+	}
+	if signedOn.Before(entryDate) {
+		return nil, fmt.Errorf("signed_on must be on or after the entry date %s", calendar.FormatDate(entryDate))
+	}
+	if signedOn.After(today) {
+		return nil, errors.New("signed_on cannot be in the future")
+	}
+
+	dispute := &Dispute{
+		CustomerName: customerName,
+		Statement:    statement,
+		SignedOn:     calendar.FormatDate(signedOn),
+		EntryDate:    calendar.FormatDate(entryDate),
+		WindowEndsOn: calendar.FormatDate(windowEndsOn),
+	}
+	comment := fmt.Sprintf("Written statement of unauthorized debit received from %s, signed %s.", customerName, dispute.SignedOn)
+
+	return s.repo.RecordDisputeStatement(ctx, id, dispute, receivedBy, comment)
+}
+
+// ApproveDispute approves a dispute and returns the entry through RDFI. The case moves to
+// RETURN_PENDING before RDFI is called, then to RETURNED and RESOLVED with DISPUTE_APPROVED
+// once the return is made. If RDFI refuses the return, the case is compensated back to
+// STATEMENT_RECEIVED and ErrDisputeReturnFailed is returned. Each approval is an attempt
+// with its own Idempotency-Key for the return; approving again while an attempt may still be
+// running returns ErrDisputeState, and an entry RDFI already shows returned with the same
+// reason counts as returned. It returns nil when the case does not exist.
+func (s *Service) ApproveDispute(ctx context.Context, id string, req *ApproveDisputeRequest) (*EIPCase, error) {
+	approvedBy := strings.TrimSpace(req.ApprovedBy)
+	if approvedBy == "" {
+		return nil, errors.New("approved_by is required")
+	}
+	reason := req.ReturnReason
+	if reason == "" {
+		reason = ReturnReasonUnauthorized
+	}
+	if reason != ReturnReasonUnauthorized && reason != ReturnReasonCorporateUnauthorized {
+		return nil, errors.New("return_reason must be R10 or R05")
+	}
+
+	eipCase, err := s.disputeCase(ctx, id)
+	if err != nil || eipCase == nil {
+		return nil, err
+	}
+	if eipCase.Dispute == nil {
+		return nil, fmt.Errorf("%w: no written statement has been recorded", ErrDisputeState)
+	}
+	windowEndsOn, err := calendar.ParseDate(eipCase.Dispute.WindowEndsOn)
+	if err == nil && calendar.Today().After(windowEndsOn) {
+		return nil, fmt.Errorf("%w on %s", ErrDisputeWindow, eipCase.Dispute.WindowEndsOn)
+	}
+
+	attempt := uuid.New().String()
+	comment := fmt.Sprintf("Dispute approved; returning entry %s with %s.", eipCase.EntryID, reason)
+	eipCase, err = s.repo.BeginDisputeReturn(ctx, id, attempt, reason, approvedBy, comment)
+	if err != nil || eipCase == nil {
+		return nil, err
+	}
+
+	entry, err := s.returnDisputedEntry(ctx, eipCase, attempt, reason)
+	if err != nil {
+		// The compensation must land even if the caller has gone away
+		if _, failErr := s.repo.FailDisputeReturn(context.WithoutCancel(ctx), id, attempt, err.Error()); failErr != nil {
+			return nil, fmt.Errorf("%w: %v (compensation failed: %v)", ErrDisputeReturnFailed, err, failErr)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrDisputeReturnFailed, err)
+	}
+
+	return s.repo.CompleteDisputeReturn(ctx, id, attempt, entry.UpdatedAt, &Resolution{
+		Code:       ResolutionDisputeApproved,
+		Summary:    fmt.Sprintf("Entry %s returned with %s on the customer's written statement of unauthorized debit.", entry.ID, reason),
+		ResolvedBy: approvedBy,
+	})
 }
 
+// DenyDispute resolves a dispute case with DISPUTE_DENIED, leaving the entry in place. It
+// returns nil when the case does not exist.
+func (s *Service) DenyDispute(ctx context.Context, id string, req *DenyDisputeRequest) (*EIPCase, error) {
+	deniedBy := strings.TrimSpace(req.DeniedBy)
+	if deniedBy == "" {
+		return nil, errors.New("denied_by is required")
+	}
+	summary := strings.TrimSpace(req.Summary)
+	if summary == "" {
+		return nil, errors.New("summary is required")
+	}
+
+	eipCase, err := s.disputeCase(ctx, id)
+	if err != nil || eipCase == nil {
+		return nil, err
+	}
+
+	return s.repo.DenyDispute(ctx, id, &Resolution{
+		Code:       ResolutionDisputeDenied,
+		Summary:    summary,
+		ResolvedBy: deniedBy,
+	})
+}
+
+// disputeCase loads a case and checks it can enter the dispute workflow
+func (s *Service) disputeCase(ctx context.Context, id string) (*EIPCase, error) {
+	if s.rdfi == nil {
+		return nil, fmt.Errorf("%w: RDFI_BASE_URL is not set", ErrRDFIUnavailable)
+	}
+
+	eipCase, err := s.repo.GetByID(ctx, id)
+	if err != nil || eipCase == nil {
+		return nil, err
+	}
+	if eipCase.Type != TypeCustomerDispute || eipCase.Side != SideRDFI {
+		return nil, errors.New("only RDFI CUSTOMER_DISPUTE cases use the dispute workflow")
+	}
+	if eipCase.EntryID == "" {
+		return nil, errors.New("case must be linked to the disputed RDFI entry by entry_id")
+	}
+
+	return eipCase, nil
+}
+
+// disputedEntry fetches the RDFI entry a dispute case is linked to
+func (s *Service) disputedEntry(ctx context.Context, eipCase *EIPCase) (*RDFIEntry, error) {
+	entry, err := s.rdfi.GetEntry(ctx, eipCase.EntryID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRDFIUnavailable, err)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("linked RDFI entry %s not found", eipCase.EntryID)
+	}
+	if entry.Direction != ach.DirectionDebit {
+		return nil, errors.New("only debit entries can be disputed as unauthorized")
+	}
+
+	return entry, nil
+}
+
+// returnDisputedEntry returns the case's entry with reason, keyed by attempt, treating an
+// entry RDFI already shows returned with reason as done. After an ambiguous failure the entry
+// is read back, so a return that landed despite a lost response is not rolled back.
+func (s *Service) returnDisputedEntry(ctx context.Context, eipCase *EIPCase, attempt, reason string) (*RDFIEntry, error) {
+	entry, err := s.disputedEntry(ctx, eipCase)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status == rdfiStatusReturned {
+		if entry.ReturnReason == reason {
+			return entry, nil
+		}
+		return nil, fmt.Errorf("entry was already returned with %s", entry.ReturnReason)
+	}
+
+	returned, err := s.rdfi.ReturnEntry(ctx, entry.ID, reason, "eip-dispute-"+attempt)
+	if err == nil {
+		return returned, nil
+	}
+
+	if current, getErr := s.rdfi.GetEntry(ctx, entry.ID); getErr == nil && current != nil &&
+		current.Status == rdfiStatusReturned && current.ReturnReason == reason {
+		return current, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,121 @@
+package eip
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+// SLAPolicy is the deadline a case type is worked against, in business days from creation
+type SLAPolicy struct {
+	BusinessDays int `json:"business_days"`
+	// AtRiskDays is how many business days before the due date a case counts as at risk
+	AtRiskDays int `json:"at_risk_days"`
+}
+
+// SLAPolicies holds the deadline for each case type. Return reviews follow the two-banking-day
+// return window, NOC reviews the six banking days allowed to apply a change, and customer
+// disputes the ten business days Regulation E allows for an investigation.
+var SLAPolicies = map[string]SLAPolicy{
+	TypeReturnReview:    {BusinessDays: 2, AtRiskDays: 1},
+	TypeNOCReview:       {BusinessDays: 6, AtRiskDays: 1},
+	TypeCustomerDispute: {BusinessDays: 10, AtRiskDays: 2},
+}
+
+// SLA status constants
+const (
+	SLAOnTrack = "ON_TRACK"
+	SLAAtRisk  = "AT_RISK"
+	SLAOverdue = "OVERDUE"
+)
+
+// Priority constants. The sweeper raises at-risk cases to HIGH and overdue cases to URGENT.
+const (
+	PriorityNormal = "NORMAL"
+	PriorityHigh   = "HIGH"
+	PriorityUrgent = "URGENT"
+)
+
+// EscalationQueue is where the sweeper moves cases once they are overdue
+const EscalationQueue = "ESCALATIONS"
+
+// slaSweeperAuthor signs the comments the sweeper leaves on escalated cases
+const slaSweeperAuthor = "sla-sweeper"
+
+// DefaultSweepInterval is how often the SLA sweeper runs when SLA_SWEEP_INTERVAL is not set
+const DefaultSweepInterval = time.Minute
+
+// SLASummary counts unresolved cases by SLA standing
+type SLASummary struct {
+	AsOf     string               `json:"as_of"`
+	Open     int64                `json:"open"`
+	OnTrack  int64                `json:"on_track"`
+	AtRisk   int64                `json:"at_risk"`
+	Overdue  int64                `json:"overdue"`
+	DueToday int64                `json:"due_today"`
+	ByType   []SLATypeSummary     `json:"by_type"`
+	Policies map[string]SLAPolicy `json:"policies"`
+}
+
+// SLATypeSummary counts unresolved cases of one type by SLA standing
+type SLATypeSummary struct {
+	Type     string `json:"type"`
+	Open     int64  `json:"open"`
+	OnTrack  int64  `json:"on_track"`
+	AtRisk   int64  `json:"at_risk"`
+	Overdue  int64  `json:"overdue"`
+	DueToday int64  `json:"due_today"`
+}
+
+// Escalation is a case whose SLA standing the sweeper worsened
+type Escalation struct {
+	CaseID    string
+	SLAStatus string
+	DueDate   string
+}
+
+// Sweeper periodically re-evaluates the SLA standing of unresolved cases
+type Sweeper struct {
+	service  *Service
+	interval time.Duration
+}
+
+// NewSweeper creates a sweeper that runs every interval
+func NewSweeper(service *Service, interval time.Duration) *Sweeper {
+	return &Sweeper{service: service, interval: interval}
+}
+
+// NewSweeperFromEnv creates a sweeper using SLA_SWEEP_INTERVAL (a Go duration such as "5m")
+func NewSweeperFromEnv(service *Service) *Sweeper {
+	interval := DefaultSweepInterval
+	if value := os.Getenv("SLA_SWEEP_INTERVAL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			interval = parsed
+		}
+	}
+
+	return NewSweeper(service, interval)
+}
+
+// Run sweeps every interval until ctx is cancelled
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		escalations, err := s.service.SweepSLA(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("sla sweep: %v", err)
+		}
+		for _, e := range escalations {
+			log.Printf("sla sweep: case %s is %s (due %s)", e.CaseID, e.SLAStatus, e.DueDate)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,31 +1,69 @@
 package ledger
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 
+	"ach-concourse/internal/common/calendar"
+	"ach-concourse/internal/common/events"
 	commonhttp "ach-concourse/internal/common/http"
+	"ach-concourse/internal/common/idempotency"
+	"ach-concourse/internal/common/pagination"
+	"ach-concourse/internal/common/validation"
 )
 
 // Handler handles HTTP requests for ledger
 type Handler struct {
 	service *Service
+	keys    *idempotency.Store
 }
 
 // NewHandler creates a new ledger handler
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, keys *idempotency.Store) *Handler {
+	return &Handler{service: service, keys: keys}
 }
 
 // RegisterRoutes registers all ledger routes
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/api/v1/postings", func(r chi.Router) {
-		r.Post("/", h.CreatePosting)
+		r.With(idempotency.Middleware(h.keys)).Post("/", h.CreatePosting)
 		r.Get("/", h.ListPostings)
+		r.Post("/{id}/reverse", h.ReversePosting)
 	})
+	r.Route("/api/v1/journal-entries", func(r chi.Router) {
+		r.With(idempotency.Middleware(h.keys)).Post("/", h.CreateJournalEntry)
+		r.Get("/", h.ListJournalEntries)
+		r.Get("/{id}", h.GetJournalEntry)
+	})
+	r.Route("/api/v1/events", func(r chi.Router) {
+		r.With(idempotency.Middleware(h.keys)).Post("/", h.ProcessEvent)
+		r.Get("/", h.ListEvents)
+	})
+	r.Route("/api/v1/periods", func(r chi.Router) {
+		r.Get("/", h.ListPeriods)
+		r.Get("/{date}", h.GetPeriod)
+		r.Post("/{date}/close", h.ClosePeriod)
+		r.Post("/{date}/reopen", h.ReopenPeriod)
+	})
+	r.Route("/api/v1/settlements", func(r chi.Router) {
+		r.Get("/", h.GetSettlement)
+		r.Post("/{date}/transfer", h.TransferSettlement)
+	})
+	r.Route("/api/v1/holds", func(r chi.Router) {
+		r.Get("/", h.ListHolds)
+		r.Post("/{id}/release", h.ReleaseHold)
+	})
+	r.Get("/api/v1/statements", h.ExportStatement)
+	r.Get("/api/v1/accounts", h.ListAccounts)
 	r.Get("/api/v1/balances", h.GetBalances)
+	r.Get("/api/v1/balances/series", h.GetBalanceSeries)
 	r.Get("/healthz", h.Health)
 }
 
@@ -38,35 +76,177 @@ func (h *Handler) CreatePosting(w http.ResponseWriter, r *http.Request) {
 	}
 
 	entry, err := h.service.CreatePosting(r.Context(), &req)
+	if errors.Is(err, ErrPeriodClosed) {
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	}
 	if err != nil {
-		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		commonhttp.ErrorFrom(w, http.StatusBadRequest, err)
 		return
 	}
 
 	commonhttp.JSON(w, http.StatusCreated, entry)
 }
 
-// ListPostings handles GET /api/v1/postings
+// ListPostings handles GET /api/v1/postings. Results are paged with limit, cursor, sort_by
+// and sort_order; from and to bound the effective date, both inclusive.
 func (h *Handler) ListPostings(w http.ResponseWriter, r *http.Request) {
+	filter := PostingFilter{
+		AchSide:     r.URL.Query().Get("ach_side"),
+		TraceNumber: r.URL.Query().Get("trace_number"),
+	}
+	for _, p := range []struct {
+		name string
+		dst  *string
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := r.URL.Query().Get(p.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := calendar.ParseDate(value)
+		if err != nil {
+			commonhttp.Error(w, http.StatusBadRequest, p.name+" must be YYYY-MM-DD")
+			return
+		}
+		*p.dst = calendar.FormatDate(parsed)
+	}
+
+	page, err := pagination.FromQuery(r.URL.Query(), PostingSortFields, "created_at")
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := h.service.ListPostings(r.Context(), filter, page)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list postings")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, entries)
+}
+
+// ReversePosting handles POST /api/v1/postings/{id}/reverse
+func (h *Handler) ReversePosting(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(id); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid posting id")
+		return
+	}
+
+	var req ReversePostingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	reversal, err := h.service.ReversePosting(r.Context(), id, req.Reason)
+	var fieldErrs validation.Errors
+	switch {
+	case errors.Is(err, ErrAlreadyReversed) || errors.Is(err, ErrReversalOfReversal) || errors.Is(err, ErrPeriodClosed):
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	case errors.As(err, &fieldErrs):
+		commonhttp.ErrorFrom(w, http.StatusBadRequest, err)
+		return
+	case err != nil:
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to reverse posting")
+		return
+	}
+
+	if reversal == nil {
+		commonhttp.Error(w, http.StatusNotFound, "posting not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, reversal)
+}
+
+// CreateJournalEntry handles POST /api/v1/journal-entries
+func (h *Handler) CreateJournalEntry(w http.ResponseWriter, r *http.Request) {
+	var req CreateJournalEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	journal, err := h.service.CreateJournalEntry(r.Context(), &req)
+	if errors.Is(err, ErrPeriodClosed) {
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		commonhttp.ErrorFrom(w, http.StatusBadRequest, err)
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, journal)
+}
+
+// ListJournalEntries handles GET /api/v1/journal-entries
+func (h *Handler) ListJournalEntries(w http.ResponseWriter, r *http.Request) {
 	achSide := r.URL.Query().Get("ach_side")
 	traceNumber := r.URL.Query().Get("trace_number")
 
-	entries, err := h.service.ListPostings(r.Context(), achSide, traceNumber)
+	journals, err := h.service.ListJournalEntries(r.Context(), achSide, traceNumber)
 	if err != nil {
-		commonhttp.Error(w, http.StatusInternalServerError, "failed to list postings")
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list journal entries")
 		return
 	}
 
-	if entries == nil {
-		entries = []*LedgerEntry{}
+	if journals == nil {
+		journals = []*JournalEntry{}
 	}
 
-	commonhttp.JSON(w, http.StatusOK, entries)
+	commonhttp.JSON(w, http.StatusOK, journals)
+}
+
+// GetJournalEntry handles GET /api/v1/journal-entries/{id}
+func (h *Handler) GetJournalEntry(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	journal, err := h.service.GetJournalEntry(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get journal entry")
+		return
+	}
+
+	if journal == nil {
+		commonhttp.Error(w, http.StatusNotFound, "journal entry not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, journal)
+}
+
+// ListAccounts handles GET /api/v1/accounts
+func (h *Handler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.service.ListAccounts(r.Context())
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list accounts")
+		return
+	}
+
+	if accounts == nil {
+		accounts = []*Account{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, accounts)
 }
 
 // GetBalances handles GET /api/v1/balances
 func (h *Handler) GetBalances(w http.ResponseWriter, r *http.Request) {
-	balances, err := h.service.GetBalances(r.Context())
+	achSide := r.URL.Query().Get("ach_side")
+	account := r.URL.Query().Get("account")
+	traceNumber := r.URL.Query().Get("trace_number")
+
+	asOf, err := parseAsOf(r.URL.Query().Get("as_of"))
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	balances, err := h.service.GetBalances(r.Context(), achSide, account, traceNumber, asOf)
 	if err != nil {
 		commonhttp.Error(w, http.StatusInternalServerError, "failed to get balances")
 		return
@@ -75,6 +255,314 @@ func (h *Handler) GetBalances(w http.ResponseWriter, r *http.Request) {
 	commonhttp.JSON(w, http.StatusOK, balances)
 }
 
+// GetBalanceSeries handles GET /api/v1/balances/series
+func (h *Handler) GetBalanceSeries(w http.ResponseWriter, r *http.Request) {
+	achSide := r.URL.Query().Get("ach_side")
+	account := r.URL.Query().Get("account")
+	traceNumber := r.URL.Query().Get("trace_number")
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = IntervalDay
+	}
+
+	var from, to time.Time
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		value := r.URL.Query().Get(p.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := calendar.ParseDate(value)
+		if err != nil {
+			commonhttp.Error(w, http.StatusBadRequest, p.name+" must be YYYY-MM-DD")
+			return
+		}
+		*p.dst = parsed
+	}
+
+	if err := ValidateSeriesRange(interval, from, to); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	series, err := h.service.GetBalanceSeries(r.Context(), achSide, account, traceNumber, interval, from, to)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get balance series")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, series)
+}
+
+// ExportStatement handles GET /api/v1/statements, rendering account activity for a date
+// range as a BAI2 file or a camt.053 document. from and to default to today.
+func (h *Handler) ExportStatement(w http.ResponseWriter, r *http.Request) {
+	account := r.URL.Query().Get("account")
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = StatementFormatBAI2
+	}
+
+	from, to := calendar.Today(), calendar.Today()
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		value := r.URL.Query().Get(p.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := calendar.ParseDate(value)
+		if err != nil {
+			commonhttp.Error(w, http.StatusBadRequest, p.name+" must be YYYY-MM-DD")
+			return
+		}
+		*p.dst = parsed
+	}
+
+	if err := ValidateStatementQuery(format, from, to); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	statement, err := h.service.BuildStatement(r.Context(), account, from, to)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to build statement")
+		return
+	}
+
+	if statement == nil {
+		commonhttp.Error(w, http.StatusNotFound, "account not found")
+		return
+	}
+
+	var body bytes.Buffer
+	if err := RenderStatement(&body, statement, format); err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to render statement")
+		return
+	}
+
+	filename := StatementFilename(account, statement.From, statement.To, format)
+	w.Header().Set("Content-Type", StatementContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+// parseAsOf accepts an RFC 3339 timestamp, or a YYYY-MM-DD date meaning the end of that
+// day in Eastern time. An empty value yields the zero time (no cutoff).
+func parseAsOf(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	date, err := calendar.ParseDate(value)
+	if err != nil {
+		return time.Time{}, errors.New("as_of must be an RFC 3339 timestamp or YYYY-MM-DD")
+	}
+	return date.AddDate(0, 0, 1).Add(-time.Microsecond), nil
+}
+
+// ListPeriods handles GET /api/v1/periods
+func (h *Handler) ListPeriods(w http.ResponseWriter, r *http.Request) {
+	periods, err := h.service.ListPeriods(r.Context())
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list periods")
+		return
+	}
+
+	if periods == nil {
+		periods = []*Period{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, periods)
+}
+
+// GetPeriod handles GET /api/v1/periods/{date}
+func (h *Handler) GetPeriod(w http.ResponseWriter, r *http.Request) {
+	date := chi.URLParam(r, "date")
+	if _, err := calendar.ParseDate(date); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "date must be YYYY-MM-DD")
+		return
+	}
+
+	period, err := h.service.GetPeriod(r.Context(), date)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get period")
+		return
+	}
+
+	if period == nil {
+		commonhttp.Error(w, http.StatusNotFound, "period not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, period)
+}
+
+// ClosePeriod handles POST /api/v1/periods/{date}/close
+func (h *Handler) ClosePeriod(w http.ResponseWriter, r *http.Request) {
+	date := chi.URLParam(r, "date")
+
+	period, err := h.service.ClosePeriod(r.Context(), date)
+	if errors.Is(err, ErrPeriodClosed) {
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, period)
+}
+
+// ReopenPeriod handles POST /api/v1/periods/{date}/reopen
+func (h *Handler) ReopenPeriod(w http.ResponseWriter, r *http.Request) {
+	date := chi.URLParam(r, "date")
+
+	var req ReopenPeriodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	period, err := h.service.ReopenPeriod(r.Context(), date, req.Reason)
+	if errors.Is(err, ErrPeriodNotClosed) || errors.Is(err, ErrPeriodNotLatest) {
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, period)
+}
+
+// GetSettlement handles GET /api/v1/settlements?date=YYYY-MM-DD. The date defaults to today.
+func (h *Handler) GetSettlement(w http.ResponseWriter, r *http.Request) {
+	date := r.URL.Query().Get("date")
+	if date == "" {
+		date = calendar.FormatDate(calendar.Today())
+	}
+	achSide := r.URL.Query().Get("ach_side")
+
+	if err := ValidateSettlementQuery(date, achSide); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	settlement, err := h.service.GetSettlement(r.Context(), date, achSide)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get settlement")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, settlement)
+}
+
+// TransferSettlement handles POST /api/v1/settlements/{date}/transfer
+func (h *Handler) TransferSettlement(w http.ResponseWriter, r *http.Request) {
+	date := chi.URLParam(r, "date")
+
+	settlement, err := h.service.TransferSettlement(r.Context(), date)
+	if errors.Is(err, ErrPeriodClosed) {
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, settlement)
+}
+
+// ListHolds handles GET /api/v1/holds
+func (h *Handler) ListHolds(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	holds, err := h.service.ListHolds(r.Context(), q.Get("account"), q.Get("status"), q.Get("entry_id"), q.Get("trace_number"))
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list holds")
+		return
+	}
+
+	if holds == nil {
+		holds = []*Hold{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, holds)
+}
+
+// ReleaseHold handles POST /api/v1/holds/{id}/release
+func (h *Handler) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	hold, err := h.service.ReleaseHold(r.Context(), id)
+	if errors.Is(err, ErrHoldNotActive) {
+		commonhttp.Error(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to release hold")
+		return
+	}
+
+	if hold == nil {
+		commonhttp.Error(w, http.StatusNotFound, "hold not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, hold)
+}
+
+// ProcessEvent handles POST /api/v1/events, called by the ODFI and RDFI event dispatchers.
+// A newly applied status change returns 201; one processed before returns 200 with the
+// original result.
+func (h *Handler) ProcessEvent(w http.ResponseWriter, r *http.Request) {
+	var event events.EntryEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	processed, applied, err := h.service.ProcessEntryEvent(r.Context(), &event)
+	if err != nil {
+		commonhttp.ErrorFrom(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	status := http.StatusOK
+	if applied {
+		status = http.StatusCreated
+	}
+	commonhttp.JSON(w, status, processed)
+}
+
+// ListEvents handles GET /api/v1/events
+func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	entryID := r.URL.Query().Get("entry_id")
+	traceNumber := r.URL.Query().Get("trace_number")
+
+	processed, err := h.service.ListProcessedEvents(r.Context(), entryID, traceNumber)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list events")
+		return
+	}
+
+	if processed == nil {
+		processed = []*ProcessedEvent{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, processed)
+}
+
 // Health handles GET /healthz
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	commonhttp.Health(w)
@@ -4,31 +4,242 @@ import (
 	"time"
 )
 
-// LedgerEntry represents a ledger posting
+// Account represents an account in the chart of accounts
+type Account struct {
+	Code          string    `json:"code"`
+	Name          string    `json:"name"`
+	Type          string    `json:"type"`           // ASSET, LIABILITY or REVENUE
+	NormalBalance string    `json:"normal_balance"` // DEBIT or CREDIT
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// JournalEntry is a balanced set of ledger legs posted together. A reversal links back to
+// the entry it offsets, and a reversed entry links forward to its reversal.
+type JournalEntry struct {
+	ID                       string         `json:"id"`
+	AchSide                  string         `json:"ach_side"`
+	TraceNumber              string         `json:"trace_number"`
+	Description              string         `json:"description"`
+	EffectiveDate            string         `json:"effective_date"` // accounting date, YYYY-MM-DD
+	SettlementDate           string         `json:"settlement_date,omitempty"`
+	ReversesJournalEntryID   string         `json:"reverses_journal_entry_id,omitempty"`
+	ReversalReason           string         `json:"reversal_reason,omitempty"`
+	ReversedByJournalEntryID string         `json:"reversed_by_journal_entry_id,omitempty"`
+	CreatedAt                time.Time      `json:"created_at"`
+	Legs                     []*LedgerEntry `json:"legs"`
+}
+
+// LedgerEntry represents a ledger posting: one leg of a journal entry against a single account
 type LedgerEntry struct {
-	ID          string    `json:"id"`
-	AchSide     string    `json:"ach_side"`
-	TraceNumber string    `json:"trace_number"`
-	AmountCents int64     `json:"amount_cents"`
-	Direction   string    `json:"direction"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID             string    `json:"id"`
+	JournalEntryID string    `json:"journal_entry_id"`
+	AccountCode    string    `json:"account_code"`
+	AchSide        string    `json:"ach_side"`
+	TraceNumber    string    `json:"trace_number"`
+	AmountCents    int64     `json:"amount_cents"`
+	Direction      string    `json:"direction"`
+	Description    string    `json:"description"`
+	EffectiveDate  string    `json:"effective_date"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Reversal links of the leg's journal entry
+	ReversesJournalEntryID   string `json:"reverses_journal_entry_id,omitempty"`
+	ReversalReason           string `json:"reversal_reason,omitempty"`
+	ReversedByJournalEntryID string `json:"reversed_by_journal_entry_id,omitempty"`
+}
+
+// PostingFilter narrows a posting listing; empty fields match every posting. From and To
+// bound the effective date (YYYY-MM-DD), both inclusive.
+type PostingFilter struct {
+	AchSide     string
+	TraceNumber string
+	From        string
+	To          string
 }
 
-// CreatePostingRequest represents the request to create a ledger posting
+// CreatePostingRequest represents the request to create a ledger posting.
+// The posting is booked against the side's clearing account and offset against settlement.
 type CreatePostingRequest struct {
 	AchSide     string `json:"ach_side"`
 	TraceNumber string `json:"trace_number"`
 	AmountCents int64  `json:"amount_cents"`
 	Direction   string `json:"direction"`
 	Description string `json:"description"`
+
+	// EffectiveDate is optional (YYYY-MM-DD); defaults to the first open accounting day
+	EffectiveDate string `json:"effective_date"`
+}
+
+// ReversePostingRequest represents the request to reverse a posting
+type ReversePostingRequest struct {
+	Reason string `json:"reason"`
+}
+
+// CreateJournalEntryRequest represents the request to post a balanced journal entry
+type CreateJournalEntryRequest struct {
+	AchSide     string            `json:"ach_side"`
+	TraceNumber string            `json:"trace_number"`
+	Description string            `json:"description"`
+	Legs        []JournalLegInput `json:"legs"`
+
+	// EffectiveDate is optional (YYYY-MM-DD); defaults to the first open accounting day
+	EffectiveDate string `json:"effective_date"`
+	// SettlementDate is optional (YYYY-MM-DD); defaults to the effective date
+	SettlementDate string `json:"settlement_date"`
+}
+
+// JournalLegInput is one leg of a journal entry request
+type JournalLegInput struct {
+	AccountCode string `json:"account_code"`
+	Direction   string `json:"direction"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+// ProcessedEvent records how the ledger handled one entry status change
+type ProcessedEvent struct {
+	EventID        string    `json:"event_id"`
+	Side           string    `json:"side"`
+	EntryID        string    `json:"entry_id"`
+	TraceNumber    string    `json:"trace_number"`
+	Status         string    `json:"status"`
+	Action         string    `json:"action"` // POSTED, REVERSED, HELD or SKIPPED
+	JournalEntryID string    `json:"journal_entry_id,omitempty"`
+	ProcessedAt    time.Time `json:"processed_at"`
 }
 
-// BalanceResponse represents the balance calculation
+// BalanceResponse represents the balance calculation. Across all accounts debits always
+// equal credits, so the per-account balances carry the useful figures.
 type BalanceResponse struct {
-	TotalDebits  int64 `json:"total_debits"`
-	TotalCredits int64 `json:"total_credits"`
-	NetBalance   int64 `json:"net_balance"`
+	TotalDebits  int64            `json:"total_debits"`
+	TotalCredits int64            `json:"total_credits"`
+	NetBalance   int64            `json:"net_balance"`
+	Accounts     []AccountBalance `json:"accounts"`
+	Holds        []*Hold          `json:"holds"` // active holds behind the available balances
+
+	// SnapshotDate is the closed period the balances were computed from, if any
+	SnapshotDate string `json:"snapshot_date,omitempty"`
+}
+
+// AccountBalance is the balance of one account, signed in its normal direction. Balance
+// and LedgerBalance count every posting; AvailableBalance also subtracts active holds.
+type AccountBalance struct {
+	AccountCode      string `json:"account_code"`
+	AccountName      string `json:"account_name"`
+	NormalBalance    string `json:"normal_balance"`
+	TotalDebits      int64  `json:"total_debits"`
+	TotalCredits     int64  `json:"total_credits"`
+	Balance          int64  `json:"balance"`
+	LedgerBalance    int64  `json:"ledger_balance"`
+	HeldCents        int64  `json:"held_cents"`
+	AvailableBalance int64  `json:"available_balance"`
+}
+
+// Hold reserves an amount against an account for a pending entry. It stays ACTIVE until
+// the entry's next status change converts it (the change posted a journal entry) or
+// releases it, or until it expires.
+type Hold struct {
+	ID             string     `json:"id"`
+	AccountCode    string     `json:"account_code"`
+	AchSide        string     `json:"ach_side"`
+	EntryID        string     `json:"entry_id"`
+	TraceNumber    string     `json:"trace_number"`
+	AmountCents    int64      `json:"amount_cents"`
+	Status         string     `json:"status"` // ACTIVE, CONVERTED, RELEASED or EXPIRED
+	JournalEntryID string     `json:"journal_entry_id,omitempty"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+}
+
+// Period is a closed (or reopened) accounting day. Closing a day snapshots every
+// account's balance at its end and locks it, and every earlier day, against new postings.
+type Period struct {
+	Date         string             `json:"date"`
+	Status       string             `json:"status"` // CLOSED or REOPENED
+	ClosedAt     *time.Time         `json:"closed_at,omitempty"`
+	ReopenedAt   *time.Time         `json:"reopened_at,omitempty"`
+	ReopenReason string             `json:"reopen_reason,omitempty"`
+	Balances     []AccountBalance   `json:"balances,omitempty"`
+	History      []PeriodAuditEntry `json:"history,omitempty"`
+}
+
+// PeriodAuditEntry records one close or reopen of a period
+type PeriodAuditEntry struct {
+	Action     string    `json:"action"` // CLOSED or REOPENED
+	Reason     string    `json:"reason,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ReopenPeriodRequest represents the request to reopen a closed period
+type ReopenPeriodRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Settlement is the position against the ACH operator for one settlement date, per side,
+// and the transfer journal entries posted for it
+type Settlement struct {
+	SettlementDate string               `json:"settlement_date"`
+	Positions      []SettlementPosition `json:"positions"`
+	Transfers      []*JournalEntry      `json:"transfers,omitempty"`
+}
+
+// SettlementPosition sums one side's postings to the settlement account for a settlement
+// date. Debits are amounts due from the ACH operator and credits amounts due to it, so a
+// positive net position is owed to us. Transfers move the net position out of the
+// settlement account; what remains is outstanding.
+type SettlementPosition struct {
+	AchSide           string     `json:"ach_side"`
+	GrossDebits       int64      `json:"gross_debits"`
+	GrossCredits      int64      `json:"gross_credits"`
+	NetPosition       int64      `json:"net_position"`
+	JournalEntryCount int64      `json:"journal_entry_count"`
+	TransferredCents  int64      `json:"transferred_cents"`
+	OutstandingCents  int64      `json:"outstanding_cents"`
+	LastTransferAt    *time.Time `json:"last_transfer_at,omitempty"`
+}
+
+// Statement is the activity of one or more accounts over a range of accounting days,
+// day by day, as rendered into BAI2 or camt.053
+type Statement struct {
+	From        string         `json:"from"`
+	To          string         `json:"to"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Days        []StatementDay `json:"days"`
+}
+
+// StatementDay holds every account's statement for one accounting day
+type StatementDay struct {
+	Date     string             `json:"date"`
+	Accounts []AccountStatement `json:"accounts"`
+}
+
+// AccountStatement is one account's opening and closing balance for a day and the legs
+// posted in between. Balances are signed in the account's normal direction, so legs in
+// that direction are statement credits and the others statement debits.
+type AccountStatement struct {
+	AccountCode    string         `json:"account_code"`
+	AccountName    string         `json:"account_name"`
+	NormalBalance  string         `json:"normal_balance"`
+	OpeningBalance int64          `json:"opening_balance"`
+	ClosingBalance int64          `json:"closing_balance"`
+	Entries        []*LedgerEntry `json:"entries"`
+}
+
+// BalanceSeriesResponse is a running balance per account at the end of each period
+type BalanceSeriesResponse struct {
+	Interval string         `json:"interval"`
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	Points   []BalancePoint `json:"points"`
+}
+
+// BalancePoint is one account's activity in a period and its balance at the period's end
+type BalancePoint struct {
+	PeriodStart   string `json:"period_start"` // YYYY-MM-DD, Eastern time
+	AccountCode   string `json:"account_code"`
+	PeriodDebits  int64  `json:"period_debits"`
+	PeriodCredits int64  `json:"period_credits"`
+	Balance       int64  `json:"balance"`
 }
 
 // Direction constants
@@ -43,3 +254,54 @@ const (
 	SideRDFI = "RDFI"
 )
 
+// Chart of accounts
+const (
+	AccountSettlement         = "SETTLEMENT"
+	AccountOriginatorClearing = "ORIGINATOR_CLEARING"
+	AccountReceiverDDA        = "RECEIVER_DDA"
+	AccountReturnsSuspense    = "RETURNS_SUSPENSE"
+	AccountFeeIncome          = "FEE_INCOME"
+	AccountFedReserve         = "FED_RESERVE"
+)
+
+// Processed event action constants
+const (
+	EventActionPosted   = "POSTED"
+	EventActionReversed = "REVERSED"
+	EventActionHeld     = "HELD"
+	EventActionSkipped  = "SKIPPED"
+)
+
+// Hold status constants
+const (
+	HoldStatusActive    = "ACTIVE"
+	HoldStatusConverted = "CONVERTED"
+	HoldStatusReleased  = "RELEASED"
+	HoldStatusExpired   = "EXPIRED"
+)
+
+// Period status constants
+const (
+	PeriodStatusClosed   = "CLOSED"
+	PeriodStatusReopened = "REOPENED"
+)
+
+// Statement format constants
+const (
+	StatementFormatBAI2    = "bai2"
+	StatementFormatCamt053 = "camt053"
+)
+
+// Balance series interval constants
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// Account type constants
+const (
+	AccountTypeAsset     = "ASSET"
+	AccountTypeLiability = "LIABILITY"
+	AccountTypeRevenue   = "REVENUE"
+)
@@ -3,12 +3,41 @@ package ledger
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+
+	"ach-concourse/internal/common/calendar"
+	"ach-concourse/internal/common/events"
+	"ach-concourse/internal/common/pagination"
+)
+
+// Reversal errors
+var (
+	ErrAlreadyReversed    = errors.New("posting has already been reversed")
+	ErrReversalOfReversal = errors.New("a reversal cannot itself be reversed")
+)
+
+// ErrHoldNotActive is returned when releasing a hold that was already resolved or expired
+var ErrHoldNotActive = errors.New("hold is not active")
+
+// Period errors
+var (
+	ErrPeriodClosed    = errors.New("accounting period is closed")
+	ErrPeriodNotClosed = errors.New("accounting period is not closed")
+	ErrPeriodNotLatest = errors.New("only the most recently closed period can be reopened")
 )
 
+// periodLockKey is the advisory lock that serializes period closes against postings.
+// Postings hold it shared; closing and reopening hold it exclusively.
+const periodLockKey int64 = 0x6c65646765720001
+
+// settlementLockKey serializes settlement transfers so each outstanding position is
+// transferred once
+const settlementLockKey int64 = 0x6c65646765720002
+
 // Repository handles database operations for ledger entries
 type Repository struct {
 	db *sql.DB
@@ -20,6 +49,30 @@ func NewRepository(db *sql.DB) *Repository {
 }
 
 const schema = `
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+	code TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	type TEXT NOT NULL,
+	normal_balance TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO ledger_accounts (code, name, type, normal_balance) VALUES
+	('SETTLEMENT', 'Federal Reserve settlement', 'ASSET', 'DEBIT'),
+	('ORIGINATOR_CLEARING', 'Originator clearing', 'LIABILITY', 'CREDIT'),
+	('RECEIVER_DDA', 'Receiver demand deposit accounts', 'LIABILITY', 'CREDIT'),
+	('RETURNS_SUSPENSE', 'Returns suspense', 'LIABILITY', 'CREDIT'),
+	('FEE_INCOME', 'ACH fee income', 'REVENUE', 'CREDIT')
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+	id UUID PRIMARY KEY,
+	ach_side TEXT NOT NULL,
+	trace_number TEXT,
+	description TEXT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
 CREATE TABLE IF NOT EXISTS ledger_entries (
 	id UUID PRIMARY KEY,
 	ach_side TEXT NOT NULL,
@@ -30,9 +83,175 @@ CREATE TABLE IF NOT EXISTS ledger_entries (
 	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
 
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS journal_entry_id UUID REFERENCES journal_entries(id);
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS account_code TEXT REFERENCES ledger_accounts(code);
+
+-- Single-sided postings written before the chart of accounts existed become two-leg
+-- journal entries: the original row against its side's account, offset by settlement
+INSERT INTO journal_entries (id, ach_side, trace_number, description, created_at)
+SELECT id, ach_side, trace_number, description, created_at
+FROM ledger_entries WHERE journal_entry_id IS NULL
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO ledger_entries (id, journal_entry_id, account_code, ach_side, trace_number, amount_cents, direction, description, created_at)
+SELECT gen_random_uuid(), id, 'SETTLEMENT', ach_side, trace_number, amount_cents,
+	CASE direction WHEN 'DEBIT' THEN 'CREDIT' ELSE 'DEBIT' END, description, created_at
+FROM ledger_entries WHERE journal_entry_id IS NULL;
+
+UPDATE ledger_entries
+SET journal_entry_id = id,
+	account_code = CASE ach_side WHEN 'ODFI' THEN 'ORIGINATOR_CLEARING' ELSE 'RECEIVER_DDA' END
+WHERE journal_entry_id IS NULL;
+
+ALTER TABLE ledger_entries ALTER COLUMN journal_entry_id SET NOT NULL;
+ALTER TABLE ledger_entries ALTER COLUMN account_code SET NOT NULL;
+
 CREATE INDEX IF NOT EXISTS idx_ledger_entries_ach_side ON ledger_entries(ach_side);
 CREATE INDEX IF NOT EXISTS idx_ledger_entries_trace_number ON ledger_entries(trace_number);
 CREATE INDEX IF NOT EXISTS idx_ledger_entries_direction ON ledger_entries(direction);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_journal_entry_id ON ledger_entries(journal_entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account_code ON ledger_entries(account_code);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_trace_number ON journal_entries(trace_number);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account_created_at ON ledger_entries(account_code, created_at);
+
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS reverses_id UUID REFERENCES journal_entries(id);
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS reversal_reason TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_entries_reverses_id ON journal_entries(reverses_id) WHERE reverses_id IS NOT NULL;
+
+-- Postings are append-only: corrections are made with reversing journal entries
+CREATE OR REPLACE FUNCTION ledger_reject_modification() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION '% is append-only; post a reversal instead', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS ledger_entries_append_only ON ledger_entries;
+CREATE TRIGGER ledger_entries_append_only
+	BEFORE UPDATE OR DELETE ON ledger_entries
+	FOR EACH ROW EXECUTE FUNCTION ledger_reject_modification();
+
+DROP TRIGGER IF EXISTS journal_entries_append_only ON journal_entries;
+CREATE TRIGGER journal_entries_append_only
+	BEFORE UPDATE OR DELETE ON journal_entries
+	FOR EACH ROW EXECUTE FUNCTION ledger_reject_modification();
+
+DROP TRIGGER IF EXISTS ledger_entries_no_truncate ON ledger_entries;
+CREATE TRIGGER ledger_entries_no_truncate
+	BEFORE TRUNCATE ON ledger_entries
+	FOR EACH STATEMENT EXECUTE FUNCTION ledger_reject_modification();
+
+DROP TRIGGER IF EXISTS journal_entries_no_truncate ON journal_entries;
+CREATE TRIGGER journal_entries_no_truncate
+	BEFORE TRUNCATE ON journal_entries
+	FOR EACH STATEMENT EXECUTE FUNCTION ledger_reject_modification();
+
+-- Entry status changes already applied; one row per entry and status makes posting
+-- from lifecycle events exactly-once
+CREATE TABLE IF NOT EXISTS ledger_entry_events (
+	event_id UUID PRIMARY KEY,
+	side TEXT NOT NULL,
+	entry_id UUID NOT NULL,
+	trace_number TEXT,
+	status TEXT NOT NULL,
+	action TEXT NOT NULL,
+	journal_entry_id UUID REFERENCES journal_entries(id),
+	processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	UNIQUE (side, entry_id, status)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entry_events_trace_number ON ledger_entry_events(trace_number);
+
+-- Accounting dates. Rows written before they existed take the Eastern date they were
+-- recorded on. The backfill runs once, while the columns are still nullable, and is the only
+-- time the append-only triggers are suspended.
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS effective_date DATE;
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS effective_date DATE;
+
+DO $$
+BEGIN
+	IF EXISTS (
+		SELECT 1 FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name IN ('journal_entries', 'ledger_entries')
+			AND column_name = 'effective_date' AND is_nullable = 'YES'
+	) THEN
+		ALTER TABLE journal_entries DISABLE TRIGGER journal_entries_append_only;
+		ALTER TABLE ledger_entries DISABLE TRIGGER ledger_entries_append_only;
+		UPDATE journal_entries SET effective_date = (created_at AT TIME ZONE 'America/New_York')::date WHERE effective_date IS NULL;
+		UPDATE ledger_entries SET effective_date = (created_at AT TIME ZONE 'America/New_York')::date WHERE effective_date IS NULL;
+		ALTER TABLE journal_entries ENABLE TRIGGER journal_entries_append_only;
+		ALTER TABLE ledger_entries ENABLE TRIGGER ledger_entries_append_only;
+
+		ALTER TABLE journal_entries ALTER COLUMN effective_date SET NOT NULL;
+		ALTER TABLE ledger_entries ALTER COLUMN effective_date SET NOT NULL;
+	END IF;
+END $$;
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_account_effective_date ON ledger_entries(account_code, effective_date);
+
+CREATE TABLE IF NOT EXISTS ledger_periods (
+	period_date DATE PRIMARY KEY,
+	status TEXT NOT NULL,
+	closed_at TIMESTAMPTZ,
+	reopened_at TIMESTAMPTZ,
+	reopen_reason TEXT
+);
+
+CREATE TABLE IF NOT EXISTS ledger_period_audit (
+	id UUID PRIMARY KEY,
+	period_date DATE NOT NULL REFERENCES ledger_periods(period_date),
+	action TEXT NOT NULL,
+	reason TEXT,
+	occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_period_audit_period_date ON ledger_period_audit(period_date);
+
+-- Cumulative debits and credits per account and side through the end of a closed period
+CREATE TABLE IF NOT EXISTS ledger_balance_snapshots (
+	period_date DATE NOT NULL REFERENCES ledger_periods(period_date),
+	account_code TEXT NOT NULL REFERENCES ledger_accounts(code),
+	ach_side TEXT NOT NULL,
+	total_debits BIGINT NOT NULL,
+	total_credits BIGINT NOT NULL,
+	PRIMARY KEY (period_date, account_code, ach_side)
+);
+
+-- Settlement. Journal entries written before settlement dates existed settle on their
+-- effective date; net positions are transferred from SETTLEMENT to the Fed master account.
+INSERT INTO ledger_accounts (code, name, type, normal_balance) VALUES
+	('FED_RESERVE', 'Federal Reserve master account', 'ASSET', 'DEBIT')
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE journal_entries ADD COLUMN IF NOT EXISTS settlement_date DATE;
+CREATE INDEX IF NOT EXISTS idx_journal_entries_settlement_date ON journal_entries(settlement_date);
+
+CREATE TABLE IF NOT EXISTS ledger_settlement_transfers (
+	id UUID PRIMARY KEY,
+	settlement_date DATE NOT NULL,
+	ach_side TEXT NOT NULL,
+	journal_entry_id UUID NOT NULL UNIQUE REFERENCES journal_entries(id),
+	amount_cents BIGINT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_settlement_transfers_date ON ledger_settlement_transfers(settlement_date, ach_side);
+
+-- Amount holds placed by hold rules; an ACTIVE hold past expires_at is reported as EXPIRED
+CREATE TABLE IF NOT EXISTS ledger_holds (
+	id UUID PRIMARY KEY,
+	account_code TEXT NOT NULL REFERENCES ledger_accounts(code),
+	ach_side TEXT NOT NULL,
+	entry_id UUID NOT NULL,
+	trace_number TEXT,
+	amount_cents BIGINT NOT NULL,
+	status TEXT NOT NULL,
+	journal_entry_id UUID REFERENCES journal_entries(id),
+	expires_at TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_holds_entry_id ON ledger_holds(entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_holds_active ON ledger_holds(account_code, expires_at) WHERE status = 'ACTIVE';
 `
 
 // GetSchema returns the SQL schema for ledger tables
@@ -40,37 +259,390 @@ func GetSchema() string {
 	return schema
 }
 
-// Create creates a new ledger entry
-func (r *Repository) Create(ctx context.Context, entry *LedgerEntry) error {
-	entry.ID = uuid.New().String()
-	entry.CreatedAt = time.Now()
+const legColumns = `id, journal_entry_id, account_code, ach_side, trace_number, amount_cents, direction, description, effective_date, created_at`
+
+// legSelect reads legs together with the reversal links of their journal entry
+const legSelect = `
+	SELECT ` + legSelectColumns + `
+	` + legFrom
+
+// legSelectColumns and legFrom are the two halves of legSelect, for queries that select
+// more columns after the leg's
+const legSelectColumns = `l.id, l.journal_entry_id, l.account_code, l.ach_side, l.trace_number, l.amount_cents, l.direction, l.description, l.effective_date, l.created_at,
+		j.reverses_id, j.reversal_reason, rev.id`
+
+const legFrom = `FROM ledger_entries l
+	JOIN journal_entries j ON j.id = l.journal_entry_id
+	LEFT JOIN journal_entries rev ON rev.reverses_id = l.journal_entry_id`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanLeg scans a row selected with legSelect into a LedgerEntry. Columns selected after
+// legSelect's are scanned into extra.
+func scanLeg(row rowScanner, extra ...any) (*LedgerEntry, error) {
+	entry := &LedgerEntry{}
+	var traceNumber, description, reversesID, reversalReason, reversedByID sql.NullString
+	var effectiveDate time.Time
+
+	dest := []any{
+		&entry.ID, &entry.JournalEntryID, &entry.AccountCode, &entry.AchSide, &traceNumber,
+		&entry.AmountCents, &entry.Direction, &description,
+		&effectiveDate, &entry.CreatedAt,
+		&reversesID, &reversalReason, &reversedByID,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	entry.EffectiveDate = effectiveDate.Format(calendar.DateLayout)
+	entry.TraceNumber = traceNumber.String
+	entry.Description = description.String
+	entry.ReversesJournalEntryID = reversesID.String
+	entry.ReversalReason = reversalReason.String
+	entry.ReversedByJournalEntryID = reversedByID.String
+
+	return entry, nil
+}
+
+// ListAccounts retrieves the chart of accounts
+func (r *Repository) ListAccounts(ctx context.Context) ([]*Account, error) {
+	query := `SELECT code, name, type, normal_balance, created_at FROM ledger_accounts ORDER BY code`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*Account
+	for rows.Next() {
+		account := &Account{}
+		if err := rows.Scan(&account.Code, &account.Name, &account.Type, &account.NormalBalance, &account.CreatedAt); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// CreateJournalEntry inserts a journal entry and all of its legs in one transaction
+func (r *Repository) CreateJournalEntry(ctx context.Context, journal *JournalEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertJournalEntry(ctx, tx, journal); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertJournalEntry writes a journal entry and its legs inside tx. An empty effective
+// date becomes the first open accounting day; an explicit one inside a closed period is
+// rejected with ErrPeriodClosed.
+func insertJournalEntry(ctx context.Context, tx *sql.Tx, journal *JournalEntry) error {
+	// Hold the period lock shared so that a close waits for postings in flight
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock_shared($1)`, periodLockKey); err != nil {
+		return err
+	}
+	lastClose, err := closedThrough(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if journal.EffectiveDate == "" {
+		journal.EffectiveDate = firstOpenDate(lastClose)
+	} else if lastClose != "" && journal.EffectiveDate <= lastClose {
+		return fmt.Errorf("%w: %s is on or before the last close (%s)", ErrPeriodClosed, journal.EffectiveDate, lastClose)
+	}
+	if journal.SettlementDate == "" {
+		journal.SettlementDate = journal.EffectiveDate
+	}
+
+	journal.ID = uuid.New().String()
+	journal.CreatedAt = time.Now()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO journal_entries (id, ach_side, trace_number, description, reverses_id, reversal_reason, effective_date, settlement_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, journal.ID, journal.AchSide, journal.TraceNumber, journal.Description,
+		nullString(journal.ReversesJournalEntryID), nullString(journal.ReversalReason),
+		journal.EffectiveDate, journal.SettlementDate, journal.CreatedAt)
+	if err != nil {
+		return err
+	}
 
 	query := `
-		INSERT INTO ledger_entries (id, ach_side, trace_number, amount_cents, direction, description, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO ledger_entries (` + legColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
+	for _, leg := range journal.Legs {
+		leg.ID = uuid.New().String()
+		leg.JournalEntryID = journal.ID
+		leg.AchSide = journal.AchSide
+		leg.TraceNumber = journal.TraceNumber
+		leg.Description = journal.Description
+		leg.ReversesJournalEntryID = journal.ReversesJournalEntryID
+		leg.ReversalReason = journal.ReversalReason
+		leg.EffectiveDate = journal.EffectiveDate
+		leg.CreatedAt = journal.CreatedAt
 
-	_, err := r.db.ExecContext(ctx, query,
-		entry.ID, entry.AchSide, entry.TraceNumber,
-		entry.AmountCents, entry.Direction, entry.Description,
-		entry.CreatedAt)
+		_, err := tx.ExecContext(ctx, query,
+			leg.ID, leg.JournalEntryID, leg.AccountCode, leg.AchSide, leg.TraceNumber,
+			leg.AmountCents, leg.Direction, leg.Description,
+			leg.EffectiveDate, leg.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
 
-	return err
+	return nil
+}
+
+// ReverseJournalEntry posts a journal entry that mirrors the one containing id (a journal
+// entry ID or the ID of one of its legs) with every direction flipped, linked back to it.
+// It returns (nil, nil) when nothing matches id, ErrAlreadyReversed if a reversal already
+// exists and ErrReversalOfReversal if the target is itself a reversal.
+func (r *Repository) ReverseJournalEntry(ctx context.Context, id, reason string) (*JournalEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reversal, err := reverseJournalEntry(ctx, tx, id, reason)
+	if err != nil || reversal == nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+// reverseJournalEntry posts the reversal of id inside tx; see ReverseJournalEntry
+func reverseJournalEntry(ctx context.Context, tx *sql.Tx, id, reason string) (*JournalEntry, error) {
+	// Lock the original so concurrent reversals serialize on it
+	var original JournalEntry
+	var traceNumber, description, reversesID sql.NullString
+	err := tx.QueryRowContext(ctx, `
+		SELECT id, ach_side, trace_number, description, reverses_id
+		FROM journal_entries
+		WHERE id = (SELECT journal_entry_id FROM ledger_entries WHERE id = $1)
+			OR id = $1
+		FOR UPDATE
+	`, id).Scan(&original.ID, &original.AchSide, &traceNumber, &description, &reversesID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if reversesID.Valid {
+		return nil, ErrReversalOfReversal
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM journal_entries WHERE reverses_id = $1)`, original.ID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadyReversed
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT account_code, direction, amount_cents
+		FROM ledger_entries WHERE journal_entry_id = $1
+		ORDER BY direction DESC, account_code
+	`, original.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reversal := &JournalEntry{
+		AchSide:                original.AchSide,
+		TraceNumber:            traceNumber.String,
+		Description:            "Reversal: " + description.String,
+		ReversesJournalEntryID: original.ID,
+		ReversalReason:         reason,
+	}
+	for rows.Next() {
+		leg := &LedgerEntry{}
+		if err := rows.Scan(&leg.AccountCode, &leg.Direction, &leg.AmountCents); err != nil {
+			return nil, err
+		}
+		if leg.Direction == DirectionDebit {
+			leg.Direction = DirectionCredit
+		} else {
+			leg.Direction = DirectionDebit
+		}
+		reversal.Legs = append(reversal.Legs, leg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := insertJournalEntry(ctx, tx, reversal); err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+// ApplyEntryEvent applies an entry status change exactly once. In one transaction it
+// claims the change, then posts journal (when not nil) or reverses the journal entry
+// posted for reversesStatus (when set). It then places hold (when not nil), or else
+// resolves the entry's active holds: converted if the change posted, released otherwise.
+// A change that was already applied, under this event ID or another, is not applied
+// again: the earlier result is returned with applied set to false.
+func (r *Repository) ApplyEntryEvent(ctx context.Context, event *events.EntryEvent, journal *JournalEntry, reversesStatus, reason string, hold *Hold) (processed *ProcessedEvent, applied bool, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	processed = &ProcessedEvent{
+		EventID:     event.ID,
+		Side:        event.Side,
+		EntryID:     event.EntryID,
+		TraceNumber: event.TraceNumber,
+		Status:      event.Status,
+		Action:      EventActionSkipped,
+		ProcessedAt: time.Now(),
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO ledger_entry_events (event_id, side, entry_id, trace_number, status, action, processed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT DO NOTHING
+	`, processed.EventID, processed.Side, processed.EntryID, nullString(processed.TraceNumber),
+		processed.Status, processed.Action, processed.ProcessedAt)
+	if err != nil {
+		return nil, false, err
+	}
+	if inserted, _ := result.RowsAffected(); inserted == 0 {
+		tx.Rollback()
+		existing, err := r.getProcessedEvent(ctx, event)
+		return existing, false, err
+	}
+
+	switch {
+	case journal != nil:
+		if err := insertJournalEntry(ctx, tx, journal); err != nil {
+			return nil, false, err
+		}
+		processed.Action = EventActionPosted
+		processed.JournalEntryID = journal.ID
+
+	case reversesStatus != "":
+		var originalID sql.NullString
+		err := tx.QueryRowContext(ctx, `
+			SELECT journal_entry_id FROM ledger_entry_events
+			WHERE side = $1 AND entry_id = $2 AND status = $3
+		`, event.Side, event.EntryID, reversesStatus).Scan(&originalID)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, false, err
+		}
+
+		// Nothing was posted for the earlier status, or it was already reversed by hand
+		if originalID.Valid {
+			reversal, err := reverseJournalEntry(ctx, tx, originalID.String, reason)
+			if err != nil && !errors.Is(err, ErrAlreadyReversed) {
+				return nil, false, err
+			}
+			if reversal != nil {
+				processed.Action = EventActionReversed
+				processed.JournalEntryID = reversal.ID
+			}
+		}
+	}
+
+	if hold != nil {
+		if err := insertHold(ctx, tx, hold); err != nil {
+			return nil, false, err
+		}
+		processed.Action = EventActionHeld
+	} else {
+		status := HoldStatusReleased
+		if processed.Action == EventActionPosted {
+			status = HoldStatusConverted
+		}
+		_, err := tx.ExecContext(ctx, `
+			UPDATE ledger_holds SET status = $3, journal_entry_id = $4, resolved_at = $5
+			WHERE ach_side = $1 AND entry_id = $2 AND status = 'ACTIVE'
+		`, event.Side, event.EntryID, status, nullString(processed.JournalEntryID), processed.ProcessedAt)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE ledger_entry_events SET action = $2, journal_entry_id = $3 WHERE event_id = $1
+	`, processed.EventID, processed.Action, nullString(processed.JournalEntryID))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	return processed, true, nil
+}
+
+const processedEventColumns = `event_id, side, entry_id, trace_number, status, action, journal_entry_id, processed_at`
+
+func scanProcessedEvent(row rowScanner) (*ProcessedEvent, error) {
+	processed := &ProcessedEvent{}
+	var traceNumber, journalEntryID sql.NullString
+
+	err := row.Scan(&processed.EventID, &processed.Side, &processed.EntryID, &traceNumber,
+		&processed.Status, &processed.Action, &journalEntryID, &processed.ProcessedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	processed.TraceNumber = traceNumber.String
+	processed.JournalEntryID = journalEntryID.String
+
+	return processed, nil
 }
 
-// List retrieves ledger entries with optional filters
-func (r *Repository) List(ctx context.Context, achSide, traceNumber string) ([]*LedgerEntry, error) {
+// getProcessedEvent finds the record of an event, or of the same entry status change
+// delivered under another event ID
+func (r *Repository) getProcessedEvent(ctx context.Context, event *events.EntryEvent) (*ProcessedEvent, error) {
 	query := `
-		SELECT id, ach_side, trace_number, amount_cents, direction, description, created_at
-		FROM ledger_entries
-		WHERE 1=1
+		SELECT ` + processedEventColumns + `
+		FROM ledger_entry_events
+		WHERE event_id = $1 OR (side = $2 AND entry_id = $3 AND status = $4)
+		LIMIT 1
 	`
+
+	return scanProcessedEvent(r.db.QueryRowContext(ctx, query, event.ID, event.Side, event.EntryID, event.Status))
+}
+
+// ListProcessedEvents retrieves processed entry status changes, newest first, optionally
+// filtered by entry ID and trace number
+func (r *Repository) ListProcessedEvents(ctx context.Context, entryID, traceNumber string) ([]*ProcessedEvent, error) {
+	query := `SELECT ` + processedEventColumns + ` FROM ledger_entry_events WHERE 1=1`
 	args := []interface{}{}
 	argNum := 1
 
-	if achSide != "" {
-		query += fmt.Sprintf(" AND ach_side = $%d", argNum)
-		args = append(args, achSide)
+	if entryID != "" {
+		query += fmt.Sprintf(" AND entry_id = $%d", argNum)
+		args = append(args, entryID)
 		argNum++
 	}
 
@@ -80,7 +652,7 @@ func (r *Repository) List(ctx context.Context, achSide, traceNumber string) ([]*
 		argNum++
 	}
 
-	query += " ORDER BY created_at DESC"
+	query += " ORDER BY processed_at DESC"
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -88,41 +660,1054 @@ func (r *Repository) List(ctx context.Context, achSide, traceNumber string) ([]*
 	}
 	defer rows.Close()
 
-	var entries []*LedgerEntry
+	var processed []*ProcessedEvent
 	for rows.Next() {
-		entry := &LedgerEntry{}
-		err := rows.Scan(
-			&entry.ID, &entry.AchSide, &entry.TraceNumber,
-			&entry.AmountCents, &entry.Direction, &entry.Description,
-			&entry.CreatedAt)
+		event, err := scanProcessedEvent(rows)
 		if err != nil {
 			return nil, err
 		}
-		entries = append(entries, entry)
+		processed = append(processed, event)
 	}
 
-	return entries, rows.Err()
+	return processed, rows.Err()
 }
 
-// GetBalances calculates total debits, credits, and net balance
-func (r *Repository) GetBalances(ctx context.Context) (*BalanceResponse, error) {
+// GetJournalEntry retrieves a journal entry with its legs and reversal links
+func (r *Repository) GetJournalEntry(ctx context.Context, id string) (*JournalEntry, error) {
 	query := `
-		SELECT 
-			COALESCE(SUM(CASE WHEN direction = 'DEBIT' THEN amount_cents ELSE 0 END), 0) as total_debits,
-			COALESCE(SUM(CASE WHEN direction = 'CREDIT' THEN amount_cents ELSE 0 END), 0) as total_credits
-		FROM ledger_entries
+		SELECT j.id, j.ach_side, j.trace_number, j.description, j.reverses_id, j.reversal_reason, rev.id,
+			j.effective_date, COALESCE(j.settlement_date, j.effective_date), j.created_at
+		FROM journal_entries j
+		LEFT JOIN journal_entries rev ON rev.reverses_id = j.id
+		WHERE j.id = $1
 	`
 
-	var totalDebits, totalCredits int64
-	err := r.db.QueryRowContext(ctx, query).Scan(&totalDebits, &totalCredits)
+	journal := &JournalEntry{}
+	var traceNumber, description, reversesID, reversalReason, reversedByID sql.NullString
+	var effectiveDate, settlementDate time.Time
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&journal.ID, &journal.AchSide, &traceNumber, &description,
+		&reversesID, &reversalReason, &reversedByID, &effectiveDate, &settlementDate, &journal.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	journal.TraceNumber = traceNumber.String
+	journal.Description = description.String
+	journal.EffectiveDate = effectiveDate.Format(calendar.DateLayout)
+	journal.SettlementDate = settlementDate.Format(calendar.DateLayout)
+	journal.ReversesJournalEntryID = reversesID.String
+	journal.ReversalReason = reversalReason.String
+	journal.ReversedByJournalEntryID = reversedByID.String
+
+	journal.Legs, err = r.listLegs(ctx, legSelect+` WHERE l.journal_entry_id = $1 ORDER BY l.direction DESC, l.account_code`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return journal, nil
+}
+
+// ListJournalEntries retrieves journal entries with their legs, with optional filters
+func (r *Repository) ListJournalEntries(ctx context.Context, achSide, traceNumber string) ([]*JournalEntry, error) {
+	clause, args, _ := legFilters(achSide, traceNumber, time.Time{}, 1)
+	legs, err := r.listLegs(ctx, legSelect+`
+		WHERE 1=1`+clause+`
+		ORDER BY l.created_at DESC, l.journal_entry_id, l.direction DESC, l.account_code`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Legs come back newest first; group them under their journal entries in that order
+	var journals []*JournalEntry
+	byID := make(map[string]*JournalEntry)
+	for _, leg := range legs {
+		journal, ok := byID[leg.JournalEntryID]
+		if !ok {
+			journal = &JournalEntry{
+				ID:                       leg.JournalEntryID,
+				AchSide:                  leg.AchSide,
+				TraceNumber:              leg.TraceNumber,
+				Description:              leg.Description,
+				EffectiveDate:            leg.EffectiveDate,
+				ReversesJournalEntryID:   leg.ReversesJournalEntryID,
+				ReversalReason:           leg.ReversalReason,
+				ReversedByJournalEntryID: leg.ReversedByJournalEntryID,
+				CreatedAt:                leg.CreatedAt,
+			}
+			byID[journal.ID] = journal
+			journals = append(journals, journal)
+		}
+		journal.Legs = append(journal.Legs, leg)
+	}
+
+	return journals, nil
+}
+
+// PostingSortFields are the columns a posting listing can be sorted by
+var PostingSortFields = map[string]pagination.Field{
+	"created_at":     {Column: "l.created_at", Type: "TIMESTAMPTZ"},
+	"effective_date": {Column: "l.effective_date", Type: "DATE"},
+	"amount_cents":   {Column: "l.amount_cents", Type: "BIGINT"},
+}
+
+// List retrieves a page of ledger entries with optional filters. The total across all pages
+// is counted when page.IncludeTotal is set.
+func (r *Repository) List(ctx context.Context, filter PostingFilter, page pagination.Params) (*pagination.Page[*LedgerEntry], error) {
+	clause, args, argNum := legFilters(filter.AchSide, filter.TraceNumber, time.Time{}, 1)
+
+	if filter.From != "" {
+		clause += fmt.Sprintf(" AND l.effective_date >= $%d", argNum)
+		args = append(args, filter.From)
+		argNum++
+	}
+
+	if filter.To != "" {
+		clause += fmt.Sprintf(" AND l.effective_date <= $%d", argNum)
+		args = append(args, filter.To)
+		argNum++
+	}
+
+	query, pageArgs := page.Query(`
+		SELECT `+legSelectColumns+`, `+page.SortKey(PostingSortFields)+`
+		`+legFrom+`
+		WHERE 1=1`+clause, PostingSortFields, "l.id", argNum)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, pageArgs...)...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	var entries []*LedgerEntry
+	var keys, ids []string
+	for rows.Next() {
+		var key string
+		entry, err := scanLeg(rows, &key)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+		keys = append(keys, key)
+		ids = append(ids, entry.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &pagination.Page[*LedgerEntry]{}
+	result.Data, result.NextCursor = pagination.Trim(page, entries, keys, ids)
+	if result.Data == nil {
+		result.Data = []*LedgerEntry{}
+	}
+
+	if page.IncludeTotal {
+		var total int64
+		if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM ledger_entries l WHERE 1=1`+clause, args...).Scan(&total); err != nil {
+			return nil, err
+		}
+		result.TotalCount = &total
+	}
 
-	return &BalanceResponse{
-		TotalDebits:  totalDebits,
-		TotalCredits: totalCredits,
-		NetBalance:   totalCredits - totalDebits,
-	}, nil
+	return result, nil
 }
 
+func (r *Repository) listLegs(ctx context.Context, query string, args ...any) ([]*LedgerEntry, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*LedgerEntry
+	for rows.Next() {
+		entry, err := scanLeg(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// legFilters builds the optional ach_side/trace_number/as_of conditions on ledger_entries l,
+// numbering placeholders from argNum. A non-zero asOf keeps legs dated on or before its
+// Eastern date and recorded by asOf itself.
+func legFilters(achSide, traceNumber string, asOf time.Time, argNum int) (string, []interface{}, int) {
+	clause := ""
+	args := []interface{}{}
+
+	if achSide != "" {
+		clause += fmt.Sprintf(" AND l.ach_side = $%d", argNum)
+		args = append(args, achSide)
+		argNum++
+	}
+
+	if traceNumber != "" {
+		clause += fmt.Sprintf(" AND l.trace_number = $%d", argNum)
+		args = append(args, traceNumber)
+		argNum++
+	}
+
+	if !asOf.IsZero() {
+		clause += fmt.Sprintf(" AND l.effective_date <= $%d AND l.created_at <= $%d", argNum, argNum+1)
+		args = append(args, calendar.FormatDate(asOf), asOf)
+		argNum += 2
+	}
+
+	return clause, args, argNum
+}
+
+// snapshotFor returns the latest closed period whose snapshot can stand in for the legs
+// up to its end when computing balances as of asOf, or "" if there is none. The period
+// must end on or before asOf's date and have been closed by asOf, so that every leg it
+// covers was recorded by then. A zero asOf accepts any closed period.
+func (r *Repository) snapshotFor(ctx context.Context, asOf time.Time) (string, error) {
+	query := `SELECT MAX(period_date) FROM ledger_periods WHERE status = $1`
+	args := []interface{}{PeriodStatusClosed}
+
+	if !asOf.IsZero() {
+		query += " AND period_date <= $2 AND closed_at <= $3"
+		args = append(args, calendar.FormatDate(asOf), asOf)
+	}
+
+	var date sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&date); err != nil {
+		return "", err
+	}
+	if !date.Valid {
+		return "", nil
+	}
+	return date.Time.Format(calendar.DateLayout), nil
+}
+
+// GetBalances calculates total debits, credits, and net balance overall and per account.
+// Legs can be narrowed by side and trace number, and a non-zero asOf excludes later postings.
+// Unless a trace number is given, balances start from the latest usable closed-period
+// snapshot and only the legs dated after it are summed.
+func (r *Repository) GetBalances(ctx context.Context, achSide, accountCode, traceNumber string, asOf time.Time) (*BalanceResponse, error) {
+	snapshotDate := ""
+	if traceNumber == "" {
+		var err error
+		if snapshotDate, err = r.snapshotFor(ctx, asOf); err != nil {
+			return nil, err
+		}
+	}
+
+	filters, args, argNum := legFilters(achSide, traceNumber, asOf, 1)
+
+	amounts := `
+			SELECT l.account_code,
+				CASE WHEN l.direction = 'DEBIT' THEN l.amount_cents ELSE 0 END AS debits,
+				CASE WHEN l.direction = 'CREDIT' THEN l.amount_cents ELSE 0 END AS credits
+			FROM ledger_entries l
+			WHERE 1=1` + filters
+
+	if snapshotDate != "" {
+		amounts += fmt.Sprintf(` AND l.effective_date > $%[1]d
+			UNION ALL
+			SELECT s.account_code, s.total_debits, s.total_credits
+			FROM ledger_balance_snapshots s
+			WHERE s.period_date = $%[1]d`, argNum)
+		args = append(args, snapshotDate)
+		argNum++
+
+		if achSide != "" {
+			amounts += fmt.Sprintf(" AND s.ach_side = $%d", argNum)
+			args = append(args, achSide)
+			argNum++
+		}
+	}
+
+	query := `
+		SELECT
+			a.code, a.name, a.normal_balance,
+			COALESCE(SUM(b.debits), 0) as total_debits,
+			COALESCE(SUM(b.credits), 0) as total_credits
+		FROM ledger_accounts a
+		LEFT JOIN (` + amounts + `
+		) b ON b.account_code = a.code
+		WHERE 1=1
+	`
+
+	if accountCode != "" {
+		query += fmt.Sprintf(" AND a.code = $%d", argNum)
+		args = append(args, accountCode)
+		argNum++
+	}
+
+	query += " GROUP BY a.code, a.name, a.normal_balance ORDER BY a.code"
+
+	balances, err := r.queryBalances(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	balances.SnapshotDate = snapshotDate
+
+	return balances, nil
+}
+
+// queryBalances runs a query yielding code, name, normal balance, debits and credits per
+// account and totals the rows
+func (r *Repository) queryBalances(ctx context.Context, query string, args ...any) (*BalanceResponse, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := &BalanceResponse{Accounts: []AccountBalance{}}
+	for rows.Next() {
+		var account AccountBalance
+		err := rows.Scan(&account.AccountCode, &account.AccountName, &account.NormalBalance,
+			&account.TotalDebits, &account.TotalCredits)
+		if err != nil {
+			return nil, err
+		}
+		account.Balance = normalBalance(account.NormalBalance, account.TotalDebits, account.TotalCredits)
+
+		balances.TotalDebits += account.TotalDebits
+		balances.TotalCredits += account.TotalCredits
+		balances.Accounts = append(balances.Accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	balances.NetBalance = balances.TotalCredits - balances.TotalDebits
+
+	return balances, nil
+}
+
+// GetBalanceSeries returns, for each account and each period from..to, the period's
+// activity and the running balance at its end. Legs are bucketed by effective date.
+// Activity before the first period is folded into an opening balance so the running
+// totals are true balances, and periods without activity still carry the balance forward.
+// Unless a trace number is given, the opening balance starts from the latest closed-period
+// snapshot before the first period.
+func (r *Repository) GetBalanceSeries(ctx context.Context, achSide, accountCode, traceNumber, interval string, from, to time.Time) ([]BalancePoint, error) {
+	args := []interface{}{interval, calendar.FormatDate(from), calendar.FormatDate(to), PeriodStatusClosed, traceNumber == ""}
+	filters, filterArgs, argNum := legFilters(achSide, traceNumber, time.Time{}, 6)
+	args = append(args, filterArgs...)
+
+	snapshotFilter := ""
+	if achSide != "" {
+		snapshotFilter = fmt.Sprintf(" AND s.ach_side = $%d", argNum)
+		args = append(args, achSide)
+		argNum++
+	}
+
+	accountFilter := ""
+	if accountCode != "" {
+		accountFilter = fmt.Sprintf(" AND code = $%d", argNum)
+		args = append(args, accountCode)
+		argNum++
+	}
+
+	query := `
+		WITH params AS (
+			SELECT
+				date_trunc($1, $2::timestamp) AS first_period,
+				date_trunc($1, $3::timestamp) AS last_period,
+				('1 ' || $1)::interval AS step
+		),
+		periods AS (
+			SELECT generate_series(p.first_period, p.last_period, p.step) AS period FROM params p
+		),
+		accounts AS (
+			SELECT code, normal_balance FROM ledger_accounts WHERE 1=1` + accountFilter + `
+		),
+		snapshot AS (
+			SELECT MAX(period_date) AS period_date
+			FROM ledger_periods, params p
+			WHERE status = $4 AND period_date < p.first_period::date AND $5
+		),
+		legs AS (
+			SELECT l.account_code, date_trunc($1, l.effective_date::timestamp) AS period, l.direction, l.amount_cents
+			FROM ledger_entries l, params p, snapshot sn
+			WHERE l.effective_date < (p.last_period + p.step)::date
+				AND (sn.period_date IS NULL OR l.effective_date > sn.period_date)` + filters + `
+		),
+		opening AS (
+			SELECT o.account_code, SUM(o.debits) AS debits, SUM(o.credits) AS credits
+			FROM (
+				SELECT legs.account_code,
+					CASE WHEN direction = 'DEBIT' THEN amount_cents ELSE 0 END AS debits,
+					CASE WHEN direction = 'CREDIT' THEN amount_cents ELSE 0 END AS credits
+				FROM legs, params p
+				WHERE legs.period < p.first_period
+				UNION ALL
+				SELECT s.account_code, s.total_debits, s.total_credits
+				FROM ledger_balance_snapshots s, snapshot sn
+				WHERE s.period_date = sn.period_date` + snapshotFilter + `
+			) o
+			GROUP BY o.account_code
+		),
+		activity AS (
+			SELECT legs.account_code, legs.period,
+				SUM(CASE WHEN direction = 'DEBIT' THEN amount_cents ELSE 0 END) AS debits,
+				SUM(CASE WHEN direction = 'CREDIT' THEN amount_cents ELSE 0 END) AS credits
+			FROM legs, params p
+			WHERE legs.period >= p.first_period
+			GROUP BY legs.account_code, legs.period
+		)
+		SELECT
+			pr.period, a.code, a.normal_balance,
+			COALESCE(act.debits, 0),
+			COALESCE(act.credits, 0),
+			COALESCE(o.debits, 0) + SUM(COALESCE(act.debits, 0)) OVER w,
+			COALESCE(o.credits, 0) + SUM(COALESCE(act.credits, 0)) OVER w
+		FROM accounts a
+		CROSS JOIN periods pr
+		LEFT JOIN activity act ON act.account_code = a.code AND act.period = pr.period
+		LEFT JOIN opening o ON o.account_code = a.code
+		WINDOW w AS (PARTITION BY a.code ORDER BY pr.period)
+		ORDER BY pr.period, a.code
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []BalancePoint
+	for rows.Next() {
+		var point BalancePoint
+		var period time.Time
+		var normal string
+		var cumulativeDebits, cumulativeCredits int64
+		err := rows.Scan(&period, &point.AccountCode, &normal,
+			&point.PeriodDebits, &point.PeriodCredits,
+			&cumulativeDebits, &cumulativeCredits)
+		if err != nil {
+			return nil, err
+		}
+		point.PeriodStart = period.Format(calendar.DateLayout)
+		point.Balance = normalBalance(normal, cumulativeDebits, cumulativeCredits)
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
+
+// closedThrough returns the latest closed period inside tx, or "" if none is closed.
+// Every day up to and including it is locked against postings.
+func closedThrough(ctx context.Context, tx *sql.Tx) (string, error) {
+	var date sql.NullTime
+	err := tx.QueryRowContext(ctx, `SELECT MAX(period_date) FROM ledger_periods WHERE status = $1`, PeriodStatusClosed).Scan(&date)
+	if err != nil || !date.Valid {
+		return "", err
+	}
+	return date.Time.Format(calendar.DateLayout), nil
+}
+
+// firstOpenDate is today in Eastern time, or the day after the last close if today is
+// already closed
+func firstOpenDate(lastClose string) string {
+	today := calendar.FormatDate(calendar.Today())
+	if lastClose == "" || today > lastClose {
+		return today
+	}
+	next, _ := calendar.ParseDate(lastClose)
+	return calendar.FormatDate(next.AddDate(0, 0, 1))
+}
+
+// ClosePeriod closes the accounting day date: it snapshots the cumulative balance of
+// every account and side through the end of the day, then locks the day and all earlier
+// ones against new postings. The snapshot builds on the previous close's. Returns
+// ErrPeriodClosed if date is on or before the latest close.
+func (r *Repository) ClosePeriod(ctx context.Context, date string) (*Period, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, periodLockKey); err != nil {
+		return nil, err
+	}
+	lastClose, err := closedThrough(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if lastClose != "" && date <= lastClose {
+		return nil, fmt.Errorf("%w: %s is on or before the last close (%s)", ErrPeriodClosed, date, lastClose)
+	}
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO ledger_periods (period_date, status, closed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (period_date) DO UPDATE SET status = EXCLUDED.status, closed_at = EXCLUDED.closed_at
+	`, date, PeriodStatusClosed, now)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO ledger_balance_snapshots (period_date, account_code, ach_side, total_debits, total_credits)
+		SELECT $1, b.account_code, b.ach_side, SUM(b.debits), SUM(b.credits)
+		FROM (
+			SELECT account_code, ach_side, total_debits AS debits, total_credits AS credits
+			FROM ledger_balance_snapshots
+			WHERE period_date = $2
+			UNION ALL
+			SELECT account_code, ach_side,
+				CASE WHEN direction = 'DEBIT' THEN amount_cents ELSE 0 END,
+				CASE WHEN direction = 'CREDIT' THEN amount_cents ELSE 0 END
+			FROM ledger_entries
+			WHERE effective_date <= $1 AND ($2::date IS NULL OR effective_date > $2)
+		) b
+		GROUP BY b.account_code, b.ach_side
+	`, date, nullString(lastClose))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordPeriodAudit(ctx, tx, date, PeriodStatusClosed, "", now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetPeriod(ctx, date)
+}
+
+// ReopenPeriod reopens the most recently closed day, discarding its snapshot, so that
+// postings may be dated into it again. Returns ErrPeriodNotClosed if date is not closed
+// and ErrPeriodNotLatest if a later day is.
+func (r *Repository) ReopenPeriod(ctx context.Context, date, reason string) (*Period, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, periodLockKey); err != nil {
+		return nil, err
+	}
+
+	var status string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM ledger_periods WHERE period_date = $1`, date).Scan(&status)
+	if err == sql.ErrNoRows || (err == nil && status != PeriodStatusClosed) {
+		return nil, ErrPeriodNotClosed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lastClose, err := closedThrough(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if lastClose != date {
+		return nil, ErrPeriodNotLatest
+	}
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE ledger_periods SET status = $2, reopened_at = $3, reopen_reason = $4 WHERE period_date = $1
+	`, date, PeriodStatusReopened, now, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM ledger_balance_snapshots WHERE period_date = $1`, date); err != nil {
+		return nil, err
+	}
+
+	if err := recordPeriodAudit(ctx, tx, date, PeriodStatusReopened, reason, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetPeriod(ctx, date)
+}
+
+func recordPeriodAudit(ctx context.Context, tx *sql.Tx, date, action, reason string, at time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO ledger_period_audit (id, period_date, action, reason, occurred_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, uuid.New().String(), date, action, nullString(reason), at)
+	return err
+}
+
+const periodColumns = `period_date, status, closed_at, reopened_at, reopen_reason`
+
+func scanPeriod(row rowScanner) (*Period, error) {
+	period := &Period{}
+	var date time.Time
+	var closedAt, reopenedAt sql.NullTime
+	var reopenReason sql.NullString
+
+	if err := row.Scan(&date, &period.Status, &closedAt, &reopenedAt, &reopenReason); err != nil {
+		return nil, err
+	}
+
+	period.Date = date.Format(calendar.DateLayout)
+	if closedAt.Valid {
+		period.ClosedAt = &closedAt.Time
+	}
+	if reopenedAt.Valid {
+		period.ReopenedAt = &reopenedAt.Time
+	}
+	period.ReopenReason = reopenReason.String
+
+	return period, nil
+}
+
+// GetPeriod retrieves a period with its audit history and, while it is closed, the
+// per-account balances snapshotted at its close. Returns (nil, nil) for a day that has
+// never been closed.
+func (r *Repository) GetPeriod(ctx context.Context, date string) (*Period, error) {
+	query := `SELECT ` + periodColumns + ` FROM ledger_periods WHERE period_date = $1`
+
+	period, err := scanPeriod(r.db.QueryRowContext(ctx, query, date))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if period.Status == PeriodStatusClosed {
+		balances, err := r.queryBalances(ctx, `
+			SELECT a.code, a.name, a.normal_balance,
+				COALESCE(SUM(s.total_debits), 0), COALESCE(SUM(s.total_credits), 0)
+			FROM ledger_accounts a
+			LEFT JOIN ledger_balance_snapshots s ON s.account_code = a.code AND s.period_date = $1
+			GROUP BY a.code, a.name, a.normal_balance
+			ORDER BY a.code
+		`, date)
+		if err != nil {
+			return nil, err
+		}
+		period.Balances = balances.Accounts
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT action, reason, occurred_at FROM ledger_period_audit
+		WHERE period_date = $1
+		ORDER BY occurred_at
+	`, date)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry PeriodAuditEntry
+		var reason sql.NullString
+		if err := rows.Scan(&entry.Action, &reason, &entry.OccurredAt); err != nil {
+			return nil, err
+		}
+		entry.Reason = reason.String
+		period.History = append(period.History, entry)
+	}
+
+	return period, rows.Err()
+}
+
+// ListPeriods retrieves closed and reopened periods, newest first
+func (r *Repository) ListPeriods(ctx context.Context) ([]*Period, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+periodColumns+` FROM ledger_periods ORDER BY period_date DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var periods []*Period
+	for rows.Next() {
+		period, err := scanPeriod(rows)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+
+	return periods, rows.Err()
+}
+
+// BalancesBefore returns each account's balance from every leg dated before date, starting
+// from the latest closed-period snapshot before it. Unlike GetBalances it ignores when legs
+// were recorded, so it agrees with the legs listed by ListAccountLegs from date on.
+func (r *Repository) BalancesBefore(ctx context.Context, accountCode, date string) (*BalanceResponse, error) {
+	args := []interface{}{date, PeriodStatusClosed}
+	accountFilter := ""
+	if accountCode != "" {
+		accountFilter = " AND a.code = $3"
+		args = append(args, accountCode)
+	}
+
+	query := `
+		WITH snapshot AS (
+			SELECT MAX(period_date) AS period_date FROM ledger_periods
+			WHERE status = $2 AND period_date < $1
+		)
+		SELECT
+			a.code, a.name, a.normal_balance,
+			COALESCE(SUM(b.debits), 0), COALESCE(SUM(b.credits), 0)
+		FROM ledger_accounts a
+		LEFT JOIN (
+			SELECT l.account_code,
+				CASE WHEN l.direction = 'DEBIT' THEN l.amount_cents ELSE 0 END AS debits,
+				CASE WHEN l.direction = 'CREDIT' THEN l.amount_cents ELSE 0 END AS credits
+			FROM ledger_entries l, snapshot sn
+			WHERE l.effective_date < $1
+				AND (sn.period_date IS NULL OR l.effective_date > sn.period_date)
+			UNION ALL
+			SELECT s.account_code, s.total_debits, s.total_credits
+			FROM ledger_balance_snapshots s, snapshot sn
+			WHERE s.period_date = sn.period_date
+		) b ON b.account_code = a.code
+		WHERE 1=1` + accountFilter + `
+		GROUP BY a.code, a.name, a.normal_balance
+		ORDER BY a.code
+	`
+
+	return r.queryBalances(ctx, query, args...)
+}
+
+// ListAccountLegs retrieves the legs dated from..to (inclusive), optionally for one
+// account, in posting order
+func (r *Repository) ListAccountLegs(ctx context.Context, accountCode, from, to string) ([]*LedgerEntry, error) {
+	query := legSelect + `
+		WHERE l.effective_date BETWEEN $1 AND $2
+	`
+	args := []interface{}{from, to}
+
+	if accountCode != "" {
+		query += " AND l.account_code = $3"
+		args = append(args, accountCode)
+	}
+
+	query += " ORDER BY l.effective_date, l.created_at, l.journal_entry_id, l.direction DESC, l.account_code"
+
+	return r.listLegs(ctx, query, args...)
+}
+
+func insertHold(ctx context.Context, tx *sql.Tx, hold *Hold) error {
+	hold.ID = uuid.New().String()
+	hold.Status = HoldStatusActive
+	hold.CreatedAt = time.Now()
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO ledger_holds (id, account_code, ach_side, entry_id, trace_number, amount_cents, status, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, hold.ID, hold.AccountCode, hold.AchSide, hold.EntryID, nullString(hold.TraceNumber),
+		hold.AmountCents, hold.Status, hold.ExpiresAt, hold.CreatedAt)
+	return err
+}
+
+// holdSelect reads holds with expiry applied to their status
+const holdSelect = `
+	SELECT id, account_code, ach_side, entry_id, trace_number, amount_cents,
+		CASE WHEN status = 'ACTIVE' AND expires_at <= NOW() THEN 'EXPIRED' ELSE status END,
+		journal_entry_id, expires_at, created_at, resolved_at
+	FROM ledger_holds`
+
+func scanHold(row rowScanner) (*Hold, error) {
+	hold := &Hold{}
+	var traceNumber, journalEntryID sql.NullString
+	var resolvedAt sql.NullTime
+
+	err := row.Scan(&hold.ID, &hold.AccountCode, &hold.AchSide, &hold.EntryID, &traceNumber,
+		&hold.AmountCents, &hold.Status, &journalEntryID, &hold.ExpiresAt, &hold.CreatedAt, &resolvedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	hold.TraceNumber = traceNumber.String
+	hold.JournalEntryID = journalEntryID.String
+	if resolvedAt.Valid {
+		hold.ResolvedAt = &resolvedAt.Time
+	}
+
+	return hold, nil
+}
+
+func (r *Repository) listHolds(ctx context.Context, query string, args ...any) ([]*Hold, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []*Hold
+	for rows.Next() {
+		hold, err := scanHold(rows)
+		if err != nil {
+			return nil, err
+		}
+		holds = append(holds, hold)
+	}
+
+	return holds, rows.Err()
+}
+
+// ListHolds retrieves holds with optional filters, newest first. Filtering by EXPIRED
+// matches active holds past their expiry.
+func (r *Repository) ListHolds(ctx context.Context, accountCode, status, entryID, traceNumber string) ([]*Hold, error) {
+	query := holdSelect + `
+		WHERE 1=1
+	`
+	args := []interface{}{}
+	argNum := 1
+
+	if accountCode != "" {
+		query += fmt.Sprintf(" AND account_code = $%d", argNum)
+		args = append(args, accountCode)
+		argNum++
+	}
+
+	switch status {
+	case "":
+	case HoldStatusActive:
+		query += " AND status = 'ACTIVE' AND expires_at > NOW()"
+	case HoldStatusExpired:
+		query += " AND status = 'ACTIVE' AND expires_at <= NOW()"
+	default:
+		query += fmt.Sprintf(" AND status = $%d", argNum)
+		args = append(args, status)
+		argNum++
+	}
+
+	if entryID != "" {
+		query += fmt.Sprintf(" AND entry_id = $%d", argNum)
+		args = append(args, entryID)
+		argNum++
+	}
+
+	if traceNumber != "" {
+		query += fmt.Sprintf(" AND trace_number = $%d", argNum)
+		args = append(args, traceNumber)
+		argNum++
+	}
+
+	query += " ORDER BY created_at DESC"
+
+	return r.listHolds(ctx, query, args...)
+}
+
+// ListActiveHolds retrieves the holds that were active at asOf (now when zero): placed by
+// then, not yet resolved and not yet expired. Filters match GetBalances.
+func (r *Repository) ListActiveHolds(ctx context.Context, achSide, accountCode, traceNumber string, asOf time.Time) ([]*Hold, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+
+	query := holdSelect + `
+		WHERE created_at <= $1 AND expires_at > $1
+			AND (status = 'ACTIVE' OR resolved_at > $1)
+	`
+	args := []interface{}{asOf}
+	argNum := 2
+
+	if achSide != "" {
+		query += fmt.Sprintf(" AND ach_side = $%d", argNum)
+		args = append(args, achSide)
+		argNum++
+	}
+
+	if accountCode != "" {
+		query += fmt.Sprintf(" AND account_code = $%d", argNum)
+		args = append(args, accountCode)
+		argNum++
+	}
+
+	if traceNumber != "" {
+		query += fmt.Sprintf(" AND trace_number = $%d", argNum)
+		args = append(args, traceNumber)
+		argNum++
+	}
+
+	query += " ORDER BY created_at"
+
+	return r.listHolds(ctx, query, args...)
+}
+
+// ReleaseHold releases an active hold by hand. It returns (nil, nil) when the hold does
+// not exist and ErrHoldNotActive when it is no longer active.
+func (r *Repository) ReleaseHold(ctx context.Context, id string) (*Hold, error) {
+	hold, err := scanHold(r.db.QueryRowContext(ctx, `
+		UPDATE ledger_holds SET status = $2, resolved_at = NOW()
+		WHERE id = $1 AND status = 'ACTIVE' AND expires_at > NOW()
+		RETURNING id, account_code, ach_side, entry_id, trace_number, amount_cents,
+			status, journal_entry_id, expires_at, created_at, resolved_at
+	`, id, HoldStatusReleased))
+	if err != sql.ErrNoRows {
+		return hold, err
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM ledger_holds WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrHoldNotActive
+	}
+	return nil, nil
+}
+
+// queryer is satisfied by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// settlementPositions sums the settlement account's legs for a settlement date by side,
+// leaving out the transfers themselves, and subtracts what has been transferred. Both
+// sides are returned unless achSide narrows them.
+func settlementPositions(ctx context.Context, q queryer, date, achSide string) ([]SettlementPosition, error) {
+	rows, err := q.QueryContext(ctx, `
+		WITH sides(ach_side) AS (VALUES ('ODFI'), ('RDFI')),
+		legs AS (
+			SELECT j.ach_side,
+				SUM(CASE WHEN l.direction = 'DEBIT' THEN l.amount_cents ELSE 0 END) AS debits,
+				SUM(CASE WHEN l.direction = 'CREDIT' THEN l.amount_cents ELSE 0 END) AS credits,
+				COUNT(DISTINCT j.id) AS journals
+			FROM ledger_entries l
+			JOIN journal_entries j ON j.id = l.journal_entry_id
+			WHERE l.account_code = $1
+				AND COALESCE(j.settlement_date, j.effective_date) = $2
+				AND NOT EXISTS (SELECT 1 FROM ledger_settlement_transfers t WHERE t.journal_entry_id = j.id)
+			GROUP BY j.ach_side
+		),
+		transfers AS (
+			SELECT ach_side, SUM(amount_cents) AS transferred, MAX(created_at) AS last_transfer_at
+			FROM ledger_settlement_transfers
+			WHERE settlement_date = $2
+			GROUP BY ach_side
+		)
+		SELECT s.ach_side,
+			COALESCE(legs.debits, 0), COALESCE(legs.credits, 0), COALESCE(legs.journals, 0),
+			COALESCE(t.transferred, 0), t.last_transfer_at
+		FROM sides s
+		LEFT JOIN legs ON legs.ach_side = s.ach_side
+		LEFT JOIN transfers t ON t.ach_side = s.ach_side
+		WHERE $3 = '' OR s.ach_side = $3
+		ORDER BY s.ach_side
+	`, AccountSettlement, date, achSide)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	positions := []SettlementPosition{}
+	for rows.Next() {
+		var position SettlementPosition
+		var lastTransferAt sql.NullTime
+		err := rows.Scan(&position.AchSide, &position.GrossDebits, &position.GrossCredits,
+			&position.JournalEntryCount, &position.TransferredCents, &lastTransferAt)
+		if err != nil {
+			return nil, err
+		}
+		position.NetPosition = position.GrossDebits - position.GrossCredits
+		position.OutstandingCents = position.NetPosition - position.TransferredCents
+		if lastTransferAt.Valid {
+			position.LastTransferAt = &lastTransferAt.Time
+		}
+		positions = append(positions, position)
+	}
+
+	return positions, rows.Err()
+}
+
+// GetSettlement computes the settlement positions for a date, optionally for one side
+func (r *Repository) GetSettlement(ctx context.Context, date, achSide string) (*Settlement, error) {
+	positions, err := settlementPositions(ctx, r.db, date, achSide)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Settlement{SettlementDate: date, Positions: positions}, nil
+}
+
+// TransferSettlement posts, for each side with an outstanding position on date, a journal
+// entry that moves it between the settlement account and the Fed master account, leaving
+// the side's settlement balance for the date at zero. Sides already fully transferred are
+// skipped, so repeating a transfer only picks up postings made since.
+func (r *Repository) TransferSettlement(ctx context.Context, date string) (*Settlement, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, settlementLockKey); err != nil {
+		return nil, err
+	}
+
+	positions, err := settlementPositions(ctx, tx, date, "")
+	if err != nil {
+		return nil, err
+	}
+
+	settlement := &Settlement{SettlementDate: date}
+	for _, position := range positions {
+		amount := position.OutstandingCents
+		if amount == 0 {
+			continue
+		}
+
+		// A positive position is collected from the operator into the master account
+		debit, credit := AccountFedReserve, AccountSettlement
+		if amount < 0 {
+			debit, credit = credit, debit
+			amount = -amount
+		}
+
+		journal := &JournalEntry{
+			AchSide:        position.AchSide,
+			Description:    fmt.Sprintf("Settlement transfer %s %s", date, position.AchSide),
+			SettlementDate: date,
+			Legs: []*LedgerEntry{
+				{AccountCode: debit, Direction: DirectionDebit, AmountCents: amount},
+				{AccountCode: credit, Direction: DirectionCredit, AmountCents: amount},
+			},
+		}
+		if err := insertJournalEntry(ctx, tx, journal); err != nil {
+			return nil, err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO ledger_settlement_transfers (id, settlement_date, ach_side, journal_entry_id, amount_cents, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, uuid.New().String(), date, position.AchSide, journal.ID, position.OutstandingCents, journal.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		settlement.Transfers = append(settlement.Transfers, journal)
+	}
+
+	if settlement.Positions, err = settlementPositions(ctx, tx, date, ""); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// normalBalance signs an account balance so that it is positive in the account's normal direction
+func normalBalance(normal string, debits, credits int64) int64 {
+	if normal == DirectionDebit {
+		return debits - credits
+	}
+	return credits - debits
+}
@@ -0,0 +1,169 @@
+package ledger
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+//go:embed posting_rules.json
+var defaultPostingRules []byte
+
+// Posting rule actions
+const (
+	RuleActionPost    = "post"
+	RuleActionReverse = "reverse"
+	RuleActionHold    = "hold"
+)
+
+// defaultHoldDays is how long a hold lasts when its rule does not set hold_days
+const defaultHoldDays = 5
+
+// PostingRule maps an entry status change to a ledger action. A post rule books each leg
+// for the entry's amount; a reverse rule offsets the journal entry posted for an earlier
+// status of the same entry; a hold rule places a hold for the entry's amount against
+// an account until the entry's next status change or the hold expires.
+type PostingRule struct {
+	Side           string    `json:"side"`
+	Status         string    `json:"status"`
+	EntryDirection string    `json:"entry_direction,omitempty"` // DEBIT or CREDIT; empty matches both
+	Action         string    `json:"action"`
+	Description    string    `json:"description,omitempty"`
+	Legs           []RuleLeg `json:"legs,omitempty"`
+	ReversesStatus string    `json:"reverses_status,omitempty"`
+	HoldAccount    string    `json:"hold_account,omitempty"`
+	HoldDays       int       `json:"hold_days,omitempty"`
+}
+
+// RuleLeg is one leg of a post rule
+type RuleLeg struct {
+	AccountCode string `json:"account_code"`
+	Direction   string `json:"direction"`
+}
+
+// PostingRules is an ordered rule set; the first matching rule applies
+type PostingRules struct {
+	Rules []PostingRule `json:"rules"`
+}
+
+// LoadPostingRules reads and checks a rule set in the posting_rules.json format
+func LoadPostingRules(r io.Reader) (*PostingRules, error) {
+	var rules PostingRules
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("invalid posting rules: %w", err)
+	}
+
+	for i, rule := range rules.Rules {
+		if err := rule.check(); err != nil {
+			return nil, fmt.Errorf("posting rule %d: %w", i, err)
+		}
+	}
+
+	return &rules, nil
+}
+
+// DefaultPostingRules returns the embedded rule set
+func DefaultPostingRules() *PostingRules {
+	rules, err := LoadPostingRules(bytes.NewReader(defaultPostingRules))
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// PostingRulesFromEnv loads rules from POSTING_RULES_FILE, falling back to the embedded set
+func PostingRulesFromEnv() (*PostingRules, error) {
+	path := os.Getenv("POSTING_RULES_FILE")
+	if path == "" {
+		return DefaultPostingRules(), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open posting rules: %w", err)
+	}
+	defer f.Close()
+
+	return LoadPostingRules(f)
+}
+
+// Match returns the first rule for a status change, or nil if none applies
+func (p *PostingRules) Match(side, status, entryDirection string) *PostingRule {
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.Side == side && rule.Status == status &&
+			(rule.EntryDirection == "" || rule.EntryDirection == entryDirection) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// CheckAccounts returns an error naming the first account a rule posts to or holds against
+// that is not in known. A rule with an unknown account would fail every event it matches.
+func (p *PostingRules) CheckAccounts(known map[string]bool) error {
+	for i, rule := range p.Rules {
+		for _, leg := range rule.Legs {
+			if !known[leg.AccountCode] {
+				return fmt.Errorf("posting rule %d: unknown account %s", i, leg.AccountCode)
+			}
+		}
+		if rule.HoldAccount != "" && !known[rule.HoldAccount] {
+			return fmt.Errorf("posting rule %d: unknown hold account %s", i, rule.HoldAccount)
+		}
+	}
+	return nil
+}
+
+func (rule *PostingRule) check() error {
+	if rule.Side != SideODFI && rule.Side != SideRDFI {
+		return errors.New("side must be ODFI or RDFI")
+	}
+	if rule.Status == "" {
+		return errors.New("status is required")
+	}
+	if rule.EntryDirection != "" && rule.EntryDirection != DirectionDebit && rule.EntryDirection != DirectionCredit {
+		return errors.New("entry_direction must be DEBIT or CREDIT")
+	}
+
+	switch rule.Action {
+	case RuleActionPost:
+		// Every leg carries the entry amount, so balance means as many debits as credits
+		var debits, credits int
+		for _, leg := range rule.Legs {
+			if leg.AccountCode == "" {
+				return errors.New("legs need an account_code")
+			}
+			switch leg.Direction {
+			case DirectionDebit:
+				debits++
+			case DirectionCredit:
+				credits++
+			default:
+				return errors.New("leg direction must be DEBIT or CREDIT")
+			}
+		}
+		if debits == 0 || debits != credits {
+			return errors.New("legs must pair each debit with a credit")
+		}
+	case RuleActionReverse:
+		if rule.ReversesStatus == "" {
+			return errors.New("reverses_status is required for a reverse rule")
+		}
+	case RuleActionHold:
+		if rule.HoldAccount == "" {
+			return errors.New("hold_account is required for a hold rule")
+		}
+		if rule.HoldDays < 0 {
+			return errors.New("hold_days must not be negative")
+		}
+	default:
+		return errors.New("action must be post, reverse or hold")
+	}
+
+	return nil
+}
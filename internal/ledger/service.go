@@ -3,19 +3,50 @@ package ledger
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ach-concourse/internal/common/calendar"
+	"ach-concourse/internal/common/events"
+	"ach-concourse/internal/common/pagination"
+	"ach-concourse/internal/common/validation"
 )
 
 // Service handles business logic for ledger entries
 type Service struct {
-	repo *Repository
+	repo  *Repository
+	rules *PostingRules
+}
+
+// NewService creates a new ledger service that posts entry lifecycle events using rules
+func NewService(repo *Repository, rules *PostingRules) *Service {
+	return &Service{repo: repo, rules: rules}
 }
 
-// NewService creates a new ledger service
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+// CheckPostingRules verifies that every account the posting rules use exists, so a typo in
+// POSTING_RULES_FILE fails at startup instead of rejecting every event it matches
+func (s *Service) CheckPostingRules(ctx context.Context) error {
+	accounts, err := s.repo.ListAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		known[account.Code] = true
+	}
+	return s.rules.CheckAccounts(known)
 }
 
-// CreatePosting creates a new ledger posting
+// sideAccounts maps each ACH side to the account its postings are booked against
+var sideAccounts = map[string]string{
+	SideODFI: AccountOriginatorClearing,
+	SideRDFI: AccountReceiverDDA,
+}
+
+// CreatePosting creates a new ledger posting. It is kept for compatibility with the
+// single-sided API: the posting is booked in the requested direction against the side's
+// account and offset against settlement, and the side's leg is returned.
 func (s *Service) CreatePosting(ctx context.Context, req *CreatePostingRequest) (*LedgerEntry, error) {
 	// Validate required fields
 	if req.AchSide == "" {
@@ -35,28 +66,489 @@ func (s *Service) CreatePosting(ctx context.Context, req *CreatePostingRequest)
 		return nil, errors.New("ach_side must be ODFI or RDFI")
 	}
 
-	entry := &LedgerEntry{
-		AchSide:     req.AchSide,
-		TraceNumber: req.TraceNumber,
-		AmountCents: req.AmountCents,
-		Direction:   req.Direction,
-		Description: req.Description,
+	offset := DirectionCredit
+	if req.Direction == DirectionCredit {
+		offset = DirectionDebit
+	}
+
+	journal, err := s.CreateJournalEntry(ctx, &CreateJournalEntryRequest{
+		AchSide:       req.AchSide,
+		TraceNumber:   req.TraceNumber,
+		Description:   req.Description,
+		EffectiveDate: req.EffectiveDate,
+		Legs: []JournalLegInput{
+			{AccountCode: sideAccounts[req.AchSide], Direction: req.Direction, AmountCents: req.AmountCents},
+			{AccountCode: AccountSettlement, Direction: offset, AmountCents: req.AmountCents},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return journal.Legs[0], nil
+}
+
+// CreateJournalEntry validates and posts a balanced journal entry
+func (s *Service) CreateJournalEntry(ctx context.Context, req *CreateJournalEntryRequest) (*JournalEntry, error) {
+	journal, err := s.buildJournalEntry(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateJournalEntry(ctx, journal); err != nil {
+		return nil, err
+	}
+
+	return journal, nil
+}
+
+// buildJournalEntry validates a journal entry request against the chart of accounts
+func (s *Service) buildJournalEntry(ctx context.Context, req *CreateJournalEntryRequest) (*JournalEntry, error) {
+	accounts, err := s.repo.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		known[account.Code] = true
+	}
+
+	var errs validation.Errors
+	if req.AchSide != SideODFI && req.AchSide != SideRDFI {
+		errs.Add("ach_side", "must be ODFI or RDFI")
+	}
+	if len(req.Legs) < 2 {
+		errs.Add("legs", "a journal entry needs at least two legs")
+	}
+	if req.EffectiveDate != "" {
+		if err := validatePeriodDate(req.EffectiveDate); err != nil {
+			errs.Add("effective_date", err.Error())
+		}
+	}
+	if req.SettlementDate != "" {
+		if _, err := calendar.ParseDate(req.SettlementDate); err != nil {
+			errs.Add("settlement_date", "must be YYYY-MM-DD")
+		}
+	}
+
+	var debits, credits int64
+	for i, leg := range req.Legs {
+		field := fmt.Sprintf("legs[%d]", i)
+		if !known[leg.AccountCode] {
+			errs.Add(field+".account_code", "unknown account")
+		}
+		if leg.AmountCents <= 0 {
+			errs.Add(field+".amount_cents", "must be greater than zero")
+		}
+		switch leg.Direction {
+		case DirectionDebit:
+			debits += leg.AmountCents
+		case DirectionCredit:
+			credits += leg.AmountCents
+		default:
+			errs.Add(field+".direction", "must be DEBIT or CREDIT")
+		}
+	}
+	if len(errs) == 0 && debits != credits {
+		errs.Add("legs", fmt.Sprintf("debits (%d) must equal credits (%d)", debits, credits))
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	journal := &JournalEntry{
+		AchSide:        req.AchSide,
+		TraceNumber:    req.TraceNumber,
+		Description:    req.Description,
+		EffectiveDate:  req.EffectiveDate,
+		SettlementDate: req.SettlementDate,
+	}
+	for _, leg := range req.Legs {
+		journal.Legs = append(journal.Legs, &LedgerEntry{
+			AccountCode: leg.AccountCode,
+			Direction:   leg.Direction,
+			AmountCents: leg.AmountCents,
+		})
+	}
+
+	return journal, nil
+}
+
+// ProcessEntryEvent applies the posting rule matching an ODFI or RDFI entry status change.
+// Each change is applied at most once; applied is false when it had been processed before,
+// in which case the original result is returned. Changes no rule matches are recorded as
+// SKIPPED.
+func (s *Service) ProcessEntryEvent(ctx context.Context, event *events.EntryEvent) (processed *ProcessedEvent, applied bool, err error) {
+	var errs validation.Errors
+	if event.ID == "" {
+		errs.Add("id", "is required")
+	}
+	if event.Side != SideODFI && event.Side != SideRDFI {
+		errs.Add("side", "must be ODFI or RDFI")
+	}
+	if event.EntryID == "" {
+		errs.Add("entry_id", "is required")
+	}
+	if event.Status == "" {
+		errs.Add("status", "is required")
+	}
+	if err := errs.Err(); err != nil {
+		return nil, false, err
+	}
+
+	var journal *JournalEntry
+	var hold *Hold
+	var reversesStatus, reason string
+
+	rule := s.rules.Match(event.Side, event.Status, event.Direction)
+	switch {
+	case rule == nil:
+	case rule.Action == RuleActionPost:
+		description := rule.Description
+		if description == "" {
+			description = fmt.Sprintf("%s entry %s", event.Side, strings.ToLower(event.Status))
+		}
+		req := &CreateJournalEntryRequest{
+			AchSide:        event.Side,
+			TraceNumber:    event.TraceNumber,
+			Description:    description,
+			SettlementDate: event.SettlementDate,
+		}
+		for _, leg := range rule.Legs {
+			req.Legs = append(req.Legs, JournalLegInput{
+				AccountCode: leg.AccountCode,
+				Direction:   leg.Direction,
+				AmountCents: event.AmountCents,
+			})
+		}
+		journal, err = s.buildJournalEntry(ctx, req)
+		if err != nil {
+			return nil, false, err
+		}
+	case rule.Action == RuleActionReverse:
+		reversesStatus = rule.ReversesStatus
+		reason = "Entry " + strings.ToLower(event.Status)
+		if event.Reason != "" {
+			reason += ": " + event.Reason
+		}
+	case rule.Action == RuleActionHold:
+		days := rule.HoldDays
+		if days == 0 {
+			days = defaultHoldDays
+		}
+		placedAt := event.OccurredAt
+		if placedAt.IsZero() {
+			placedAt = time.Now()
+		}
+		hold = &Hold{
+			AccountCode: rule.HoldAccount,
+			AchSide:     event.Side,
+			EntryID:     event.EntryID,
+			TraceNumber: event.TraceNumber,
+			AmountCents: event.AmountCents,
+			ExpiresAt:   placedAt.AddDate(0, 0, days),
+		}
+	}
+
+	return s.repo.ApplyEntryEvent(ctx, event, journal, reversesStatus, reason, hold)
+}
+
+// ListProcessedEvents retrieves processed entry status changes with optional filters
+func (s *Service) ListProcessedEvents(ctx context.Context, entryID, traceNumber string) ([]*ProcessedEvent, error) {
+	return s.repo.ListProcessedEvents(ctx, entryID, traceNumber)
+}
+
+// ReversePosting offsets a posting (by leg or journal entry ID) with a linked reversing
+// journal entry. It returns (nil, nil) when the posting does not exist.
+func (s *Service) ReversePosting(ctx context.Context, id, reason string) (*JournalEntry, error) {
+	var errs validation.Errors
+	if strings.TrimSpace(reason) == "" {
+		errs.Add("reason", "is required")
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ReverseJournalEntry(ctx, id, reason)
+}
+
+// GetJournalEntry retrieves a journal entry with its legs
+func (s *Service) GetJournalEntry(ctx context.Context, id string) (*JournalEntry, error) {
+	return s.repo.GetJournalEntry(ctx, id)
+}
+
+// ListJournalEntries retrieves journal entries with optional filters
+func (s *Service) ListJournalEntries(ctx context.Context, achSide, traceNumber string) ([]*JournalEntry, error) {
+	return s.repo.ListJournalEntries(ctx, achSide, traceNumber)
+}
+
+// ListAccounts returns the chart of accounts
+func (s *Service) ListAccounts(ctx context.Context) ([]*Account, error) {
+	return s.repo.ListAccounts(ctx)
+}
+
+// ListPostings retrieves a page of ledger postings with optional filters
+func (s *Service) ListPostings(ctx context.Context, filter PostingFilter, page pagination.Params) (*pagination.Page[*LedgerEntry], error) {
+	return s.repo.List(ctx, filter, page)
+}
+
+// GetBalances calculates and returns balance information, optionally filtered and as of
+// a point in time (a zero asOf means now). Holds active at that time are listed and
+// subtracted from each account's available balance.
+func (s *Service) GetBalances(ctx context.Context, achSide, accountCode, traceNumber string, asOf time.Time) (*BalanceResponse, error) {
+	balances, err := s.repo.GetBalances(ctx, achSide, accountCode, traceNumber, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	holds, err := s.repo.ListActiveHolds(ctx, achSide, accountCode, traceNumber, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if holds == nil {
+		holds = []*Hold{}
+	}
+
+	held := make(map[string]int64)
+	for _, hold := range holds {
+		held[hold.AccountCode] += hold.AmountCents
+	}
+	for i := range balances.Accounts {
+		account := &balances.Accounts[i]
+		account.LedgerBalance = account.Balance
+		account.HeldCents = held[account.AccountCode]
+		account.AvailableBalance = account.Balance - account.HeldCents
+	}
+	balances.Holds = holds
+
+	return balances, nil
+}
+
+// ListHolds retrieves holds with optional filters
+func (s *Service) ListHolds(ctx context.Context, accountCode, status, entryID, traceNumber string) ([]*Hold, error) {
+	return s.repo.ListHolds(ctx, accountCode, status, entryID, traceNumber)
+}
+
+// ReleaseHold releases an active hold before its entry moves on or it expires
+func (s *Service) ReleaseHold(ctx context.Context, id string) (*Hold, error) {
+	return s.repo.ReleaseHold(ctx, id)
+}
+
+// ClosePeriod closes the accounting day date (YYYY-MM-DD), snapshotting balances and
+// locking it and every earlier day against postings
+func (s *Service) ClosePeriod(ctx context.Context, date string) (*Period, error) {
+	if err := validatePeriodDate(date); err != nil {
+		return nil, fmt.Errorf("date %w", err)
+	}
+
+	return s.repo.ClosePeriod(ctx, date)
+}
+
+// ReopenPeriod reopens the most recently closed day; the reason is kept in its history
+func (s *Service) ReopenPeriod(ctx context.Context, date, reason string) (*Period, error) {
+	if err := validatePeriodDate(date); err != nil {
+		return nil, fmt.Errorf("date %w", err)
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	return s.repo.ReopenPeriod(ctx, date, reason)
+}
+
+// GetPeriod retrieves a closed or reopened period with its snapshot and history
+func (s *Service) GetPeriod(ctx context.Context, date string) (*Period, error) {
+	return s.repo.GetPeriod(ctx, date)
+}
+
+// ListPeriods retrieves closed and reopened periods, newest first
+func (s *Service) ListPeriods(ctx context.Context) ([]*Period, error) {
+	return s.repo.ListPeriods(ctx)
+}
+
+// validatePeriodDate checks that date is a YYYY-MM-DD accounting day that has started
+func validatePeriodDate(date string) error {
+	parsed, err := calendar.ParseDate(date)
+	if err != nil {
+		return errors.New("must be YYYY-MM-DD")
+	}
+	if parsed.After(calendar.Today()) {
+		return errors.New("must not be in the future")
+	}
+	return nil
+}
+
+// GetSettlement computes the net settlement position per side for a settlement date
+// (YYYY-MM-DD), optionally for one side
+func (s *Service) GetSettlement(ctx context.Context, date, achSide string) (*Settlement, error) {
+	return s.repo.GetSettlement(ctx, date, achSide)
+}
+
+// ValidateSettlementQuery checks a settlement date and side before they are queried
+func ValidateSettlementQuery(date, achSide string) error {
+	if _, err := calendar.ParseDate(date); err != nil {
+		return errors.New("date must be YYYY-MM-DD")
+	}
+	if achSide != "" && achSide != SideODFI && achSide != SideRDFI {
+		return errors.New("ach_side must be ODFI or RDFI")
+	}
+	return nil
+}
+
+// TransferSettlement posts the settlement transfer journal entries for a settlement date
+// that has been reached
+func (s *Service) TransferSettlement(ctx context.Context, date string) (*Settlement, error) {
+	if err := validatePeriodDate(date); err != nil {
+		return nil, fmt.Errorf("date %w", err)
 	}
 
-	if err := s.repo.Create(ctx, entry); err != nil {
+	return s.repo.TransferSettlement(ctx, date)
+}
+
+// maxStatementDays bounds the number of days a single statement can span
+const maxStatementDays = 92
+
+// BuildStatement assembles day-by-day statements for one account (or every account when
+// accountCode is empty) from from to to. The opening balance of the first day comes from
+// the balance computation and every later balance rolls forward through the day's legs.
+// Returns (nil, nil) for an unknown account.
+func (s *Service) BuildStatement(ctx context.Context, accountCode string, from, to time.Time) (*Statement, error) {
+	fromDate, toDate := calendar.FormatDate(from), calendar.FormatDate(to)
+
+	opening, err := s.repo.BalancesBefore(ctx, accountCode, fromDate)
+	if err != nil {
 		return nil, err
 	}
+	if len(opening.Accounts) == 0 {
+		return nil, nil
+	}
+
+	legs, err := s.repo.ListAccountLegs(ctx, accountCode, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[string]map[string][]*LedgerEntry)
+	for _, leg := range legs {
+		if byDay[leg.EffectiveDate] == nil {
+			byDay[leg.EffectiveDate] = make(map[string][]*LedgerEntry)
+		}
+		byDay[leg.EffectiveDate][leg.AccountCode] = append(byDay[leg.EffectiveDate][leg.AccountCode], leg)
+	}
+
+	balances := make([]int64, len(opening.Accounts))
+	for i, account := range opening.Accounts {
+		balances[i] = account.Balance
+	}
+
+	statement := &Statement{From: fromDate, To: toDate, GeneratedAt: time.Now()}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := calendar.FormatDate(day)
+		statementDay := StatementDay{Date: date}
+		for i, account := range opening.Accounts {
+			entries := byDay[date][account.AccountCode]
+			if entries == nil {
+				entries = []*LedgerEntry{}
+			}
+
+			closing := balances[i]
+			for _, leg := range entries {
+				if leg.Direction == account.NormalBalance {
+					closing += leg.AmountCents
+				} else {
+					closing -= leg.AmountCents
+				}
+			}
+
+			statementDay.Accounts = append(statementDay.Accounts, AccountStatement{
+				AccountCode:    account.AccountCode,
+				AccountName:    account.AccountName,
+				NormalBalance:  account.NormalBalance,
+				OpeningBalance: balances[i],
+				ClosingBalance: closing,
+				Entries:        entries,
+			})
+			balances[i] = closing
+		}
+		statement.Days = append(statement.Days, statementDay)
+	}
+
+	return statement, nil
+}
+
+// ValidateStatementQuery checks a statement format and date range before it is built
+func ValidateStatementQuery(format string, from, to time.Time) error {
+	if format != StatementFormatBAI2 && format != StatementFormatCamt053 {
+		return errors.New("format must be bai2 or camt053")
+	}
+	if from.After(to) {
+		return errors.New("from must not be after to")
+	}
+	if from.AddDate(0, 0, maxStatementDays).Before(to) {
+		return fmt.Errorf("a statement may span at most %d days", maxStatementDays)
+	}
+	return nil
+}
+
+// maxSeriesPeriods bounds the number of periods a single series request can span
+const maxSeriesPeriods = 366
 
-	return entry, nil
+// defaultSeriesPeriods is how far back a series reaches when from is not given
+var defaultSeriesPeriods = map[string]int{
+	IntervalDay:   30,
+	IntervalWeek:  12,
+	IntervalMonth: 12,
 }
 
-// ListPostings retrieves ledger postings with optional filters
-func (s *Service) ListPostings(ctx context.Context, achSide, traceNumber string) ([]*LedgerEntry, error) {
-	return s.repo.List(ctx, achSide, traceNumber)
+// GetBalanceSeries returns per-account running balances by period. Zero from/to default
+// to a window ending today.
+func (s *Service) GetBalanceSeries(ctx context.Context, achSide, accountCode, traceNumber, interval string, from, to time.Time) (*BalanceSeriesResponse, error) {
+	if to.IsZero() {
+		to = calendar.Today()
+	}
+	if from.IsZero() {
+		from = addPeriods(to, interval, 1-defaultSeriesPeriods[interval])
+	}
+
+	points, err := s.repo.GetBalanceSeries(ctx, achSide, accountCode, traceNumber, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if points == nil {
+		points = []BalancePoint{}
+	}
+
+	return &BalanceSeriesResponse{
+		Interval: interval,
+		From:     calendar.FormatDate(from),
+		To:       calendar.FormatDate(to),
+		Points:   points,
+	}, nil
 }
 
-// GetBalances calculates and returns balance information
-func (s *Service) GetBalances(ctx context.Context) (*BalanceResponse, error) {
-	return s.repo.GetBalances(ctx)
+// ValidateSeriesRange checks a series interval and date range before it is queried
+func ValidateSeriesRange(interval string, from, to time.Time) error {
+	if _, ok := defaultSeriesPeriods[interval]; !ok {
+		return errors.New("interval must be day, week or month")
+	}
+	if from.IsZero() || to.IsZero() {
+		return nil
+	}
+	if from.After(to) {
+		return errors.New("from must not be after to")
+	}
+	if addPeriods(from, interval, maxSeriesPeriods).Before(to) {
+		return fmt.Errorf("a series may span at most %d periods", maxSeriesPeriods)
+	}
+	return nil
 }
 
+func addPeriods(t time.Time, interval string, n int) time.Time {
+	switch interval {
+	case IntervalWeek:
+		return t.AddDate(0, 0, 7*n)
+	case IntervalMonth:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
@@ -0,0 +1,331 @@
+package ledger
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"ach-concourse/internal/common/calendar"
+)
+
+// statementBankID identifies the ledger as sender and account servicer in exported statements
+const statementBankID = "ACHCONCOURSE"
+
+// statementCurrency is the currency of every ledger amount
+const statementCurrency = "USD"
+
+// BAI2 type codes used for summaries and transaction details
+const (
+	baiOpeningLedger    = "010"
+	baiClosingLedger    = "015"
+	baiTotalCredits     = "100"
+	baiTotalDebits      = "400"
+	baiACHCredit        = "165" // preauthorized ACH credit
+	baiACHDebit         = "455" // preauthorized ACH debit
+	baiMiscCredit       = "399" // legs without a trace number, such as settlement transfers
+	baiMiscDebit        = "699"
+	baiFundsTypeUnknown = "Z"
+)
+
+// StatementContentType returns the media type a statement format is served as
+func StatementContentType(format string) string {
+	if format == StatementFormatCamt053 {
+		return "application/xml"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// StatementFilename names a statement download, e.g. statement-SETTLEMENT-2026-10-01-2026-10-16.bai2
+func StatementFilename(accountCode, from, to, format string) string {
+	if accountCode == "" {
+		accountCode = "ALL"
+	}
+	extension := "bai2"
+	if format == StatementFormatCamt053 {
+		extension = "xml"
+	}
+	return fmt.Sprintf("statement-%s-%s-%s.%s", accountCode, from, to, extension)
+}
+
+// RenderStatement writes a statement in format (bai2 or camt053)
+func RenderStatement(w io.Writer, statement *Statement, format string) error {
+	switch format {
+	case StatementFormatBAI2:
+		return renderBAI2(w, statement)
+	case StatementFormatCamt053:
+		return renderCamt053(w, statement)
+	default:
+		return fmt.Errorf("unknown statement format %q", format)
+	}
+}
+
+// isStatementCredit reports whether a leg raises its account's balance
+func isStatementCredit(account *AccountStatement, leg *LedgerEntry) bool {
+	return leg.Direction == account.NormalBalance
+}
+
+// baiTypeCode picks the BAI2 transaction type code for a leg
+func baiTypeCode(credit bool, traceNumber string) string {
+	switch {
+	case credit && traceNumber != "":
+		return baiACHCredit
+	case credit:
+		return baiMiscCredit
+	case traceNumber != "":
+		return baiACHDebit
+	default:
+		return baiMiscDebit
+	}
+}
+
+// baiDate renders a YYYY-MM-DD date as BAI2's YYMMDD
+func baiDate(date string) string {
+	return strings.ReplaceAll(date, "-", "")[2:]
+}
+
+// baiText strips characters that would break a BAI2 record out of free text
+func baiText(s string) string {
+	return strings.NewReplacer(",", " ", "/", " ", "\n", " ", "\r", " ").Replace(s)
+}
+
+// renderBAI2 writes a BAI2 file with one group per day and one account per ledger account.
+// Amounts are in cents; control totals sum the amounts of the records they cover.
+func renderBAI2(w io.Writer, statement *Statement) error {
+	var records []string
+	var fileTotal int64
+
+	generated := statement.GeneratedAt.In(calendar.Location)
+	records = append(records, fmt.Sprintf("01,%s,%s,%s,%s,%s,,,2/",
+		statementBankID, statementBankID, generated.Format("060102"), generated.Format("1504"), generated.Format("0102150405")))
+
+	for _, day := range statement.Days {
+		groupStart := len(records)
+		var groupTotal int64
+		records = append(records, fmt.Sprintf("02,%s,%s,1,%s,,%s,2/",
+			statementBankID, statementBankID, baiDate(day.Date), statementCurrency))
+
+		for i := range day.Accounts {
+			account := &day.Accounts[i]
+			accountStart := len(records)
+
+			var credits, debits, creditCount, debitCount int64
+			details := make([]string, 0, len(account.Entries))
+			for _, leg := range account.Entries {
+				credit := isStatementCredit(account, leg)
+				code := baiTypeCode(credit, leg.TraceNumber)
+				if credit {
+					credits += leg.AmountCents
+					creditCount++
+				} else {
+					debits += leg.AmountCents
+					debitCount++
+				}
+				// Text runs to the end of the record, so only an empty one is closed with "/"
+				detail := fmt.Sprintf("16,%s,%d,%s,%s,%s,", code, leg.AmountCents, baiFundsTypeUnknown, leg.TraceNumber, leg.JournalEntryID)
+				if text := baiText(leg.Description); text != "" {
+					detail += text
+				} else {
+					detail += "/"
+				}
+				details = append(details, detail)
+			}
+
+			records = append(records, fmt.Sprintf("03,%s,%s,%s,%d,,,%s,%d,,,%s,%d,%d,,%s,%d,%d,/",
+				account.AccountCode, statementCurrency,
+				baiOpeningLedger, account.OpeningBalance,
+				baiClosingLedger, account.ClosingBalance,
+				baiTotalCredits, credits, creditCount,
+				baiTotalDebits, debits, debitCount))
+			records = append(records, details...)
+
+			// The 03 record's four summary amounts plus every 16 record's amount
+			accountTotal := account.OpeningBalance + account.ClosingBalance + 2*(credits+debits)
+			records = append(records, fmt.Sprintf("49,%d,%d/", accountTotal, len(records)-accountStart+1))
+			groupTotal += accountTotal
+		}
+
+		records = append(records, fmt.Sprintf("98,%d,%d,%d/", groupTotal, len(day.Accounts), len(records)-groupStart+1))
+		fileTotal += groupTotal
+	}
+
+	records = append(records, fmt.Sprintf("99,%d,%d,%d/", fileTotal, len(statement.Days), len(records)+1))
+
+	_, err := io.WriteString(w, strings.Join(records, "\n")+"\n")
+	return err
+}
+
+// camt.053 (BankToCustomerStatement, version 02) documents
+
+type camtDocument struct {
+	XMLName xml.Name      `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02 Document"`
+	Report  camtStatement `xml:"BkToCstmrStmt"`
+}
+
+type camtStatement struct {
+	GroupHeader camtGroupHeader `xml:"GrpHdr"`
+	Statements  []camtStmt      `xml:"Stmt"`
+}
+
+type camtGroupHeader struct {
+	MessageID string `xml:"MsgId"`
+	CreatedAt string `xml:"CreDtTm"`
+}
+
+type camtStmt struct {
+	ID        string        `xml:"Id"`
+	CreatedAt string        `xml:"CreDtTm"`
+	Account   camtAccount   `xml:"Acct"`
+	Balances  []camtBalance `xml:"Bal"`
+	Summary   camtSummary   `xml:"TxsSummry"`
+	Entries   []camtEntry   `xml:"Ntry"`
+}
+
+type camtAccount struct {
+	ID       string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy"`
+	Name     string `xml:"Nm"`
+	Servicer string `xml:"Svcr>FinInstnId>Othr>Id"`
+}
+
+type camtBalance struct {
+	Type      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    camtAmount `xml:"Amt"`
+	Indicator string     `xml:"CdtDbtInd"`
+	Date      string     `xml:"Dt>Dt"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtSummary struct {
+	Total   camtTotal `xml:"TtlNtries"`
+	Credits camtTotal `xml:"TtlCdtNtries"`
+	Debits  camtTotal `xml:"TtlDbtNtries"`
+}
+
+type camtTotal struct {
+	Count int64  `xml:"NbOfNtries"`
+	Sum   string `xml:"Sum"`
+}
+
+type camtEntry struct {
+	Reference   string        `xml:"NtryRef"`
+	Amount      camtAmount    `xml:"Amt"`
+	Indicator   string        `xml:"CdtDbtInd"`
+	Status      string        `xml:"Sts"`
+	BookingDate string        `xml:"BookgDt>Dt"`
+	ValueDate   string        `xml:"ValDt>Dt"`
+	ServicerRef string        `xml:"AcctSvcrRef"`
+	TxCode      camtTxCode    `xml:"BkTxCd>Prtry"`
+	Details     camtTxDetails `xml:"NtryDtls>TxDtls"`
+}
+
+type camtTxCode struct {
+	Code   string `xml:"Cd"`
+	Issuer string `xml:"Issr"`
+}
+
+type camtTxDetails struct {
+	ServicerRef    string `xml:"Refs>AcctSvcrRef"`
+	ClearingRef    string `xml:"Refs>ClrSysRef,omitempty"`
+	AdditionalInfo string `xml:"AddtlTxInf,omitempty"`
+}
+
+// camtAmountOf renders cents as a decimal amount and its credit/debit indicator
+func camtAmountOf(cents int64) (camtAmount, string) {
+	indicator := "CRDT"
+	if cents < 0 {
+		indicator = "DBIT"
+		cents = -cents
+	}
+	return camtAmount{Currency: statementCurrency, Value: fmt.Sprintf("%d.%02d", cents/100, cents%100)}, indicator
+}
+
+// renderCamt053 writes a camt.053 document with one statement per account and day. The
+// trace number of each leg is carried as the clearing system reference.
+func renderCamt053(w io.Writer, statement *Statement) error {
+	created := statement.GeneratedAt.In(calendar.Location).Format("2006-01-02T15:04:05-07:00")
+	document := camtDocument{Report: camtStatement{
+		GroupHeader: camtGroupHeader{
+			MessageID: fmt.Sprintf("%s-%s-%s", statementBankID, statement.From, statement.To),
+			CreatedAt: created,
+		},
+	}}
+
+	for _, day := range statement.Days {
+		for i := range day.Accounts {
+			account := &day.Accounts[i]
+			stmt := camtStmt{
+				ID:        fmt.Sprintf("%s-%s", account.AccountCode, day.Date),
+				CreatedAt: created,
+				Account: camtAccount{
+					ID:       account.AccountCode,
+					Currency: statementCurrency,
+					Name:     account.AccountName,
+					Servicer: statementBankID,
+				},
+			}
+
+			opening, openingIndicator := camtAmountOf(account.OpeningBalance)
+			closing, closingIndicator := camtAmountOf(account.ClosingBalance)
+			stmt.Balances = []camtBalance{
+				{Type: "OPBD", Amount: opening, Indicator: openingIndicator, Date: day.Date},
+				{Type: "CLBD", Amount: closing, Indicator: closingIndicator, Date: day.Date},
+			}
+
+			var credits, debits, creditCount, debitCount int64
+			for _, leg := range account.Entries {
+				credit := isStatementCredit(account, leg)
+				amount, indicator := camtAmountOf(leg.AmountCents)
+				if credit {
+					credits += leg.AmountCents
+					creditCount++
+				} else {
+					indicator = "DBIT"
+					debits += leg.AmountCents
+					debitCount++
+				}
+				stmt.Entries = append(stmt.Entries, camtEntry{
+					Reference:   leg.ID,
+					Amount:      amount,
+					Indicator:   indicator,
+					Status:      "BOOK",
+					BookingDate: leg.EffectiveDate,
+					ValueDate:   leg.EffectiveDate,
+					ServicerRef: leg.JournalEntryID,
+					TxCode:      camtTxCode{Code: baiTypeCode(credit, leg.TraceNumber), Issuer: "BAI"},
+					Details: camtTxDetails{
+						ServicerRef:    leg.ID,
+						ClearingRef:    leg.TraceNumber,
+						AdditionalInfo: leg.Description,
+					},
+				})
+			}
+
+			total, _ := camtAmountOf(credits + debits)
+			creditSum, _ := camtAmountOf(credits)
+			debitSum, _ := camtAmountOf(debits)
+			stmt.Summary = camtSummary{
+				Total:   camtTotal{Count: creditCount + debitCount, Sum: total.Value},
+				Credits: camtTotal{Count: creditCount, Sum: creditSum.Value},
+				Debits:  camtTotal{Count: debitCount, Sum: debitSum.Value},
+			}
+
+			document.Report.Statements = append(document.Report.Statements, stmt)
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
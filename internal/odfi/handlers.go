@@ -2,31 +2,43 @@ package odfi
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 
+	"ach-concourse/internal/common/calendar"
 	commonhttp "ach-concourse/internal/common/http"
+	"ach-concourse/internal/common/idempotency"
 )
 
 // Handler handles HTTP requests for ODFI
 type Handler struct {
 	service *Service
+	keys    *idempotency.Store
 }
 
 // NewHandler creates a new ODFI handler
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, keys *idempotency.Store) *Handler {
+	return &Handler{service: service, keys: keys}
 }
 
 // RegisterRoutes registers all ODFI routes
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/api/v1/entries", func(r chi.Router) {
-		r.Post("/", h.CreateEntry)
+		r.With(idempotency.Middleware(h.keys)).Post("/", h.CreateEntry)
 		r.Get("/", h.ListEntries)
 		r.Get("/{id}", h.GetEntry)
 		r.Patch("/{id}/status", h.UpdateStatus)
 	})
+	r.Route("/api/v1/batches", func(r chi.Router) {
+		r.With(idempotency.Middleware(h.keys)).Post("/", h.CreateBatch)
+		r.Get("/", h.ListBatches)
+		r.Get("/{id}", h.GetBatch)
+		r.With(idempotency.Middleware(h.keys)).Post("/{id}/entries", h.AddBatchEntry)
+		r.Post("/{id}/close", h.CloseBatch)
+		r.Post("/{id}/cancel", h.CancelBatch)
+	})
 	r.Get("/healthz", h.Health)
 }
 
@@ -40,7 +52,7 @@ func (h *Handler) CreateEntry(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.service.CreateEntry(r.Context(), &req)
 	if err != nil {
-		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		commonhttp.ErrorFrom(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -51,8 +63,13 @@ func (h *Handler) CreateEntry(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) ListEntries(w http.ResponseWriter, r *http.Request) {
 	status := r.URL.Query().Get("status")
 	traceNumber := r.URL.Query().Get("trace_number")
+	settlementFrom, settlementTo, err := settlementRange(r)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	entries, err := h.service.ListEntries(r.Context(), status, traceNumber)
+	entries, err := h.service.ListEntries(r.Context(), status, traceNumber, settlementFrom, settlementTo)
 	if err != nil {
 		commonhttp.Error(w, http.StatusInternalServerError, "failed to list entries")
 		return
@@ -107,8 +124,139 @@ func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 	commonhttp.JSON(w, http.StatusOK, entry)
 }
 
+// CreateBatch handles POST /api/v1/batches
+func (h *Handler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	var req CreateBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	batch, err := h.service.CreateBatch(r.Context(), &req)
+	if err != nil {
+		commonhttp.ErrorFrom(w, http.StatusBadRequest, err)
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, batch)
+}
+
+// ListBatches handles GET /api/v1/batches
+func (h *Handler) ListBatches(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	batches, err := h.service.ListBatches(r.Context(), status)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to list batches")
+		return
+	}
+
+	if batches == nil {
+		batches = []*ODFIBatch{}
+	}
+
+	commonhttp.JSON(w, http.StatusOK, batches)
+}
+
+// GetBatch handles GET /api/v1/batches/{id}
+func (h *Handler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	batch, err := h.service.GetBatch(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusInternalServerError, "failed to get batch")
+		return
+	}
+
+	if batch == nil {
+		commonhttp.Error(w, http.StatusNotFound, "batch not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, batch)
+}
+
+// AddBatchEntry handles POST /api/v1/batches/{id}/entries
+func (h *Handler) AddBatchEntry(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req CreateEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	entry, err := h.service.AddEntryToBatch(r.Context(), id, &req)
+	if err != nil {
+		commonhttp.ErrorFrom(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if entry == nil {
+		commonhttp.Error(w, http.StatusNotFound, "batch not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusCreated, entry)
+}
+
+// CloseBatch handles POST /api/v1/batches/{id}/close
+func (h *Handler) CloseBatch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	batch, err := h.service.CloseBatch(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if batch == nil {
+		commonhttp.Error(w, http.StatusNotFound, "batch not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, batch)
+}
+
+// CancelBatch handles POST /api/v1/batches/{id}/cancel
+func (h *Handler) CancelBatch(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	batch, err := h.service.CancelBatch(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if batch == nil {
+		commonhttp.Error(w, http.StatusNotFound, "batch not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, batch)
+}
+
+// settlementRange reads settlement_date (exact) or settlement_date_from/settlement_date_to
+func settlementRange(r *http.Request) (string, string, error) {
+	from := r.URL.Query().Get("settlement_date_from")
+	to := r.URL.Query().Get("settlement_date_to")
+	if date := r.URL.Query().Get("settlement_date"); date != "" {
+		from, to = date, date
+	}
+
+	for _, d := range []string{from, to} {
+		if d == "" {
+			continue
+		}
+		if _, err := calendar.ParseDate(d); err != nil {
+			return "", "", errors.New("settlement dates must be YYYY-MM-DD")
+		}
+	}
+
+	return from, to, nil
+}
+
 // Health handles GET /healthz
 func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 	commonhttp.Health(w)
 }
-
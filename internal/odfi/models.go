@@ -6,22 +6,57 @@ import (
 
 // ODFIEntry represents an origination ACH entry
 type ODFIEntry struct {
-	ID          string    `json:"id"`
-	TraceNumber string    `json:"trace_number"`
-	CompanyName string    `json:"company_name"`
-	SecCode     string    `json:"sec_code"`
-	AmountCents int64     `json:"amount_cents"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                string `json:"id"`
+	BatchID           string `json:"batch_id,omitempty"`
+	TraceNumber       string `json:"trace_number"`
+	CompanyName       string `json:"company_name"`
+	SecCode           string `json:"sec_code"`
+	AmountCents       int64  `json:"amount_cents"`
+	RoutingNumber     string `json:"routing_number"`
+	AccountNumber     string `json:"account_number"` // Masked, e.g. "****6789"
+	AccountType       string `json:"account_type"`
+	TransactionCode   string `json:"transaction_code"`
+	Direction         string `json:"direction"` // Derived from transaction_code
+	ReceiverName      string `json:"receiver_name,omitempty"`
+	ReceiverID        string `json:"receiver_id,omitempty"`
+	AuthorizationType string `json:"authorization_type,omitempty"` // WEB/TEL only
+	// EffectiveEntryDate is the date the originator asked to settle; SettlementDate is when
+	// it will actually settle after weekends, holidays and same-day cutoffs (YYYY-MM-DD)
+	EffectiveEntryDate string    `json:"effective_entry_date,omitempty"`
+	SettlementDate     string    `json:"settlement_date,omitempty"`
+	Status             string    `json:"status"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+	Addenda            []Addenda `json:"addenda,omitempty"`
+
+	// Storage-only account number fields; never serialized
+	AccountNumberEncrypted string `json:"-"`
+	AccountNumberLast4     string `json:"-"`
 }
 
 // CreateEntryRequest represents the request to create an ODFI entry
 type CreateEntryRequest struct {
-	TraceNumber string `json:"trace_number"`
-	CompanyName string `json:"company_name"`
-	SecCode     string `json:"sec_code"`
-	AmountCents int64  `json:"amount_cents"`
+	TraceNumber     string `json:"trace_number"`
+	CompanyName     string `json:"company_name"`
+	SecCode         string `json:"sec_code"`
+	AmountCents     int64  `json:"amount_cents"`
+	RoutingNumber   string `json:"routing_number"`
+	AccountNumber   string `json:"account_number"`
+	AccountType     string `json:"account_type"`
+	TransactionCode string `json:"transaction_code"`
+	// EffectiveEntryDate is optional (YYYY-MM-DD); defaults to the next business day
+	EffectiveEntryDate string   `json:"effective_entry_date"`
+	ReceiverName       string   `json:"receiver_name"`
+	ReceiverID         string   `json:"receiver_id"`
+	AuthorizationType  string   `json:"authorization_type"`
+	Addenda            []string `json:"addenda"` // Payment related information, one per 05 addenda record
+}
+
+// Addenda represents a payment related (type 05) addenda record
+type Addenda struct {
+	SequenceNumber            int    `json:"sequence_number"`
+	TypeCode                  string `json:"type_code"`
+	PaymentRelatedInformation string `json:"payment_related_information"`
 }
 
 // UpdateStatusRequest represents the request to update an entry status
@@ -29,6 +64,33 @@ type UpdateStatusRequest struct {
 	Status string `json:"status"`
 }
 
+// ODFIBatch represents an ACH batch: a company/batch header plus the entries under it
+type ODFIBatch struct {
+	ID                      string       `json:"id"`
+	CompanyName             string       `json:"company_name"`
+	CompanyID               string       `json:"company_id"`
+	CompanyEntryDescription string       `json:"company_entry_description"`
+	SecCode                 string       `json:"sec_code"`
+	EffectiveEntryDate      string       `json:"effective_entry_date"`
+	Status                  string       `json:"status"`
+	EntryCount              int64        `json:"entry_count"`
+	TotalDebitCents         int64        `json:"total_debit_cents"`
+	TotalCreditCents        int64        `json:"total_credit_cents"`
+	CreatedAt               time.Time    `json:"created_at"`
+	UpdatedAt               time.Time    `json:"updated_at"`
+	Entries                 []*ODFIEntry `json:"entries,omitempty"`
+}
+
+// CreateBatchRequest represents the request to open a new batch
+type CreateBatchRequest struct {
+	CompanyName             string `json:"company_name"`
+	CompanyID               string `json:"company_id"`
+	CompanyEntryDescription string `json:"company_entry_description"`
+	SecCode                 string `json:"sec_code"`
+	// EffectiveEntryDate is optional (YYYY-MM-DD); defaults to the next business day
+	EffectiveEntryDate string `json:"effective_entry_date"`
+}
+
 // Status constants
 const (
 	StatusPending   = "PENDING"
@@ -36,3 +98,14 @@ const (
 	StatusCancelled = "CANCELLED"
 )
 
+// entryTransitions lists the statuses each entry status may move to
+var entryTransitions = map[string]map[string]bool{
+	StatusPending: {StatusSent: true, StatusCancelled: true},
+}
+
+// Batch status constants
+const (
+	BatchStatusOpen      = "OPEN"
+	BatchStatusClosed    = "CLOSED"
+	BatchStatusCancelled = "CANCELLED"
+)
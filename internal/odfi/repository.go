@@ -3,23 +3,61 @@ package odfi
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+
+	"ach-concourse/internal/common/ach"
+	"ach-concourse/internal/common/calendar"
+	"ach-concourse/internal/common/events"
 )
 
+// outboxTable holds entry status changes waiting to be delivered to the ledger
+const outboxTable = "odfi_entry_events"
+
 // Repository handles database operations for ODFI entries
 type Repository struct {
-	db *sql.DB
+	db     *sql.DB
+	outbox *events.Outbox
 }
 
 // NewRepository creates a new ODFI repository
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, outbox: events.NewOutbox(db, outboxTable, "ODFI")}
+}
+
+// Outbox returns the outbox that status changes are recorded in
+func (r *Repository) Outbox() *events.Outbox {
+	return r.outbox
 }
 
+// Batch state errors returned by the batch operations
+var (
+	ErrBatchNotOpen        = errors.New("batch is not open")
+	ErrBatchHasSentEntries = errors.New("batch has entries that were already sent")
+	ErrBatchEmpty          = errors.New("batch has no entries")
+)
+
+// ErrInvalidTransition is returned when an entry cannot move to the requested status
+var ErrInvalidTransition = errors.New("invalid status transition")
+
 const schema = `
+CREATE TABLE IF NOT EXISTS odfi_batches (
+	id UUID PRIMARY KEY,
+	company_name TEXT NOT NULL,
+	company_id TEXT NOT NULL,
+	company_entry_description TEXT NOT NULL,
+	sec_code TEXT NOT NULL,
+	effective_entry_date DATE NOT NULL,
+	status TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_odfi_batches_status ON odfi_batches(status);
+
 CREATE TABLE IF NOT EXISTS odfi_entries (
 	id UUID PRIMARY KEY,
 	trace_number TEXT NOT NULL,
@@ -31,46 +69,200 @@ CREATE TABLE IF NOT EXISTS odfi_entries (
 	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
 
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS routing_number TEXT;
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS account_number_encrypted TEXT;
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS account_number_last4 TEXT;
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS account_type TEXT;
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS transaction_code TEXT;
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS effective_entry_date DATE;
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS settlement_date DATE;
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS batch_id UUID REFERENCES odfi_batches(id);
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS receiver_name TEXT;
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS receiver_id TEXT;
+ALTER TABLE odfi_entries ADD COLUMN IF NOT EXISTS authorization_type TEXT;
+
 CREATE INDEX IF NOT EXISTS idx_odfi_entries_trace_number ON odfi_entries(trace_number);
 CREATE INDEX IF NOT EXISTS idx_odfi_entries_status ON odfi_entries(status);
+CREATE INDEX IF NOT EXISTS idx_odfi_entries_settlement_date ON odfi_entries(settlement_date);
+CREATE INDEX IF NOT EXISTS idx_odfi_entries_batch_id ON odfi_entries(batch_id);
+
+CREATE TABLE IF NOT EXISTS odfi_entry_addenda (
+	entry_id UUID NOT NULL REFERENCES odfi_entries(id),
+	sequence_number INT NOT NULL,
+	type_code TEXT NOT NULL,
+	payment_related_information TEXT NOT NULL,
+	PRIMARY KEY (entry_id, sequence_number)
+);
 `
 
+// entryColumns is the column list shared by every ODFI entry query, in scanEntry order
+const entryColumns = `id, batch_id, trace_number, company_name, sec_code, amount_cents,
+	routing_number, account_number_last4, account_type, transaction_code,
+	receiver_name, receiver_id, authorization_type,
+	effective_entry_date, settlement_date,
+	status, created_at, updated_at`
+
 // GetSchema returns the SQL schema for ODFI tables
 func GetSchema() string {
-	return schema
+	return schema + events.OutboxSchema(outboxTable)
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// scanEntry scans a row selected with entryColumns into an ODFIEntry
+func scanEntry(row rowScanner) (*ODFIEntry, error) {
+	entry := &ODFIEntry{}
+	var batchID, companyName, secCode, routingNumber, last4, accountType, transactionCode sql.NullString
+	var receiverName, receiverID, authorizationType sql.NullString
+	var amountCents sql.NullInt64
+	var effectiveDate, settlementDate sql.NullTime
+
+	err := row.Scan(
+		&entry.ID, &batchID, &entry.TraceNumber, &companyName, &secCode, &amountCents,
+		&routingNumber, &last4, &accountType, &transactionCode,
+		&receiverName, &receiverID, &authorizationType,
+		&effectiveDate, &settlementDate,
+		&entry.Status, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.BatchID = batchID.String
+	entry.CompanyName = companyName.String
+	entry.SecCode = secCode.String
+	entry.AmountCents = amountCents.Int64
+	entry.RoutingNumber = routingNumber.String
+	entry.AccountNumberLast4 = last4.String
+	entry.AccountNumber = ach.MaskAccountNumber(last4.String)
+	entry.AccountType = accountType.String
+	entry.TransactionCode = transactionCode.String
+	entry.Direction = ach.DirectionForTransactionCode(transactionCode.String)
+	entry.ReceiverName = receiverName.String
+	entry.ReceiverID = receiverID.String
+	entry.AuthorizationType = authorizationType.String
+
+	if effectiveDate.Valid {
+		entry.EffectiveEntryDate = effectiveDate.Time.Format(calendar.DateLayout)
+	}
+	if settlementDate.Valid {
+		entry.SettlementDate = settlementDate.Time.Format(calendar.DateLayout)
+	}
+
+	return entry, nil
 }
 
-// Create creates a new ODFI entry
+// Create creates a new ODFI entry.
+// The account number is written only as ciphertext plus its last four characters.
 func (r *Repository) Create(ctx context.Context, entry *ODFIEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+	if err := r.recordCreated(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateInBatch adds an entry to a batch, locking the batch so it cannot be closed
+// or cancelled concurrently. Returns ErrBatchNotOpen if the batch no longer accepts entries.
+func (r *Repository) CreateInBatch(ctx context.Context, entry *ODFIEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, found, err := lockBatch(ctx, tx, entry.BatchID)
+	if err != nil {
+		return err
+	}
+	if !found || status != BatchStatusOpen {
+		return ErrBatchNotOpen
+	}
+
+	if err := insertEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+	if err := r.recordCreated(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertEntry writes a new entry row and its addenda records inside tx
+func insertEntry(ctx context.Context, tx execer, entry *ODFIEntry) error {
 	entry.ID = uuid.New().String()
 	entry.CreatedAt = time.Now()
 	entry.UpdatedAt = time.Now()
 
 	query := `
-		INSERT INTO odfi_entries (id, trace_number, company_name, sec_code, amount_cents, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO odfi_entries (id, batch_id, trace_number, company_name, sec_code, amount_cents,
+			routing_number, account_number_encrypted, account_number_last4, account_type, transaction_code,
+			receiver_name, receiver_id, authorization_type,
+			effective_entry_date, settlement_date,
+			status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		entry.ID, entry.TraceNumber, entry.CompanyName, entry.SecCode,
-		entry.AmountCents, entry.Status, entry.CreatedAt, entry.UpdatedAt)
+	_, err := tx.ExecContext(ctx, query,
+		entry.ID, nullString(entry.BatchID), entry.TraceNumber, entry.CompanyName, entry.SecCode, entry.AmountCents,
+		entry.RoutingNumber, entry.AccountNumberEncrypted, entry.AccountNumberLast4, entry.AccountType, entry.TransactionCode,
+		nullString(entry.ReceiverName), nullString(entry.ReceiverID), nullString(entry.AuthorizationType),
+		nullString(entry.EffectiveEntryDate), nullString(entry.SettlementDate),
+		entry.Status, entry.CreatedAt, entry.UpdatedAt)
+	if err != nil {
+		return err
+	}
 
-	return err
+	for _, addenda := range entry.Addenda {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO odfi_entry_addenda (entry_id, sequence_number, type_code, payment_related_information)
+			VALUES ($1, $2, $3, $4)
+		`, entry.ID, addenda.SequenceNumber, addenda.TypeCode, addenda.PaymentRelatedInformation)
+		if err != nil {
+			return err
+		}
+	}
+
+	entry.AccountNumber = ach.MaskAccountNumber(entry.AccountNumberLast4)
+	entry.Direction = ach.DirectionForTransactionCode(entry.TransactionCode)
+
+	return nil
 }
 
-// GetByID retrieves an ODFI entry by ID
-func (r *Repository) GetByID(ctx context.Context, id string) (*ODFIEntry, error) {
-	query := `
-		SELECT id, trace_number, company_name, sec_code, amount_cents, status, created_at, updated_at
-		FROM odfi_entries
-		WHERE id = $1
-	`
+// recordCreated queues the event for a new entry, letting the ledger place holds against it
+func (r *Repository) recordCreated(ctx context.Context, tx execer, entry *ODFIEntry) error {
+	return r.outbox.Record(ctx, tx, &events.EntryEvent{
+		EntryID:        entry.ID,
+		TraceNumber:    entry.TraceNumber,
+		Status:         entry.Status,
+		Direction:      entry.Direction,
+		AmountCents:    entry.AmountCents,
+		SettlementDate: entry.SettlementDate,
+	})
+}
 
-	entry := &ODFIEntry{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&entry.ID, &entry.TraceNumber, &entry.CompanyName, &entry.SecCode,
-		&entry.AmountCents, &entry.Status, &entry.CreatedAt, &entry.UpdatedAt)
+// GetByID retrieves an ODFI entry by ID, including its addenda records
+func (r *Repository) GetByID(ctx context.Context, id string) (*ODFIEntry, error) {
+	query := `SELECT ` + entryColumns + ` FROM odfi_entries WHERE id = $1`
 
+	entry, err := scanEntry(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -78,16 +270,45 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*ODFIEntry, error)
 		return nil, err
 	}
 
+	entry.Addenda, err = r.listAddenda(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	return entry, nil
 }
 
-// List retrieves ODFI entries with optional filters
-func (r *Repository) List(ctx context.Context, status, traceNumber string) ([]*ODFIEntry, error) {
+// listAddenda retrieves the addenda records for an entry in sequence order
+func (r *Repository) listAddenda(ctx context.Context, entryID string) ([]Addenda, error) {
 	query := `
-		SELECT id, trace_number, company_name, sec_code, amount_cents, status, created_at, updated_at
-		FROM odfi_entries
-		WHERE 1=1
+		SELECT sequence_number, type_code, payment_related_information
+		FROM odfi_entry_addenda
+		WHERE entry_id = $1
+		ORDER BY sequence_number
 	`
+
+	rows, err := r.db.QueryContext(ctx, query, entryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var addenda []Addenda
+	for rows.Next() {
+		var a Addenda
+		if err := rows.Scan(&a.SequenceNumber, &a.TypeCode, &a.PaymentRelatedInformation); err != nil {
+			return nil, err
+		}
+		addenda = append(addenda, a)
+	}
+
+	return addenda, rows.Err()
+}
+
+// List retrieves ODFI entries with optional filters.
+// settlementFrom and settlementTo are inclusive YYYY-MM-DD bounds on settlement_date.
+func (r *Repository) List(ctx context.Context, status, traceNumber, settlementFrom, settlementTo string) ([]*ODFIEntry, error) {
+	query := `SELECT ` + entryColumns + ` FROM odfi_entries WHERE 1=1`
 	args := []interface{}{}
 	argNum := 1
 
@@ -103,6 +324,18 @@ func (r *Repository) List(ctx context.Context, status, traceNumber string) ([]*O
 		argNum++
 	}
 
+	if settlementFrom != "" {
+		query += fmt.Sprintf(" AND settlement_date >= $%d", argNum)
+		args = append(args, settlementFrom)
+		argNum++
+	}
+
+	if settlementTo != "" {
+		query += fmt.Sprintf(" AND settlement_date <= $%d", argNum)
+		args = append(args, settlementTo)
+		argNum++
+	}
+
 	query += " ORDER BY created_at DESC"
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -113,10 +346,7 @@ func (r *Repository) List(ctx context.Context, status, traceNumber string) ([]*O
 
 	var entries []*ODFIEntry
 	for rows.Next() {
-		entry := &ODFIEntry{}
-		err := rows.Scan(
-			&entry.ID, &entry.TraceNumber, &entry.CompanyName, &entry.SecCode,
-			&entry.AmountCents, &entry.Status, &entry.CreatedAt, &entry.UpdatedAt)
+		entry, err := scanEntry(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -126,20 +356,121 @@ func (r *Repository) List(ctx context.Context, status, traceNumber string) ([]*O
 	return entries, rows.Err()
 }
 
-// UpdateStatus updates the status of an ODFI entry
+// UpdateStatus moves an ODFI entry to a new status and records the change in the outbox.
+// Returns ErrInvalidTransition if the entry's current status does not allow the move.
 func (r *Repository) UpdateStatus(ctx context.Context, id, status string) (*ODFIEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var current string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM odfi_entries WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !entryTransitions[current][status] {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, current, status)
+	}
+
 	query := `
 		UPDATE odfi_entries
 		SET status = $1, updated_at = $2
 		WHERE id = $3
-		RETURNING id, trace_number, company_name, sec_code, amount_cents, status, created_at, updated_at
+		RETURNING ` + entryColumns
+
+	entry, err := scanEntry(tx.QueryRowContext(ctx, query, status, time.Now(), id))
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.outbox.Record(ctx, tx, &events.EntryEvent{
+		EntryID:        entry.ID,
+		TraceNumber:    entry.TraceNumber,
+		Status:         status,
+		PreviousStatus: current,
+		Direction:      entry.Direction,
+		AmountCents:    entry.AmountCents,
+		SettlementDate: entry.SettlementDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// nullString converts an empty string (date or UUID) to NULL
+func nullString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
+
+// batchColumns selects a batch with its entry count and summed totals, in scanBatch order.
+// Queries using it must LEFT JOIN odfi_entries e and GROUP BY b.id.
+const batchColumns = `b.id, b.company_name, b.company_id, b.company_entry_description, b.sec_code,
+	b.effective_entry_date, b.status, b.created_at, b.updated_at,
+	COUNT(e.id),
+	COALESCE(SUM(e.amount_cents) FILTER (WHERE e.transaction_code IN ('27', '37')), 0),
+	COALESCE(SUM(e.amount_cents) FILTER (WHERE e.transaction_code IN ('22', '32')), 0)`
+
+// scanBatch scans a row selected with batchColumns into an ODFIBatch
+func scanBatch(row rowScanner) (*ODFIBatch, error) {
+	batch := &ODFIBatch{}
+	var effectiveDate time.Time
+
+	err := row.Scan(
+		&batch.ID, &batch.CompanyName, &batch.CompanyID, &batch.CompanyEntryDescription, &batch.SecCode,
+		&effectiveDate, &batch.Status, &batch.CreatedAt, &batch.UpdatedAt,
+		&batch.EntryCount, &batch.TotalDebitCents, &batch.TotalCreditCents)
+	if err != nil {
+		return nil, err
+	}
+
+	batch.EffectiveEntryDate = effectiveDate.Format(calendar.DateLayout)
+	return batch, nil
+}
+
+// CreateBatch creates a new batch header
+func (r *Repository) CreateBatch(ctx context.Context, batch *ODFIBatch) error {
+	batch.ID = uuid.New().String()
+	batch.CreatedAt = time.Now()
+	batch.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO odfi_batches (id, company_name, company_id, company_entry_description, sec_code,
+			effective_entry_date, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	entry := &ODFIEntry{}
-	err := r.db.QueryRowContext(ctx, query, status, time.Now(), id).Scan(
-		&entry.ID, &entry.TraceNumber, &entry.CompanyName, &entry.SecCode,
-		&entry.AmountCents, &entry.Status, &entry.CreatedAt, &entry.UpdatedAt)
+	_, err := r.db.ExecContext(ctx, query,
+		batch.ID, batch.CompanyName, batch.CompanyID, batch.CompanyEntryDescription, batch.SecCode,
+		batch.EffectiveEntryDate, batch.Status, batch.CreatedAt, batch.UpdatedAt)
+
+	return err
+}
+
+// GetBatch retrieves a batch with its totals
+func (r *Repository) GetBatch(ctx context.Context, id string) (*ODFIBatch, error) {
+	query := `
+		SELECT ` + batchColumns + `
+		FROM odfi_batches b
+		LEFT JOIN odfi_entries e ON e.batch_id = b.id
+		WHERE b.id = $1
+		GROUP BY b.id
+	`
 
+	batch, err := scanBatch(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -147,6 +478,200 @@ func (r *Repository) UpdateStatus(ctx context.Context, id, status string) (*ODFI
 		return nil, err
 	}
 
-	return entry, nil
+	return batch, nil
+}
+
+// ListBatches retrieves batches with their totals, optionally filtered by status
+func (r *Repository) ListBatches(ctx context.Context, status string) ([]*ODFIBatch, error) {
+	query := `
+		SELECT ` + batchColumns + `
+		FROM odfi_batches b
+		LEFT JOIN odfi_entries e ON e.batch_id = b.id
+		WHERE 1=1
+	`
+	args := []interface{}{}
+
+	if status != "" {
+		query += " AND b.status = $1"
+		args = append(args, status)
+	}
+
+	query += " GROUP BY b.id ORDER BY b.created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []*ODFIBatch
+	for rows.Next() {
+		batch, err := scanBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, batch)
+	}
+
+	return batches, rows.Err()
 }
 
+// ListBatchEntries retrieves the entries belonging to a batch
+func (r *Repository) ListBatchEntries(ctx context.Context, batchID string) ([]*ODFIEntry, error) {
+	query := `SELECT ` + entryColumns + ` FROM odfi_entries WHERE batch_id = $1 ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*ODFIEntry
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// CloseBatch moves an open, non-empty batch to CLOSED.
+// Returns (false, nil) if the batch does not exist.
+func (r *Repository) CloseBatch(ctx context.Context, id string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	status, found, err := lockBatch(ctx, tx, id)
+	if err != nil || !found {
+		return false, err
+	}
+	if status != BatchStatusOpen {
+		return true, ErrBatchNotOpen
+	}
+
+	var entryCount int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM odfi_entries WHERE batch_id = $1`, id).Scan(&entryCount); err != nil {
+		return true, err
+	}
+	if entryCount == 0 {
+		return true, ErrBatchEmpty
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE odfi_batches SET status = $1, updated_at = $2 WHERE id = $3`,
+		BatchStatusClosed, time.Now(), id); err != nil {
+		return true, err
+	}
+
+	return true, tx.Commit()
+}
+
+// CancelBatch cancels a batch and all of its pending entries in one transaction,
+// recording a status change event for each cancelled entry.
+// A batch whose entries were already sent cannot be cancelled.
+// Returns (false, nil) if the batch does not exist.
+func (r *Repository) CancelBatch(ctx context.Context, id string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	status, found, err := lockBatch(ctx, tx, id)
+	if err != nil || !found {
+		return false, err
+	}
+	if status == BatchStatusCancelled {
+		return true, ErrBatchNotOpen
+	}
+
+	// Lock the batch's entries, as UpdateStatus does, so none can be sent between this check
+	// and the cancellation below
+	statuses, err := tx.QueryContext(ctx,
+		`SELECT status FROM odfi_entries WHERE batch_id = $1 ORDER BY id FOR UPDATE`, id)
+	if err != nil {
+		return true, err
+	}
+	sentCount := 0
+	for statuses.Next() {
+		var entryStatus string
+		if err := statuses.Scan(&entryStatus); err != nil {
+			statuses.Close()
+			return true, err
+		}
+		if entryStatus == StatusSent {
+			sentCount++
+		}
+	}
+	statuses.Close()
+	if err := statuses.Err(); err != nil {
+		return true, err
+	}
+	if sentCount > 0 {
+		return true, ErrBatchHasSentEntries
+	}
+
+	now := time.Now()
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE odfi_entries SET status = $1, updated_at = $2
+		WHERE batch_id = $3 AND status = $4
+		RETURNING id, trace_number, amount_cents, transaction_code, settlement_date
+	`, StatusCancelled, now, id, StatusPending)
+	if err != nil {
+		return true, err
+	}
+	var cancelled []*events.EntryEvent
+	for rows.Next() {
+		var amountCents sql.NullInt64
+		var transactionCode sql.NullString
+		var settlementDate sql.NullTime
+		event := &events.EntryEvent{Status: StatusCancelled, PreviousStatus: StatusPending}
+		if err := rows.Scan(&event.EntryID, &event.TraceNumber, &amountCents, &transactionCode, &settlementDate); err != nil {
+			rows.Close()
+			return true, err
+		}
+		event.AmountCents = amountCents.Int64
+		event.Direction = ach.DirectionForTransactionCode(transactionCode.String)
+		if settlementDate.Valid {
+			event.SettlementDate = settlementDate.Time.Format(calendar.DateLayout)
+		}
+		cancelled = append(cancelled, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return true, err
+	}
+
+	for _, event := range cancelled {
+		if err := r.outbox.Record(ctx, tx, event); err != nil {
+			return true, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE odfi_batches SET status = $1, updated_at = $2 WHERE id = $3`,
+		BatchStatusCancelled, now, id); err != nil {
+		return true, err
+	}
+
+	return true, tx.Commit()
+}
+
+// lockBatch reads a batch status with FOR UPDATE inside tx
+func lockBatch(ctx context.Context, tx *sql.Tx, id string) (string, bool, error) {
+	var status string
+	err := tx.QueryRowContext(ctx, `SELECT status FROM odfi_batches WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return status, true, nil
+}
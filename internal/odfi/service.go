@@ -3,36 +3,102 @@ package odfi
 import (
 	"context"
 	"errors"
+	"time"
+
+	"ach-concourse/internal/common/ach"
+	"ach-concourse/internal/common/calendar"
+	commoncrypto "ach-concourse/internal/common/crypto"
 )
 
 // Service handles business logic for ODFI entries
 type Service struct {
-	repo *Repository
+	repo     *Repository
+	cipher   *commoncrypto.FieldCipher
+	calendar *calendar.Calendar
 }
 
 // NewService creates a new ODFI service
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, cipher *commoncrypto.FieldCipher, cal *calendar.Calendar) *Service {
+	return &Service{repo: repo, cipher: cipher, calendar: cal}
 }
 
 // CreateEntry creates a new ODFI entry
 func (s *Service) CreateEntry(ctx context.Context, req *CreateEntryRequest) (*ODFIEntry, error) {
-	if req.TraceNumber == "" {
-		return nil, errors.New("trace_number is required")
+	entry, err := s.buildEntry(req)
+	if err != nil {
+		return nil, err
 	}
 
-	entry := &ODFIEntry{
-		TraceNumber: req.TraceNumber,
-		CompanyName: req.CompanyName,
-		SecCode:     req.SecCode,
-		AmountCents: req.AmountCents,
-		Status:      StatusPending,
+	if err := s.repo.Create(ctx, entry); err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.Create(ctx, entry); err != nil {
+	return entry, nil
+}
+
+// buildEntry validates a create request and returns the entry to insert
+func (s *Service) buildEntry(req *CreateEntryRequest) (*ODFIEntry, error) {
+	errs := validateEntryRequest(req)
+
+	now := time.Now()
+	var effective time.Time
+	if req.EffectiveEntryDate != "" {
+		parsed, err := calendar.ParseDate(req.EffectiveEntryDate)
+		if err != nil {
+			errs.Add("effective_entry_date", "must be YYYY-MM-DD")
+		} else if !s.calendar.Covers(parsed) {
+			errs.Add("effective_entry_date", "is outside the holiday calendar")
+		}
+		effective = parsed
+	} else {
+		next, err := s.calendar.NextBusinessDay(now)
+		if err != nil {
+			return nil, err
+		}
+		effective = next
+	}
+
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	tc, _ := ach.LookupTransactionCode(req.TransactionCode)
+	settlement, err := s.calendar.SettlementDate(effective, now, req.AmountCents)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.cipher.Encrypt(req.AccountNumber)
+	if err != nil {
 		return nil, err
 	}
 
+	entry := &ODFIEntry{
+		TraceNumber:            req.TraceNumber,
+		CompanyName:            req.CompanyName,
+		SecCode:                req.SecCode,
+		AmountCents:            req.AmountCents,
+		RoutingNumber:          req.RoutingNumber,
+		AccountType:            tc.AccountType,
+		TransactionCode:        tc.Code,
+		ReceiverName:           req.ReceiverName,
+		ReceiverID:             req.ReceiverID,
+		AuthorizationType:      req.AuthorizationType,
+		EffectiveEntryDate:     calendar.FormatDate(effective),
+		SettlementDate:         calendar.FormatDate(settlement),
+		Status:                 StatusPending,
+		AccountNumberEncrypted: encrypted,
+		AccountNumberLast4:     ach.Last4(req.AccountNumber),
+	}
+
+	for i, info := range req.Addenda {
+		entry.Addenda = append(entry.Addenda, Addenda{
+			SequenceNumber:            i + 1,
+			TypeCode:                  ach.AddendaTypePaymentRelated,
+			PaymentRelatedInformation: info,
+		})
+	}
+
 	return entry, nil
 }
 
@@ -42,8 +108,8 @@ func (s *Service) GetEntry(ctx context.Context, id string) (*ODFIEntry, error) {
 }
 
 // ListEntries retrieves ODFI entries with optional filters
-func (s *Service) ListEntries(ctx context.Context, status, traceNumber string) ([]*ODFIEntry, error) {
-	return s.repo.List(ctx, status, traceNumber)
+func (s *Service) ListEntries(ctx context.Context, status, traceNumber, settlementFrom, settlementTo string) ([]*ODFIEntry, error) {
+	return s.repo.List(ctx, status, traceNumber, settlementFrom, settlementTo)
 }
 
 // UpdateEntryStatus updates the status of an ODFI entry
@@ -62,3 +128,115 @@ func (s *Service) UpdateEntryStatus(ctx context.Context, id string, status strin
 	return s.repo.UpdateStatus(ctx, id, status)
 }
 
+// CreateBatch opens a new batch
+func (s *Service) CreateBatch(ctx context.Context, req *CreateBatchRequest) (*ODFIBatch, error) {
+	errs := validateBatchRequest(req)
+
+	var effective time.Time
+	if req.EffectiveEntryDate != "" {
+		parsed, err := calendar.ParseDate(req.EffectiveEntryDate)
+		if err != nil {
+			errs.Add("effective_entry_date", "must be YYYY-MM-DD")
+		} else if !s.calendar.Covers(parsed) {
+			errs.Add("effective_entry_date", "is outside the holiday calendar")
+		}
+		effective = parsed
+	}
+
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+
+	if effective.IsZero() {
+		next, err := s.calendar.NextBusinessDay(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		effective = next
+	}
+
+	batch := &ODFIBatch{
+		CompanyName:             req.CompanyName,
+		CompanyID:               req.CompanyID,
+		CompanyEntryDescription: req.CompanyEntryDescription,
+		SecCode:                 req.SecCode,
+		EffectiveEntryDate:      calendar.FormatDate(effective),
+		Status:                  BatchStatusOpen,
+	}
+
+	if err := s.repo.CreateBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+// GetBatch retrieves a batch with its totals and entries
+func (s *Service) GetBatch(ctx context.Context, id string) (*ODFIBatch, error) {
+	batch, err := s.repo.GetBatch(ctx, id)
+	if err != nil || batch == nil {
+		return batch, err
+	}
+
+	entries, err := s.repo.ListBatchEntries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	batch.Entries = entries
+
+	return batch, nil
+}
+
+// ListBatches retrieves batches with their totals
+func (s *Service) ListBatches(ctx context.Context, status string) ([]*ODFIBatch, error) {
+	return s.repo.ListBatches(ctx, status)
+}
+
+// AddEntryToBatch creates an entry under an open batch.
+// Company name, SEC code and effective entry date come from the batch header.
+// Returns (nil, nil) if the batch does not exist.
+func (s *Service) AddEntryToBatch(ctx context.Context, batchID string, req *CreateEntryRequest) (*ODFIEntry, error) {
+	batch, err := s.repo.GetBatch(ctx, batchID)
+	if err != nil || batch == nil {
+		return nil, err
+	}
+	if batch.Status != BatchStatusOpen {
+		return nil, ErrBatchNotOpen
+	}
+
+	req.CompanyName = batch.CompanyName
+	req.SecCode = batch.SecCode
+	req.EffectiveEntryDate = batch.EffectiveEntryDate
+
+	entry, err := s.buildEntry(req)
+	if err != nil {
+		return nil, err
+	}
+	entry.BatchID = batch.ID
+
+	if err := s.repo.CreateInBatch(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// CloseBatch closes an open batch to further entries.
+// Returns (nil, nil) if the batch does not exist.
+func (s *Service) CloseBatch(ctx context.Context, id string) (*ODFIBatch, error) {
+	found, err := s.repo.CloseBatch(ctx, id)
+	if err != nil || !found {
+		return nil, err
+	}
+	return s.GetBatch(ctx, id)
+}
+
+// CancelBatch cancels a batch and all of its pending entries atomically.
+// Returns (nil, nil) if the batch does not exist.
+func (s *Service) CancelBatch(ctx context.Context, id string) (*ODFIBatch, error) {
+	found, err := s.repo.CancelBatch(ctx, id)
+	if err != nil || !found {
+		return nil, err
+	}
+	return s.GetBatch(ctx, id)
+}
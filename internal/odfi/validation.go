@@ -0,0 +1,114 @@
+package odfi
+
+import (
+	"fmt"
+	"strings"
+
+	"ach-concourse/internal/common/ach"
+	"ach-concourse/internal/common/validation"
+)
+
+// validateEntryRequest checks a create request against the account rules and the
+// rules for its SEC code, returning every failing field
+func validateEntryRequest(req *CreateEntryRequest) validation.Errors {
+	var errs validation.Errors
+
+	if req.TraceNumber == "" {
+		errs.Add("trace_number", "is required")
+	}
+	if req.AmountCents <= 0 {
+		errs.Add("amount_cents", "must be greater than zero")
+	} else if req.AmountCents > ach.MaxEntryAmountCents {
+		errs.Add("amount_cents", fmt.Sprintf("must be at most %d", ach.MaxEntryAmountCents))
+	}
+
+	errs = append(errs, ach.ValidateAccountDetails(req.RoutingNumber, req.AccountNumber, req.AccountType, req.TransactionCode)...)
+
+	if req.SecCode == "" {
+		errs.Add("sec_code", "is required")
+		return errs
+	}
+
+	rule, ok := ach.LookupSecRule(req.SecCode)
+	if !ok {
+		errs.Add("sec_code", "must be one of "+strings.Join(ach.SupportedSecCodes, ", "))
+		return errs
+	}
+
+	return append(errs, validateSecRule(rule, req)...)
+}
+
+// validateSecRule applies the SEC-code-specific checks
+func validateSecRule(rule ach.SecRule, req *CreateEntryRequest) validation.Errors {
+	var errs validation.Errors
+
+	if _, known := ach.LookupTransactionCode(req.TransactionCode); known && !rule.AllowsTransactionCode(req.TransactionCode) {
+		errs.Add("transaction_code", fmt.Sprintf("%s entries allow transaction codes %s",
+			rule.Code, strings.Join(rule.AllowedTransactionCodes, ", ")))
+	}
+
+	if rule.RequireReceiverName && strings.TrimSpace(req.ReceiverName) == "" {
+		errs.Add("receiver_name", fmt.Sprintf("is required for %s entries", rule.Code))
+	} else if rule.ReceiverNameMaxLength > 0 && len(req.ReceiverName) > rule.ReceiverNameMaxLength {
+		errs.Add("receiver_name", fmt.Sprintf("must be at most %d characters for %s entries", rule.ReceiverNameMaxLength, rule.Code))
+	}
+
+	if rule.RequireReceiverID && strings.TrimSpace(req.ReceiverID) == "" {
+		errs.Add("receiver_id", fmt.Sprintf("is required for %s entries", rule.Code))
+	} else if len(req.ReceiverID) > 15 {
+		errs.Add("receiver_id", "must be at most 15 characters")
+	}
+
+	if rule.RequireAuthorizationType {
+		if req.AuthorizationType != ach.AuthorizationSingle && req.AuthorizationType != ach.AuthorizationRecurring {
+			errs.Add("authorization_type", fmt.Sprintf("must be %s or %s for %s entries",
+				ach.AuthorizationSingle, ach.AuthorizationRecurring, rule.Code))
+		}
+	} else if req.AuthorizationType != "" {
+		errs.Add("authorization_type", fmt.Sprintf("is not used for %s entries", rule.Code))
+	}
+
+	if rule.MaxAddenda == 0 && len(req.Addenda) > 0 {
+		errs.Add("addenda", fmt.Sprintf("is not allowed for %s entries", rule.Code))
+	} else if len(req.Addenda) > rule.MaxAddenda {
+		errs.Add("addenda", fmt.Sprintf("%s entries allow at most %d addenda records", rule.Code, rule.MaxAddenda))
+	}
+	for i, info := range req.Addenda {
+		if strings.TrimSpace(info) == "" {
+			errs.Add(fmt.Sprintf("addenda[%d]", i), "must not be empty")
+		} else if len(info) > ach.AddendaInfoMaxLength {
+			errs.Add(fmt.Sprintf("addenda[%d]", i), fmt.Sprintf("must be at most %d characters", ach.AddendaInfoMaxLength))
+		}
+	}
+
+	return errs
+}
+
+// validateBatchRequest checks a batch header, returning every failing field
+func validateBatchRequest(req *CreateBatchRequest) validation.Errors {
+	var errs validation.Errors
+
+	for _, field := range []struct {
+		name      string
+		value     string
+		maxLength int
+	}{
+		{"company_name", req.CompanyName, 16},
+		{"company_id", req.CompanyID, 10},
+		{"company_entry_description", req.CompanyEntryDescription, 10},
+	} {
+		if field.value == "" {
+			errs.Add(field.name, "is required")
+		} else if len(field.value) > field.maxLength {
+			errs.Add(field.name, fmt.Sprintf("must be at most %d characters", field.maxLength))
+		}
+	}
+
+	if req.SecCode == "" {
+		errs.Add("sec_code", "is required")
+	} else if _, ok := ach.LookupSecRule(req.SecCode); !ok {
+		errs.Add("sec_code", "must be one of "+strings.Join(ach.SupportedSecCodes, ", "))
+	}
+
+	return errs
+}
@@ -0,0 +1,117 @@
+package odfi
+
+import (
+	"strings"
+	"testing"
+
+	"ach-concourse/internal/common/ach"
+	"ach-concourse/internal/common/validation"
+)
+
+func validEntryRequest(secCode string) *CreateEntryRequest {
+	return &CreateEntryRequest{
+		TraceNumber:     "021000020000001",
+		SecCode:         secCode,
+		AmountCents:     125000,
+		RoutingNumber:   "021000021",
+		AccountNumber:   "123456789",
+		TransactionCode: ach.TransactionCodeCheckingDebit,
+		ReceiverName:    "Jane Doe",
+		ReceiverID:      "CUST-1",
+	}
+}
+
+// fieldMessage returns the message errs holds for field, or "" when it holds none
+func fieldMessage(errs validation.Errors, field string) string {
+	for _, fe := range errs {
+		if fe.Field == field {
+			return fe.Message
+		}
+	}
+	return ""
+}
+
+func TestValidateEntryRequestAcceptsEachSecCode(t *testing.T) {
+	for _, code := range ach.SupportedSecCodes {
+		req := validEntryRequest(code)
+		if code == ach.SecWEB || code == ach.SecTEL {
+			req.AuthorizationType = ach.AuthorizationSingle
+		}
+		if errs := validateEntryRequest(req); len(errs) != 0 {
+			t.Errorf("%s: got %v", code, errs)
+		}
+	}
+}
+
+func TestValidateEntryRequestAmounts(t *testing.T) {
+	// WEB and TEL have no amount caps of their own
+	for _, code := range []string{ach.SecWEB, ach.SecTEL} {
+		req := validEntryRequest(code)
+		req.AuthorizationType = ach.AuthorizationRecurring
+		req.AmountCents = 10000000
+		if errs := validateEntryRequest(req); len(errs) != 0 {
+			t.Errorf("%s $100,000: got %v", code, errs)
+		}
+	}
+
+	req := validEntryRequest(ach.SecPPD)
+	req.AmountCents = ach.MaxEntryAmountCents + 1
+	if msg := fieldMessage(validateEntryRequest(req), "amount_cents"); !strings.Contains(msg, "at most") {
+		t.Errorf("amount over the field width: amount_cents error = %q", msg)
+	}
+
+	req.AmountCents = 0
+	if msg := fieldMessage(validateEntryRequest(req), "amount_cents"); msg != "must be greater than zero" {
+		t.Errorf("zero amount: amount_cents error = %q", msg)
+	}
+}
+
+func TestValidateEntryRequestAddenda(t *testing.T) {
+	for _, tc := range []struct {
+		code    string
+		addenda []string
+		want    string
+	}{
+		{ach.SecPPD, []string{"INV 1001"}, ""},
+		{ach.SecPPD, []string{"INV 1001", "INV 1002"}, "at most 1"},
+		{ach.SecTEL, []string{"INV 1001"}, "is not allowed for TEL entries"},
+		{ach.SecIAT, []string{"INV 1001"}, "is not allowed for IAT entries"},
+		{ach.SecCTX, []string{"INV 1001", "INV 1002", "INV 1003"}, ""},
+	} {
+		req := validEntryRequest(tc.code)
+		if tc.code == ach.SecTEL {
+			req.AuthorizationType = ach.AuthorizationSingle
+		}
+		req.Addenda = tc.addenda
+
+		msg := fieldMessage(validateEntryRequest(req), "addenda")
+		if (tc.want == "" && msg != "") || !strings.Contains(msg, tc.want) {
+			t.Errorf("%s with %d addenda: addenda error = %q, want %q", tc.code, len(tc.addenda), msg, tc.want)
+		}
+	}
+}
+
+func TestValidateEntryRequestSecRules(t *testing.T) {
+	req := validEntryRequest(ach.SecWEB)
+	req.TransactionCode = ach.TransactionCodeCheckingCredit
+	errs := validateEntryRequest(req)
+	if fieldMessage(errs, "transaction_code") == "" {
+		t.Errorf("WEB credit: got %v, want a transaction_code error", errs)
+	}
+	if fieldMessage(errs, "authorization_type") == "" {
+		t.Errorf("WEB without authorization_type: got %v, want an authorization_type error", errs)
+	}
+
+	req = validEntryRequest(ach.SecCTX)
+	req.ReceiverID = ""
+	req.ReceiverName = strings.Repeat("x", 17)
+	errs = validateEntryRequest(req)
+	if fieldMessage(errs, "receiver_id") == "" || fieldMessage(errs, "receiver_name") == "" {
+		t.Errorf("CTX without receiver_id and a long name: got %v", errs)
+	}
+
+	req = validEntryRequest("XYZ")
+	if fieldMessage(validateEntryRequest(req), "sec_code") == "" {
+		t.Error("unknown SEC code was accepted")
+	}
+}
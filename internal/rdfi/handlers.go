@@ -7,25 +7,28 @@ import (
 	"github.com/go-chi/chi/v5"
 
 	commonhttp "ach-concourse/internal/common/http"
+	"ach-concourse/internal/common/idempotency"
 )
 
 // Handler handles HTTP requests for RDFI
 type Handler struct {
 	service *Service
+	keys    *idempotency.Store
 }
 
 // NewHandler creates a new RDFI handler
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, keys *idempotency.Store) *Handler {
+	return &Handler{service: service, keys: keys}
 }
 
 // RegisterRoutes registers all RDFI routes
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/api/v1/entries", func(r chi.Router) {
-		r.Post("/", h.CreateEntry)
+		r.With(idempotency.Middleware(h.keys)).Post("/", h.CreateEntry)
 		r.Get("/", h.ListEntries)
 		r.Get("/{id}", h.GetEntry)
-		r.Post("/{id}/return", h.ReturnEntry)
+		r.Post("/{id}/post", h.PostEntry)
+		r.With(idempotency.Middleware(h.keys)).Post("/{id}/return", h.ReturnEntry)
 	})
 	r.Get("/healthz", h.Health)
 }
@@ -40,7 +43,7 @@ func (h *Handler) CreateEntry(w http.ResponseWriter, r *http.Request) {
 
 	entry, err := h.service.CreateEntry(r.Context(), &req)
 	if err != nil {
-		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		commonhttp.ErrorFrom(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -83,6 +86,24 @@ func (h *Handler) GetEntry(w http.ResponseWriter, r *http.Request) {
 	commonhttp.JSON(w, http.StatusOK, entry)
 }
 
+// PostEntry handles POST /api/v1/entries/{id}/post
+func (h *Handler) PostEntry(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	entry, err := h.service.PostEntry(r.Context(), id)
+	if err != nil {
+		commonhttp.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if entry == nil {
+		commonhttp.Error(w, http.StatusNotFound, "entry not found")
+		return
+	}
+
+	commonhttp.JSON(w, http.StatusOK, entry)
+}
+
 // ReturnEntry handles POST /api/v1/entries/{id}/return
 func (h *Handler) ReturnEntry(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
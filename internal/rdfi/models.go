@@ -6,21 +6,38 @@ import (
 
 // RDFIEntry represents a receiving ACH entry
 type RDFIEntry struct {
-	ID           string    `json:"id"`
-	TraceNumber  string    `json:"trace_number"`
-	ReceiverName string    `json:"receiver_name"`
-	AmountCents  int64     `json:"amount_cents"`
-	Status       string    `json:"status"`
-	ReturnReason string    `json:"return_reason,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              string    `json:"id"`
+	TraceNumber     string    `json:"trace_number"`
+	ReceiverName    string    `json:"receiver_name"`
+	AmountCents     int64     `json:"amount_cents"`
+	RoutingNumber   string    `json:"routing_number"`
+	AccountNumber   string    `json:"account_number"` // Masked, e.g. "****6789"
+	AccountType     string    `json:"account_type"`
+	TransactionCode string    `json:"transaction_code"`
+	Direction       string    `json:"direction"` // Derived from transaction_code
+	Status          string    `json:"status"`
+	ReturnReason    string    `json:"return_reason,omitempty"`
+	SettlementDate  string    `json:"settlement_date,omitempty"` // YYYY-MM-DD
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Storage-only account number fields; never serialized
+	AccountNumberEncrypted string `json:"-"`
+	AccountNumberLast4     string `json:"-"`
 }
 
 // CreateEntryRequest represents the request to create an RDFI entry
 type CreateEntryRequest struct {
-	TraceNumber  string `json:"trace_number"`
-	ReceiverName string `json:"receiver_name"`
-	AmountCents  int64  `json:"amount_cents"`
+	TraceNumber     string `json:"trace_number"`
+	ReceiverName    string `json:"receiver_name"`
+	AmountCents     int64  `json:"amount_cents"`
+	RoutingNumber   string `json:"routing_number"`
+	AccountNumber   string `json:"account_number"`
+	AccountType     string `json:"account_type"`
+	TransactionCode string `json:"transaction_code"`
+	// SettlementDate is the date the ACH operator settles the entry, YYYY-MM-DD;
+	// it defaults to the day the entry is received
+	SettlementDate string `json:"settlement_date,omitempty"`
 }
 
 // ReturnRequest represents the request to return an entry
@@ -35,3 +52,8 @@ const (
 	StatusReturned = "RETURNED"
 )
 
+// entryTransitions lists the statuses each entry status may move to
+var entryTransitions = map[string]map[string]bool{
+	StatusReceived: {StatusPosted: true, StatusReturned: true},
+	StatusPosted:   {StatusReturned: true},
+}
@@ -3,20 +3,37 @@ package rdfi
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+
+	"ach-concourse/internal/common/ach"
+	"ach-concourse/internal/common/calendar"
+	"ach-concourse/internal/common/events"
 )
 
+// ErrInvalidTransition is returned when an entry cannot move to the requested status
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// outboxTable holds entry status changes waiting to be delivered to the ledger
+const outboxTable = "rdfi_entry_events"
+
 // Repository handles database operations for RDFI entries
 type Repository struct {
-	db *sql.DB
+	db     *sql.DB
+	outbox *events.Outbox
 }
 
 // NewRepository creates a new RDFI repository
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, outbox: events.NewOutbox(db, outboxTable, "RDFI")}
+}
+
+// Outbox returns the outbox that status changes are recorded in
+func (r *Repository) Outbox() *events.Outbox {
+	return r.outbox
 }
 
 const schema = `
@@ -31,50 +48,108 @@ CREATE TABLE IF NOT EXISTS rdfi_entries (
 	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
 
+ALTER TABLE rdfi_entries ADD COLUMN IF NOT EXISTS routing_number TEXT;
+ALTER TABLE rdfi_entries ADD COLUMN IF NOT EXISTS account_number_encrypted TEXT;
+ALTER TABLE rdfi_entries ADD COLUMN IF NOT EXISTS account_number_last4 TEXT;
+ALTER TABLE rdfi_entries ADD COLUMN IF NOT EXISTS account_type TEXT;
+ALTER TABLE rdfi_entries ADD COLUMN IF NOT EXISTS transaction_code TEXT;
+ALTER TABLE rdfi_entries ADD COLUMN IF NOT EXISTS settlement_date DATE;
+
+-- Rows from before the check are left unvalidated rather than failing startup
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'rdfi_entries_amount_cents_positive') THEN
+		ALTER TABLE rdfi_entries ADD CONSTRAINT rdfi_entries_amount_cents_positive CHECK (amount_cents > 0) NOT VALID;
+	END IF;
+END $$;
+
 CREATE INDEX IF NOT EXISTS idx_rdfi_entries_trace_number ON rdfi_entries(trace_number);
 CREATE INDEX IF NOT EXISTS idx_rdfi_entries_status ON rdfi_entries(status);
 `
 
+// entryColumns is the column list shared by every RDFI entry query, in scanEntry order
+const entryColumns = `id, trace_number, receiver_name, amount_cents,
+	routing_number, account_number_last4, account_type, transaction_code,
+	status, return_reason, settlement_date, created_at, updated_at`
+
 // GetSchema returns the SQL schema for RDFI tables
 func GetSchema() string {
-	return schema
+	return schema + events.OutboxSchema(outboxTable)
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
-// Create creates a new RDFI entry
+// scanEntry scans a row selected with entryColumns into an RDFIEntry
+func scanEntry(row rowScanner) (*RDFIEntry, error) {
+	entry := &RDFIEntry{}
+	var receiverName, routingNumber, last4, accountType, transactionCode, returnReason sql.NullString
+	var amountCents sql.NullInt64
+	var settlementDate sql.NullTime
+
+	err := row.Scan(
+		&entry.ID, &entry.TraceNumber, &receiverName, &amountCents,
+		&routingNumber, &last4, &accountType, &transactionCode,
+		&entry.Status, &returnReason, &settlementDate, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.ReceiverName = receiverName.String
+	entry.AmountCents = amountCents.Int64
+	entry.RoutingNumber = routingNumber.String
+	entry.AccountNumberLast4 = last4.String
+	entry.AccountNumber = ach.MaskAccountNumber(last4.String)
+	entry.AccountType = accountType.String
+	entry.TransactionCode = transactionCode.String
+	entry.Direction = ach.DirectionForTransactionCode(transactionCode.String)
+
+	if returnReason.Valid {
+		entry.ReturnReason = returnReason.String
+	}
+	if settlementDate.Valid {
+		entry.SettlementDate = settlementDate.Time.Format(calendar.DateLayout)
+	}
+
+	return entry, nil
+}
+
+// Create creates a new RDFI entry.
+// The account number is written only as ciphertext plus its last four characters.
 func (r *Repository) Create(ctx context.Context, entry *RDFIEntry) error {
 	entry.ID = uuid.New().String()
 	entry.CreatedAt = time.Now()
 	entry.UpdatedAt = time.Now()
 
 	query := `
-		INSERT INTO rdfi_entries (id, trace_number, receiver_name, amount_cents, status, return_reason, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO rdfi_entries (id, trace_number, receiver_name, amount_cents,
+			routing_number, account_number_encrypted, account_number_last4, account_type, transaction_code,
+			status, return_reason, settlement_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
-		entry.ID, entry.TraceNumber, entry.ReceiverName,
-		entry.AmountCents, entry.Status, nullString(entry.ReturnReason),
+		entry.ID, entry.TraceNumber, entry.ReceiverName, entry.AmountCents,
+		entry.RoutingNumber, entry.AccountNumberEncrypted, entry.AccountNumberLast4, entry.AccountType, entry.TransactionCode,
+		entry.Status, nullString(entry.ReturnReason), nullString(entry.SettlementDate),
 		entry.CreatedAt, entry.UpdatedAt)
+	if err != nil {
+		return err
+	}
 
-	return err
+	entry.AccountNumber = ach.MaskAccountNumber(entry.AccountNumberLast4)
+	entry.Direction = ach.DirectionForTransactionCode(entry.TransactionCode)
+
+	return nil
 }
 
 // GetByID retrieves an RDFI entry by ID
 func (r *Repository) GetByID(ctx context.Context, id string) (*RDFIEntry, error) {
-	query := `
-		SELECT id, trace_number, receiver_name, amount_cents, status, return_reason, created_at, updated_at
-		FROM rdfi_entries
-		WHERE id = $1
-	`
-
-	entry := &RDFIEntry{}
-	var returnReason sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&entry.ID, &entry.TraceNumber, &entry.ReceiverName,
-		&entry.AmountCents, &entry.Status, &returnReason,
-		&entry.CreatedAt, &entry.UpdatedAt)
+	query := `SELECT ` + entryColumns + ` FROM rdfi_entries WHERE id = $1`
 
+	entry, err := scanEntry(r.db.QueryRowContext(ctx, query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -82,20 +157,12 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*RDFIEntry, error)
 		return nil, err
 	}
 
-	if returnReason.Valid {
-		entry.ReturnReason = returnReason.String
-	}
-
 	return entry, nil
 }
 
 // List retrieves RDFI entries with optional filters
 func (r *Repository) List(ctx context.Context, status, traceNumber string) ([]*RDFIEntry, error) {
-	query := `
-		SELECT id, trace_number, receiver_name, amount_cents, status, return_reason, created_at, updated_at
-		FROM rdfi_entries
-		WHERE 1=1
-	`
+	query := `SELECT ` + entryColumns + ` FROM rdfi_entries WHERE 1=1`
 	args := []interface{}{}
 	argNum := 1
 
@@ -121,53 +188,75 @@ func (r *Repository) List(ctx context.Context, status, traceNumber string) ([]*R
 
 	var entries []*RDFIEntry
 	for rows.Next() {
-		entry := &RDFIEntry{}
-		var returnReason sql.NullString
-
-		err := rows.Scan(
-			&entry.ID, &entry.TraceNumber, &entry.ReceiverName,
-			&entry.AmountCents, &entry.Status, &returnReason,
-			&entry.CreatedAt, &entry.UpdatedAt)
+		entry, err := scanEntry(rows)
 		if err != nil {
 			return nil, err
 		}
-
-		if returnReason.Valid {
-			entry.ReturnReason = returnReason.String
-		}
-
 		entries = append(entries, entry)
 	}
 
 	return entries, rows.Err()
 }
 
+// Post marks a received entry as posted to the receiver's account
+func (r *Repository) Post(ctx context.Context, id string) (*RDFIEntry, error) {
+	return r.transition(ctx, id, StatusPosted, "")
+}
+
 // Return marks an entry as returned with a reason
 func (r *Repository) Return(ctx context.Context, id, reason string) (*RDFIEntry, error) {
-	query := `
-		UPDATE rdfi_entries
-		SET status = $1, return_reason = $2, updated_at = $3
-		WHERE id = $4
-		RETURNING id, trace_number, receiver_name, amount_cents, status, return_reason, created_at, updated_at
-	`
-
-	entry := &RDFIEntry{}
-	var returnReason sql.NullString
+	return r.transition(ctx, id, StatusReturned, reason)
+}
 
-	err := r.db.QueryRowContext(ctx, query, StatusReturned, reason, time.Now(), id).Scan(
-		&entry.ID, &entry.TraceNumber, &entry.ReceiverName,
-		&entry.AmountCents, &entry.Status, &returnReason,
-		&entry.CreatedAt, &entry.UpdatedAt)
+// transition moves an entry to status and records the change in the outbox, in one
+// transaction. Returns (nil, nil) if the entry does not exist and ErrInvalidTransition
+// if its current status does not allow the move.
+func (r *Repository) transition(ctx context.Context, id, status, reason string) (*RDFIEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
+	var current string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM rdfi_entries WHERE id = $1 FOR UPDATE`, id).Scan(&current)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
+	if !entryTransitions[current][status] {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, current, status)
+	}
 
-	if returnReason.Valid {
-		entry.ReturnReason = returnReason.String
+	query := `
+		UPDATE rdfi_entries
+		SET status = $1, return_reason = COALESCE($2, return_reason), updated_at = $3
+		WHERE id = $4
+		RETURNING ` + entryColumns
+
+	entry, err := scanEntry(tx.QueryRowContext(ctx, query, status, nullString(reason), time.Now(), id))
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.outbox.Record(ctx, tx, &events.EntryEvent{
+		EntryID:        entry.ID,
+		TraceNumber:    entry.TraceNumber,
+		Status:         status,
+		PreviousStatus: current,
+		Direction:      entry.Direction,
+		AmountCents:    entry.AmountCents,
+		SettlementDate: entry.SettlementDate,
+		Reason:         reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
 	}
 
 	return entry, nil
@@ -179,4 +268,3 @@ func nullString(s string) sql.NullString {
 	}
 	return sql.NullString{String: s, Valid: true}
 }
-
@@ -3,29 +3,62 @@ package rdfi
 import (
 	"context"
 	"errors"
+
+	"ach-concourse/internal/common/ach"
+	"ach-concourse/internal/common/calendar"
+	commoncrypto "ach-concourse/internal/common/crypto"
+	"ach-concourse/internal/common/validation"
 )
 
 // Service handles business logic for RDFI entries
 type Service struct {
-	repo *Repository
+	repo   *Repository
+	cipher *commoncrypto.FieldCipher
 }
 
 // NewService creates a new RDFI service
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, cipher *commoncrypto.FieldCipher) *Service {
+	return &Service{repo: repo, cipher: cipher}
 }
 
 // CreateEntry creates a new RDFI entry
 func (s *Service) CreateEntry(ctx context.Context, req *CreateEntryRequest) (*RDFIEntry, error) {
+	var errs validation.Errors
 	if req.TraceNumber == "" {
-		return nil, errors.New("trace_number is required")
+		errs.Add("trace_number", "is required")
+	}
+	if req.AmountCents <= 0 {
+		errs.Add("amount_cents", "must be greater than zero")
+	}
+	errs = append(errs, ach.ValidateAccountDetails(req.RoutingNumber, req.AccountNumber, req.AccountType, req.TransactionCode)...)
+	settlementDate := calendar.FormatDate(calendar.Today())
+	if req.SettlementDate != "" {
+		if _, err := calendar.ParseDate(req.SettlementDate); err != nil {
+			errs.Add("settlement_date", "must be YYYY-MM-DD")
+		}
+		settlementDate = req.SettlementDate
+	}
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	tc, _ := ach.LookupTransactionCode(req.TransactionCode)
+
+	encrypted, err := s.cipher.Encrypt(req.AccountNumber)
+	if err != nil {
+		return nil, err
 	}
 
 	entry := &RDFIEntry{
-		TraceNumber:  req.TraceNumber,
-		ReceiverName: req.ReceiverName,
-		AmountCents:  req.AmountCents,
-		Status:       StatusReceived,
+		TraceNumber:            req.TraceNumber,
+		ReceiverName:           req.ReceiverName,
+		AmountCents:            req.AmountCents,
+		RoutingNumber:          req.RoutingNumber,
+		AccountType:            tc.AccountType,
+		TransactionCode:        tc.Code,
+		Status:                 StatusReceived,
+		SettlementDate:         settlementDate,
+		AccountNumberEncrypted: encrypted,
+		AccountNumberLast4:     ach.Last4(req.AccountNumber),
 	}
 
 	if err := s.repo.Create(ctx, entry); err != nil {
@@ -45,6 +78,11 @@ func (s *Service) ListEntries(ctx context.Context, status, traceNumber string) (
 	return s.repo.List(ctx, status, traceNumber)
 }
 
+// PostEntry marks a received entry as posted to the receiver's account
+func (s *Service) PostEntry(ctx context.Context, id string) (*RDFIEntry, error) {
+	return s.repo.Post(ctx, id)
+}
+
 // ReturnEntry marks an entry as returned
 func (s *Service) ReturnEntry(ctx context.Context, id, reason string) (*RDFIEntry, error) {
 	if reason == "" {
@@ -7,11 +7,11 @@
 #     go run ./cmd/seed
 #     It uses concurrent workers and is ~10x faster.
 #
-# This shell script creates 620 records total.
+# This shell script creates 420 records total.
 
 set -e
 
-echo "🌱 ACH Concourse - Database Seeding (Shell - 620 records)"
+echo "🌱 ACH Concourse - Database Seeding (Shell - 420 records)"
 echo "========================================================="
 echo "💡 For 2000+ records, use: go run ./cmd/seed"
 echo ""
@@ -36,6 +36,11 @@ for i in $(seq 1 150); do
     AMOUNT=$((RANDOM % 100000 + 1000))
     STATUSES=("PENDING" "PENDING" "SENT" "SENT" "SENT" "CANCELLED")
     STATUS=${STATUSES[$((i % 6))]}
+    # WEB and TEL debits must carry the type of authorization obtained
+    AUTH_FIELD=""
+    if [ "$SEC_CODE" = "WEB" ] || [ "$SEC_CODE" = "TEL" ]; then
+        AUTH_FIELD="\"authorization_type\": \"SINGLE\","
+    fi
     
     curl -s -X POST http://localhost:8081/api/v1/entries \
         -H "Content-Type: application/json" \
@@ -43,7 +48,12 @@ for i in $(seq 1 150); do
             \"trace_number\": \"$TRACE_NUM\",
             \"company_name\": \"$COMPANY_NAME\",
             \"sec_code\": \"$SEC_CODE\",
-            \"amount_cents\": $AMOUNT
+            \"amount_cents\": $AMOUNT,
+            \"routing_number\": \"021000021\",
+            \"account_number\": \"$((4000000000 + i))\",
+            \"transaction_code\": \"27\",
+            $AUTH_FIELD
+            \"receiver_name\": \"Receiver $i\"
         }" > /dev/null
     
     # Update status for non-PENDING entries
@@ -78,12 +88,20 @@ for i in $(seq 1 150); do
         -d "{
             \"trace_number\": \"$TRACE_NUM\",
             \"receiver_name\": \"$RECEIVER_NAME\",
-            \"amount_cents\": $AMOUNT
+            \"amount_cents\": $AMOUNT,
+            \"routing_number\": \"011000015\",
+            \"account_number\": \"$((7000000000 + i))\",
+            \"transaction_code\": \"22\"
         }" > /dev/null
     
-    # Return some entries
-    if [ "$STATUS" == "RETURNED" ]; then
+    # Post or return some entries; the ledger posts from these status changes
+    if [ "$STATUS" != "RECEIVED" ]; then
         ENTRY_ID=$(curl -s "http://localhost:8082/api/v1/entries?trace_number=$TRACE_NUM" | grep -o '"id":"[^"]*"' | head -1 | cut -d'"' -f4)
+    fi
+    if [ "$STATUS" == "POSTED" ] && [ ! -z "$ENTRY_ID" ]; then
+        curl -s -X POST "http://localhost:8082/api/v1/entries/$ENTRY_ID/post" > /dev/null
+    fi
+    if [ "$STATUS" == "RETURNED" ]; then
         RETURN_CODES=("R01" "R02" "R03" "R04" "R10")
         RETURN_CODE=${RETURN_CODES[$((i % 5))]}
         if [ ! -z "$ENTRY_ID" ]; then
@@ -100,40 +118,6 @@ done
 echo "✅ RDFI entries created: 150"
 echo ""
 
-# Seed Ledger postings
-echo "📝 Seeding Ledger postings (200 records)..."
-for i in $(seq 1 200); do
-    if [ $((i % 2)) -eq 0 ]; then
-        ACH_SIDE="ODFI"
-        TRACE_NUM=$(printf "%015d" $((1000000000000 + (i / 2))))
-    else
-        ACH_SIDE="RDFI"
-        TRACE_NUM=$(printf "%015d" $((2000000000000 + ((i + 1) / 2))))
-    fi
-    
-    DIRECTIONS=("DEBIT" "CREDIT")
-    DIRECTION=${DIRECTIONS[$((i % 2))]}
-    AMOUNT=$((RANDOM % 90000 + 1000))
-    DESCRIPTIONS=("Payment processing" "Vendor payment" "Payroll deposit" "Invoice payment" "Refund processing" "Settlement transfer" "Account funding" "Bill payment")
-    DESCRIPTION=${DESCRIPTIONS[$((i % 8))]}
-    
-    curl -s -X POST http://localhost:8083/api/v1/postings \
-        -H "Content-Type: application/json" \
-        -d "{
-            \"ach_side\": \"$ACH_SIDE\",
-            \"trace_number\": \"$TRACE_NUM\",
-            \"amount_cents\": $AMOUNT,
-            \"direction\": \"$DIRECTION\",
-            \"description\": \"$DESCRIPTION\"
-        }" > /dev/null
-    
-    if [ $((i % 40)) -eq 0 ]; then
-        echo "  Created $i ledger postings..."
-    fi
-done
-echo "✅ Ledger postings created: 200"
-echo ""
-
 # Seed EIP cases
 echo "📝 Seeding EIP cases (120 records)..."
 for i in $(seq 1 120); do
@@ -195,10 +179,10 @@ echo ""
 echo "📊 Summary:"
 echo "  ODFI entries:      150 records"
 echo "  RDFI entries:      150 records"
-echo "  Ledger postings:   200 records"
 echo "  EIP cases:         120 records"
 echo "  ─────────────────────────────"
-echo "  Total:             620 records"
+echo "  Total:             420 records"
+echo "  Ledger postings follow from the SENT, POSTED and RETURNED entries"
 echo ""
 echo "🔍 Verify data:"
 echo "  Console: curl http://localhost:8080/api/v1/ach-items"